@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -15,7 +16,7 @@ import (
 // Event handling functions
 
 // getEvents connects to the Incus monitoring interface.
-func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
+func (r *ProtocolIncus) getEvents(allProjects bool, since int64) (*EventListener, error) {
 	// Prevent anything else from interacting with the listeners
 	r.eventListenersLock.Lock()
 	defer r.eventListenersLock.Unlock()
@@ -38,21 +39,35 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 		listener.projectName = connInfo.Project
 	}
 
+	// Listeners replaying past events get their own connection.
+	listener.key = listener.projectName
+	if since >= 0 {
+		listener.key = fmt.Sprintf("%s?since=%d", listener.projectName, since)
+	}
+
 	// There is an existing Go routine for the required project filter, so just add another target.
-	if r.eventListeners[listener.projectName] != nil {
-		r.eventListeners[listener.projectName] = append(r.eventListeners[listener.projectName], &listener)
+	if r.eventListeners[listener.key] != nil {
+		r.eventListeners[listener.key] = append(r.eventListeners[listener.key], &listener)
 		return &listener, nil
 	}
 
 	// Setup a new connection with Incus
-	var url string
-	var err error
+	query := []string{}
 	if allProjects {
-		url, err = r.setQueryAttributes("/events?all-projects=true")
-	} else {
-		url, err = r.setQueryAttributes("/events")
+		query = append(query, "all-projects=true")
+	}
+
+	if since >= 0 {
+		query = append(query, fmt.Sprintf("since=%d", since))
 	}
 
+	path := "/events"
+	if len(query) > 0 {
+		path += "?" + strings.Join(query, "&")
+	}
+
+	url, err := r.setQueryAttributes(path)
+
 	if err != nil {
 		return nil, err
 	}
@@ -64,11 +79,11 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 	}
 
 	r.eventConnsLock.Lock()
-	r.eventConns[listener.projectName] = wsConn // Save for others to use.
+	r.eventConns[listener.key] = wsConn // Save for others to use.
 	r.eventConnsLock.Unlock()
 
 	// Initialize the event listener list if we were able to connect to the events websocket.
-	r.eventListeners[listener.projectName] = []*EventListener{&listener}
+	r.eventListeners[listener.key] = []*EventListener{&listener}
 
 	// Spawn a watcher that will close the websocket connection after all
 	// listeners are gone.
@@ -83,14 +98,14 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 
 			r.eventListenersLock.Lock()
 			r.eventConnsLock.Lock()
-			if len(r.eventListeners[listener.projectName]) == 0 {
+			if len(r.eventListeners[listener.key]) == 0 {
 				// We don't need the connection anymore, disconnect and clear.
-				if r.eventListeners[listener.projectName] != nil {
-					_ = r.eventConns[listener.projectName].Close()
-					delete(r.eventConns, listener.projectName)
+				if r.eventListeners[listener.key] != nil {
+					_ = r.eventConns[listener.key].Close()
+					delete(r.eventConns, listener.key)
 				}
 
-				r.eventListeners[listener.projectName] = nil
+				r.eventListeners[listener.key] = nil
 				r.eventListenersLock.Unlock()
 				r.eventConnsLock.Unlock()
 
@@ -112,14 +127,14 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 				defer r.eventListenersLock.Unlock()
 
 				// Tell all the current listeners about the failure
-				for _, listener := range r.eventListeners[listener.projectName] {
+				for _, listener := range r.eventListeners[listener.key] {
 					listener.err = err
 					listener.ctxCancel()
 				}
 
 				// And remove them all from the list so that when watcher routine runs it will
 				// close the websocket connection.
-				r.eventListeners[listener.projectName] = nil
+				r.eventListeners[listener.key] = nil
 
 				close(stopCh) // Instruct watcher go routine to cleanup.
 
@@ -140,7 +155,7 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 
 			// Send the message to all handlers
 			r.eventListenersLock.Lock()
-			for _, listener := range r.eventListeners[listener.projectName] {
+			for _, listener := range r.eventListeners[listener.key] {
 				listener.targetsLock.Lock()
 				for _, target := range listener.targets {
 					if target.types != nil && !slices.Contains(target.types, event.Type) {
@@ -162,12 +177,32 @@ func (r *ProtocolIncus) getEvents(allProjects bool) (*EventListener, error) {
 
 // GetEvents gets the events for the project defined on the client.
 func (r *ProtocolIncus) GetEvents() (*EventListener, error) {
-	return r.getEvents(false)
+	return r.getEvents(false, -1)
 }
 
 // GetEventsAllProjects gets events for all projects.
 func (r *ProtocolIncus) GetEventsAllProjects() (*EventListener, error) {
-	return r.getEvents(true)
+	return r.getEvents(true, -1)
+}
+
+// GetEventsSince gets the events for the project defined on the client, starting with the replay of
+// the recorded events that have an ID greater than the given one.
+func (r *ProtocolIncus) GetEventsSince(since int64) (*EventListener, error) {
+	if !r.HasExtension("events_history") {
+		return nil, fmt.Errorf("The server is missing the required \"events_history\" API extension")
+	}
+
+	return r.getEvents(false, since)
+}
+
+// GetEventsAllProjectsSince gets events for all projects, starting with the replay of the recorded events
+// that have an ID greater than the given one.
+func (r *ProtocolIncus) GetEventsAllProjectsSince(since int64) (*EventListener, error) {
+	if !r.HasExtension("events_history") {
+		return nil, fmt.Errorf("The server is missing the required \"events_history\" API extension")
+	}
+
+	return r.getEvents(true, since)
 }
 
 // SendEvent send an event to the server via the client's event listener connection.
@@ -461,6 +461,29 @@ func (r *ProtocolIncus) GetInstance(name string) (*api.Instance, string, error)
 	return &instance, etag, nil
 }
 
+// GetInstanceWithOrigin returns the instance entry for the provided name, along with the origin of its
+// expanded configuration keys and devices.
+func (r *ProtocolIncus) GetInstanceWithOrigin(name string) (*api.Instance, string, error) {
+	if !r.HasExtension("instances_config_origin") {
+		return nil, "", fmt.Errorf("The server is missing the required \"instances_config_origin\" API extension")
+	}
+
+	instance := api.Instance{}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Fetch the raw value
+	etag, err := r.queryStruct("GET", fmt.Sprintf("%s/%s?origin=1", path, url.PathEscape(name)), nil, "", &instance)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &instance, etag, nil
+}
+
 // GetInstanceFull returns the instance entry for the provided name along with snapshot information.
 func (r *ProtocolIncus) GetInstanceFull(name string) (*api.InstanceFull, string, error) {
 	instance := api.InstanceFull{}
@@ -1128,6 +1151,12 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 		}
 	}
 
+	if exec.Detachable {
+		if !r.HasExtension("instance_exec_detach") {
+			return nil, fmt.Errorf("The server is missing the required \"instance_exec_detach\" API extension")
+		}
+	}
+
 	var uri string
 
 	if r.IsAgent() {
@@ -1150,18 +1179,6 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 	opAPI := op.Get()
 
 	// Process additional arguments
-
-	// Parse the fds
-	fds := map[string]string{}
-
-	value, ok := opAPI.Metadata["fds"]
-	if ok {
-		values := value.(map[string]any)
-		for k, v := range values {
-			fds[k] = v.(string)
-		}
-	}
-
 	if exec.RecordOutput && (args.Stdout != nil || args.Stderr != nil) {
 		err = op.Wait()
 		if err != nil {
@@ -1219,10 +1236,70 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 		}
 	}
 
+	err = r.connectInstanceExec(opAPI, exec.Interactive, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// AttachInstanceExec attaches to a running detachable exec session.
+// The output produced by the command so far is sent to the output writers before the live output.
+func (r *ProtocolIncus) AttachInstanceExec(operationID string, args *InstanceExecArgs) (Operation, error) {
+	if !r.HasExtension("instance_exec_detach") {
+		return nil, fmt.Errorf("The server is missing the required \"instance_exec_detach\" API extension")
+	}
+
+	// Ensure args are equivalent to empty InstanceExecArgs.
+	if args == nil {
+		args = &InstanceExecArgs{}
+	}
+
+	opAPI, _, err := r.GetOperation(operationID)
+	if err != nil {
+		return nil, err
+	}
+
+	detachable, _ := opAPI.Metadata["detachable"].(bool)
+	if !detachable {
+		return nil, fmt.Errorf("Operation %q isn't a detachable exec session", operationID)
+	}
+
+	interactive, _ := opAPI.Metadata["interactive"].(bool)
+
+	// Setup an Operation wrapper
+	op := operation{
+		Operation: *opAPI,
+		r:         r,
+		chActive:  make(chan bool),
+	}
+
+	err = r.connectInstanceExec(op.Get(), interactive, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &op, nil
+}
+
+// connectInstanceExec connects the websockets of an exec operation to the provided arguments.
+func (r *ProtocolIncus) connectInstanceExec(opAPI api.Operation, interactive bool, args *InstanceExecArgs) error {
+	// Parse the fds
+	fds := map[string]string{}
+
+	value, ok := opAPI.Metadata["fds"]
+	if ok {
+		values := value.(map[string]any)
+		for k, v := range values {
+			fds[k] = v.(string)
+		}
+	}
+
 	if fds[api.SecretNameControl] != "" {
 		conn, err := r.GetOperationWebsocket(opAPI.ID, fds[api.SecretNameControl])
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		go func() {
@@ -1235,13 +1312,13 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 		}
 	}
 
-	if exec.Interactive {
+	if interactive {
 		// Handle interactive sections
 		if args.Stdin != nil && args.Stdout != nil {
 			// Connect to the websocket
 			conn, err := r.GetOperationWebsocket(opAPI.ID, fds["0"])
 			if err != nil {
-				return nil, err
+				return err
 			}
 
 			// And attach stdin and stdout to it
@@ -1268,7 +1345,7 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 		if fds["0"] != "" {
 			conn, err := r.GetOperationWebsocket(opAPI.ID, fds["0"])
 			if err != nil {
-				return nil, err
+				return err
 			}
 
 			go func() {
@@ -1285,7 +1362,7 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 		if fds["1"] != "" {
 			conn, err := r.GetOperationWebsocket(opAPI.ID, fds["1"])
 			if err != nil {
-				return nil, err
+				return err
 			}
 
 			// Discard Stdout from remote command if output writer not supplied.
@@ -1302,7 +1379,7 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 		if fds["2"] != "" {
 			conn, err := r.GetOperationWebsocket(opAPI.ID, fds["2"])
 			if err != nil {
-				return nil, err
+				return err
 			}
 
 			// Discard Stderr from remote command if output writer not supplied.
@@ -1350,7 +1427,7 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 		}()
 	}
 
-	return op, nil
+	return nil
 }
 
 // GetInstanceFile retrieves the provided path from the instance.
@@ -1473,6 +1550,30 @@ func (r *ProtocolIncus) CreateInstanceFile(instanceName string, filePath string,
 		}
 	}
 
+	var contentRange string
+	if args.Offset > 0 {
+		if !r.HasExtension("instance_file_resume") {
+			return fmt.Errorf("The server is missing the required \"instance_file_resume\" API extension")
+		}
+
+		// Figure out the size of the remaining content.
+		remaining, err := args.Content.Seek(0, io.SeekEnd)
+		if err != nil {
+			return err
+		}
+
+		_, err = args.Content.Seek(0, io.SeekStart)
+		if err != nil {
+			return err
+		}
+
+		if remaining == 0 {
+			return nil
+		}
+
+		contentRange = fmt.Sprintf("bytes %d-%d/%d", args.Offset, args.Offset+remaining-1, args.Offset+remaining)
+	}
+
 	var requestURL string
 
 	if r.IsAgent() {
@@ -1527,6 +1628,10 @@ func (r *ProtocolIncus) CreateInstanceFile(instanceName string, filePath string,
 		req.Header.Set("X-Incus-write", args.WriteMode)
 	}
 
+	if contentRange != "" {
+		req.Header.Set("Content-Range", contentRange)
+	}
+
 	// Send the request
 	resp, err := r.DoHTTP(req)
 	if err != nil {
@@ -2154,6 +2259,48 @@ func (r *ProtocolIncus) UpdateInstanceState(name string, state api.InstanceState
 	return op, nil
 }
 
+// WaitInstance waits for the instance to reach the condition (running, stopped, agent, ip or ready) and returns its state.
+// A timeout of -1 waits indefinitely.
+func (r *ProtocolIncus) WaitInstance(name string, condition string, timeout int) (*api.InstanceState, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.HasExtension("instance_wait") {
+		return nil, fmt.Errorf("The server is missing the required \"instance_wait\" API extension")
+	}
+
+	state := api.InstanceState{}
+
+	_, err = r.queryStruct("GET", fmt.Sprintf("%s/%s/wait?for=%s&timeout=%d", path, url.PathEscape(name), url.QueryEscape(condition), timeout), nil, "", &state)
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// GetInstancePortForward returns a websocket connected to a TCP port inside the instance.
+func (r *ProtocolIncus) GetInstancePortForward(name string, port int) (*websocket.Conn, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.HasExtension("instance_port_forward") {
+		return nil, fmt.Errorf("The server is missing the required \"instance_port_forward\" API extension")
+	}
+
+	// Add project/target.
+	uri, err := r.setQueryAttributes(fmt.Sprintf("%s/%s/port-forward?port=%d", path, url.PathEscape(name), port))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.websocket(uri)
+}
+
 // GetInstanceAccess returns an Access entry for the provided instance name.
 func (r *ProtocolIncus) GetInstanceAccess(name string) (api.Access, error) {
 	access := api.Access{}
@@ -2352,6 +2499,69 @@ func (r *ProtocolIncus) UpdateInstanceMetadata(name string, metadata api.ImageMe
 	return nil
 }
 
+// GetInstanceInventory returns the software inventory of the instance.
+func (r *ProtocolIncus) GetInstanceInventory(name string) (*api.InstanceInventory, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.HasExtension("instance_inventory") {
+		return nil, fmt.Errorf("The server is missing the required \"instance_inventory\" API extension")
+	}
+
+	inventory := api.InstanceInventory{}
+
+	_, err = r.queryStruct("GET", fmt.Sprintf("%s/%s/inventory", path, url.PathEscape(name)), nil, "", &inventory)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inventory, nil
+}
+
+// GetInstanceSSHKeys returns the authorized SSH keys of a guest user.
+func (r *ProtocolIncus) GetInstanceSSHKeys(instanceName string, user string) (*api.InstanceSSHKeys, string, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !r.HasExtension("instance_ssh_keys") {
+		return nil, "", fmt.Errorf("The server is missing the required \"instance_ssh_keys\" API extension")
+	}
+
+	keys := api.InstanceSSHKeys{}
+
+	url := fmt.Sprintf("%s/%s/ssh-keys/%s", path, url.PathEscape(instanceName), url.PathEscape(user))
+	etag, err := r.queryStruct("GET", url, nil, "", &keys)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &keys, etag, nil
+}
+
+// UpdateInstanceSSHKeys replaces the authorized SSH keys of a guest user, creating the user if missing.
+func (r *ProtocolIncus) UpdateInstanceSSHKeys(instanceName string, user string, keys api.InstanceSSHKeysPut, ETag string) error {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return err
+	}
+
+	if !r.HasExtension("instance_ssh_keys") {
+		return fmt.Errorf("The server is missing the required \"instance_ssh_keys\" API extension")
+	}
+
+	url := fmt.Sprintf("%s/%s/ssh-keys/%s", path, url.PathEscape(instanceName), url.PathEscape(user))
+	_, _, err = r.query("PUT", url, keys, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetInstanceTemplateFiles returns the list of names of template files for a instance.
 func (r *ProtocolIncus) GetInstanceTemplateFiles(instanceName string) ([]string, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
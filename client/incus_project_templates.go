@@ -0,0 +1,107 @@
+package incus
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Project template handling functions
+
+// GetProjectTemplateNames returns a list of project template names.
+func (r *ProtocolIncus) GetProjectTemplateNames() ([]string, error) {
+	if !r.HasExtension("projects_templates") {
+		return nil, fmt.Errorf("The server is missing the required \"projects_templates\" API extension")
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/project-templates"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetProjectTemplates returns a list of ProjectTemplate structs.
+func (r *ProtocolIncus) GetProjectTemplates() ([]api.ProjectTemplate, error) {
+	if !r.HasExtension("projects_templates") {
+		return nil, fmt.Errorf("The server is missing the required \"projects_templates\" API extension")
+	}
+
+	templates := []api.ProjectTemplate{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", "/project-templates?recursion=1", nil, "", &templates)
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetProjectTemplate returns a ProjectTemplate entry for the provided name.
+func (r *ProtocolIncus) GetProjectTemplate(name string) (*api.ProjectTemplate, string, error) {
+	if !r.HasExtension("projects_templates") {
+		return nil, "", fmt.Errorf("The server is missing the required \"projects_templates\" API extension")
+	}
+
+	template := api.ProjectTemplate{}
+
+	// Fetch the raw value
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/project-templates/%s", url.PathEscape(name)), nil, "", &template)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &template, etag, nil
+}
+
+// CreateProjectTemplate defines a new project template.
+func (r *ProtocolIncus) CreateProjectTemplate(template api.ProjectTemplatesPost) error {
+	if !r.HasExtension("projects_templates") {
+		return fmt.Errorf("The server is missing the required \"projects_templates\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("POST", "/project-templates", template, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateProjectTemplate updates the project template to match the provided ProjectTemplatePut struct.
+func (r *ProtocolIncus) UpdateProjectTemplate(name string, template api.ProjectTemplatePut, ETag string) error {
+	if !r.HasExtension("projects_templates") {
+		return fmt.Errorf("The server is missing the required \"projects_templates\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("PUT", fmt.Sprintf("/project-templates/%s", url.PathEscape(name)), template, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteProjectTemplate deletes a project template.
+func (r *ProtocolIncus) DeleteProjectTemplate(name string) error {
+	if !r.HasExtension("projects_templates") {
+		return fmt.Errorf("The server is missing the required \"projects_templates\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("DELETE", fmt.Sprintf("/project-templates/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package incus
 import (
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/lxc/incus/v6/shared/api"
 )
@@ -78,6 +79,27 @@ func (r *ProtocolIncus) GetProjectState(name string) (*api.ProjectState, error)
 	return &projectState, nil
 }
 
+// GetProjectUsage returns the hourly usage records of the project between from and to.
+func (r *ProtocolIncus) GetProjectUsage(name string, from time.Time, to time.Time) ([]api.ProjectUsageRecord, error) {
+	if !r.HasExtension("projects_usage_history") {
+		return nil, fmt.Errorf("The server is missing the required \"projects_usage_history\" API extension")
+	}
+
+	records := []api.ProjectUsageRecord{}
+
+	v := url.Values{}
+	v.Set("from", from.UTC().Format(time.RFC3339))
+	v.Set("to", to.UTC().Format(time.RFC3339))
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", fmt.Sprintf("/projects/%s/usage?%s", url.PathEscape(name), v.Encode()), nil, "", &records)
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
 // GetProjectAccess returns an Access entry for the specified project.
 func (r *ProtocolIncus) GetProjectAccess(name string) (api.Access, error) {
 	access := api.Access{}
@@ -0,0 +1,139 @@
+package incus
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Webhook handling functions
+
+// GetWebhookNames returns a list of webhook names.
+func (r *ProtocolIncus) GetWebhookNames() ([]string, error) {
+	if !r.HasExtension("webhooks") {
+		return nil, fmt.Errorf("The server is missing the required \"webhooks\" API extension")
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/webhooks"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetWebhooks returns a list of Webhook structs.
+func (r *ProtocolIncus) GetWebhooks() ([]api.Webhook, error) {
+	if !r.HasExtension("webhooks") {
+		return nil, fmt.Errorf("The server is missing the required \"webhooks\" API extension")
+	}
+
+	webhooks := []api.Webhook{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", "/webhooks?recursion=1", nil, "", &webhooks)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// GetWebhook returns a Webhook entry for the provided name.
+func (r *ProtocolIncus) GetWebhook(name string) (*api.Webhook, string, error) {
+	if !r.HasExtension("webhooks") {
+		return nil, "", fmt.Errorf("The server is missing the required \"webhooks\" API extension")
+	}
+
+	webhook := api.Webhook{}
+
+	// Fetch the raw value
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/webhooks/%s", url.PathEscape(name)), nil, "", &webhook)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &webhook, etag, nil
+}
+
+// CreateWebhook defines a new webhook.
+func (r *ProtocolIncus) CreateWebhook(webhook api.WebhooksPost) error {
+	if !r.HasExtension("webhooks") {
+		return fmt.Errorf("The server is missing the required \"webhooks\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("POST", "/webhooks", webhook, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateWebhook updates the webhook to match the provided WebhookPut struct.
+func (r *ProtocolIncus) UpdateWebhook(name string, webhook api.WebhookPut, ETag string) error {
+	if !r.HasExtension("webhooks") {
+		return fmt.Errorf("The server is missing the required \"webhooks\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("PUT", fmt.Sprintf("/webhooks/%s", url.PathEscape(name)), webhook, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteWebhook deletes a webhook.
+func (r *ProtocolIncus) DeleteWebhook(name string) error {
+	if !r.HasExtension("webhooks") {
+		return fmt.Errorf("The server is missing the required \"webhooks\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("DELETE", fmt.Sprintf("/webhooks/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetWebhookDeadLetters returns the events that couldn't be delivered to a webhook.
+func (r *ProtocolIncus) GetWebhookDeadLetters(name string) ([]api.WebhookDeadLetter, error) {
+	if !r.HasExtension("webhooks") {
+		return nil, fmt.Errorf("The server is missing the required \"webhooks\" API extension")
+	}
+
+	letters := []api.WebhookDeadLetter{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", fmt.Sprintf("/webhooks/%s/dead-letters", url.PathEscape(name)), nil, "", &letters)
+	if err != nil {
+		return nil, err
+	}
+
+	return letters, nil
+}
+
+// DeleteWebhookDeadLetters deletes the events that couldn't be delivered to a webhook.
+func (r *ProtocolIncus) DeleteWebhookDeadLetters(name string) error {
+	if !r.HasExtension("webhooks") {
+		return fmt.Errorf("The server is missing the required \"webhooks\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("DELETE", fmt.Sprintf("/webhooks/%s/dead-letters", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pkg/sftp"
@@ -107,6 +108,7 @@ type InstanceServer interface {
 	GetInstancesFullAllProjectsWithFilter(instanceType api.InstanceType, filters []string) (instances []api.InstanceFull, err error)
 	GetInstance(name string) (instance *api.Instance, ETag string, err error)
 	GetInstanceFull(name string) (instance *api.InstanceFull, ETag string, err error)
+	GetInstanceWithOrigin(name string) (instance *api.Instance, ETag string, err error)
 	CreateInstance(instance api.InstancesPost) (op Operation, err error)
 	CreateInstanceFromImage(source ImageServer, image api.Image, req api.InstancesPost) (op RemoteOperation, err error)
 	CopyInstance(source InstanceServer, instance api.Instance, args *InstanceCopyArgs) (op RemoteOperation, err error)
@@ -119,6 +121,7 @@ type InstanceServer interface {
 	RebuildInstanceFromImage(source ImageServer, image api.Image, instanceName string, req api.InstanceRebuildPost) (op RemoteOperation, err error)
 
 	ExecInstance(instanceName string, exec api.InstanceExecPost, args *InstanceExecArgs) (op Operation, err error)
+	AttachInstanceExec(operationID string, args *InstanceExecArgs) (op Operation, err error)
 	ConsoleInstance(instanceName string, console api.InstanceConsolePost, args *InstanceConsoleArgs) (op Operation, err error)
 	ConsoleInstanceDynamic(instanceName string, console api.InstanceConsolePost, args *InstanceConsoleArgs) (Operation, func(io.ReadWriteCloser) error, error)
 
@@ -153,6 +156,8 @@ type InstanceServer interface {
 
 	GetInstanceState(name string) (state *api.InstanceState, ETag string, err error)
 	UpdateInstanceState(name string, state api.InstanceStatePut, ETag string) (op Operation, err error)
+	WaitInstance(name string, condition string, timeout int) (state *api.InstanceState, err error)
+	GetInstancePortForward(name string, port int) (conn *websocket.Conn, err error)
 
 	GetInstanceAccess(name string) (access api.Access, err error)
 
@@ -163,6 +168,11 @@ type InstanceServer interface {
 	GetInstanceMetadata(name string) (metadata *api.ImageMetadata, ETag string, err error)
 	UpdateInstanceMetadata(name string, metadata api.ImageMetadata, ETag string) (err error)
 
+	GetInstanceInventory(name string) (inventory *api.InstanceInventory, err error)
+
+	GetInstanceSSHKeys(instanceName string, user string) (keys *api.InstanceSSHKeys, ETag string, err error)
+	UpdateInstanceSSHKeys(instanceName string, user string, keys api.InstanceSSHKeysPut, ETag string) (err error)
+
 	GetInstanceTemplateFiles(instanceName string) (templates []string, err error)
 	GetInstanceTemplateFile(instanceName string, templateName string) (content io.ReadCloser, err error)
 	CreateInstanceTemplateFile(instanceName string, templateName string, content io.ReadSeeker) (err error)
@@ -171,6 +181,8 @@ type InstanceServer interface {
 	// Event handling functions
 	GetEvents() (listener *EventListener, err error)
 	GetEventsAllProjects() (listener *EventListener, err error)
+	GetEventsSince(since int64) (listener *EventListener, err error)
+	GetEventsAllProjectsSince(since int64) (listener *EventListener, err error)
 	SendEvent(event api.Event) error
 
 	// Image functions
@@ -289,6 +301,7 @@ type InstanceServer interface {
 	GetProjects() (projects []api.Project, err error)
 	GetProject(name string) (project *api.Project, ETag string, err error)
 	GetProjectState(name string) (project *api.ProjectState, err error)
+	GetProjectUsage(name string, from time.Time, to time.Time) (records []api.ProjectUsageRecord, err error)
 	GetProjectAccess(name string) (access api.Access, err error)
 	CreateProject(project api.ProjectsPost) (err error)
 	UpdateProject(name string, project api.ProjectPut, ETag string) (err error)
@@ -296,6 +309,14 @@ type InstanceServer interface {
 	DeleteProject(name string) (err error)
 	DeleteProjectForce(name string) (err error)
 
+	// Project template functions
+	GetProjectTemplateNames() (names []string, err error)
+	GetProjectTemplates() (templates []api.ProjectTemplate, err error)
+	GetProjectTemplate(name string) (template *api.ProjectTemplate, ETag string, err error)
+	CreateProjectTemplate(template api.ProjectTemplatesPost) (err error)
+	UpdateProjectTemplate(name string, template api.ProjectTemplatePut, ETag string) (err error)
+	DeleteProjectTemplate(name string) (err error)
+
 	// Storage pool functions ("storage" API extension)
 	GetStoragePoolNames() (names []string, err error)
 	GetStoragePools() (pools []api.StoragePool, err error)
@@ -393,6 +414,16 @@ type InstanceServer interface {
 	UpdateWarning(UUID string, warning api.WarningPut, ETag string) (err error)
 	DeleteWarning(UUID string) (err error)
 
+	// Webhook functions
+	GetWebhookNames() (names []string, err error)
+	GetWebhooks() (webhooks []api.Webhook, err error)
+	GetWebhook(name string) (webhook *api.Webhook, ETag string, err error)
+	CreateWebhook(webhook api.WebhooksPost) (err error)
+	UpdateWebhook(name string, webhook api.WebhookPut, ETag string) (err error)
+	DeleteWebhook(name string) (err error)
+	GetWebhookDeadLetters(name string) (letters []api.WebhookDeadLetter, err error)
+	DeleteWebhookDeadLetters(name string) (err error)
+
 	// Internal functions (for internal use)
 	RawQuery(method string, path string, data any, queryETag string) (resp *api.Response, ETag string, err error)
 	RawWebsocket(path string) (conn *websocket.Conn, err error)
@@ -644,6 +675,10 @@ type InstanceFileArgs struct {
 
 	// File write mode (overwrite or append)
 	WriteMode string
+
+	// Offset at which to start writing the content, used to resume an interrupted upload (overwrite only)
+	// The content must then only contain the remainder of the file.
+	Offset int64
 }
 
 // The InstanceFileResponse struct is used as part of the response for a instance file download.
@@ -29,14 +29,20 @@ var api10Cmd = APIEndpoint{
 
 var api10 = []APIEndpoint{
 	api10Cmd,
+	clockCmd,
 	execCmd,
 	eventsCmd,
+	inventoryCmd,
 	metricsCmd,
 	operationsCmd,
 	operationCmd,
 	operationWebsocket,
 	operationWait,
+	portForwardCmd,
+	quiesceCmd,
 	sftpCmd,
+	shutdownCmd,
+	sshKeysCmd,
 	stateCmd,
 }
 
@@ -91,6 +97,10 @@ func setConnectionInfo(d *Daemon, rd io.Reader) error {
 	d.DevIncusEnabled = data.DevIncus
 	d.DevIncusMu.Unlock()
 
+	if data.PTPKVM {
+		ptpKVMLoad()
+	}
+
 	return nil
 }
 
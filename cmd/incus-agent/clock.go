@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/internal/server/response"
+	agentAPI "github.com/lxc/incus/v6/shared/api/agent"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+var clockCmd = APIEndpoint{
+	Name: "clock",
+	Path: "clock",
+
+	Put: APIEndpointAction{Handler: clockPut},
+}
+
+func clockPut(d *Daemon, r *http.Request) response.Response {
+	req := agentAPI.ClockPut{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Time.IsZero() {
+		return response.BadRequest(fmt.Errorf("A time must be provided"))
+	}
+
+	offset := time.Until(req.Time)
+
+	tv := unix.NsecToTimeval(req.Time.UnixNano())
+	err = unix.Settimeofday(&tv)
+	if err != nil {
+		return response.InternalError(fmt.Errorf("Failed setting the clock: %w", err))
+	}
+
+	logger.Info("Stepped the clock from the host", logger.Ctx{"offset": offset})
+
+	return response.EmptySyncResponse
+}
+
+// ptpKVMLoad loads the KVM PTP clock driver and makes its device available as /dev/ptp_kvm so it can be
+// used as a time source by the guest.
+func ptpKVMLoad() {
+	err := linux.LoadModule("ptp_kvm")
+	if err != nil {
+		logger.Warn("Failed loading the KVM PTP clock driver", logger.Ctx{"err": err})
+		return
+	}
+
+	// The link is usually created by udev, but not all guests run it.
+	if util.PathExists("/dev/ptp_kvm") {
+		return
+	}
+
+	clockNames, err := filepath.Glob("/sys/class/ptp/ptp*/clock_name")
+	if err != nil {
+		return
+	}
+
+	for _, clockName := range clockNames {
+		name, err := os.ReadFile(clockName)
+		if err != nil || strings.TrimSpace(string(name)) != "KVM virtual PTP" {
+			continue
+		}
+
+		err = os.Symlink(filepath.Base(filepath.Dir(clockName)), "/dev/ptp_kvm")
+		if err != nil {
+			logger.Warn("Failed creating the KVM PTP clock device link", logger.Ctx{"err": err})
+		}
+
+		return
+	}
+
+	logger.Warn("KVM PTP clock device not found")
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"sync"
+	"time"
 
 	"github.com/lxc/incus/v6/internal/server/events"
 )
@@ -22,6 +23,11 @@ type Daemon struct {
 	DevIncusRunning bool
 	DevIncusMu      sync.Mutex
 	DevIncusEnabled bool
+
+	// Guest filesystems frozen by the host.
+	quiesceMu    sync.Mutex
+	quiesced     []string
+	quiesceTimer *time.Timer
 }
 
 // newDaemon returns a new Daemon object with the given configuration.
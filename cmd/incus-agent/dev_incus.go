@@ -102,6 +102,19 @@ var DevIncusConfigKeyGet = devIncusHandler{"/1.0/config/{key}", func(d *Daemon,
 
 	defer client.Disconnect()
 
+	if r.Method == "PUT" || r.Method == "DELETE" {
+		if !strings.HasPrefix(key, "user.guest.") {
+			return &devIncusResponse{"not authorized", http.StatusForbidden, "raw"}
+		}
+
+		_, _, err := client.RawQuery(r.Method, fmt.Sprintf("/1.0/config/%s", key), r.Body, "")
+		if err != nil {
+			return smartResponse(err)
+		}
+
+		return okResponse("", "raw")
+	}
+
 	resp, _, err := client.RawQuery("GET", fmt.Sprintf("/1.0/config/%s", key), nil, "")
 	if err != nil {
 		return smartResponse(err)
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/lxc/incus/v6/internal/inventory"
+	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+var inventoryCmd = APIEndpoint{
+	Name: "inventory",
+	Path: "inventory",
+
+	Get: APIEndpointAction{Handler: inventoryGet},
+}
+
+func inventoryGet(d *Daemon, r *http.Request) response.Response {
+	result, err := inventory.Collect(os.DirFS("/"), subprocess.RunCommand)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	uname, err := linux.Uname()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	result.OS.Kernel = uname.Release
+
+	return response.SyncResponse(true, result)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+var portForwardCmd = APIEndpoint{
+	Name: "port-forward",
+	Path: "port-forward",
+
+	Get: APIEndpointAction{Handler: portForwardHandler},
+}
+
+func portForwardHandler(d *Daemon, r *http.Request) response.Response {
+	return &portForwardServe{d, r}
+}
+
+type portForwardServe struct {
+	d *Daemon
+	r *http.Request
+}
+
+func (r *portForwardServe) String() string {
+	return "port-forward handler"
+}
+
+// Code returns the HTTP code.
+func (r *portForwardServe) Code() int {
+	return http.StatusOK
+}
+
+func (r *portForwardServe) Render(w http.ResponseWriter) error {
+	// Upgrade to a raw TCP stream.
+	if r.r.Header.Get("Upgrade") != "tcp" {
+		http.Error(w, "Missing or invalid upgrade header", http.StatusBadRequest)
+		return nil
+	}
+
+	port, err := strconv.ParseUint(r.r.FormValue("port"), 10, 16)
+	if err != nil || port == 0 {
+		http.Error(w, "Invalid port", http.StatusBadRequest)
+		return nil
+	}
+
+	// Connect to the target before upgrading so failures can be reported.
+	target, err := net.Dial("tcp", net.JoinHostPort("localhost", fmt.Sprintf("%d", port)))
+	if err != nil {
+		http.Error(w, fmt.Errorf("Failed to connect to port %d: %w", port, err).Error(), http.StatusBadGateway)
+		return nil
+	}
+
+	defer func() { _ = target.Close() }()
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Webserver doesn't support hijacking", http.StatusInternalServerError)
+
+		return nil
+	}
+
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		http.Error(w, fmt.Errorf("Failed to hijack connection: %w", err).Error(), http.StatusInternalServerError)
+
+		return nil
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	err = response.Upgrade(conn, "tcp")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return nil
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := io.Copy(conn, target)
+		if err != nil {
+			logger.Debug("Port forwarding stopped", logger.Ctx{"port": port, "err": err})
+		}
+
+		_ = conn.Close()
+	}()
+
+	// Let the target know when the client is done sending, or disconnect it if the connection broke.
+	_, err = io.Copy(target, conn)
+	tcpConn, ok := target.(*net.TCPConn)
+	if err == nil && ok {
+		_ = tcpConn.CloseWrite()
+	} else {
+		_ = target.Close()
+	}
+
+	wg.Wait()
+
+	return nil
+}
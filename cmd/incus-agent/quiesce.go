@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/server/response"
+	agentAPI "github.com/lxc/incus/v6/shared/api/agent"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+// Directory holding the executables called before freezing and after thawing the filesystems.
+const quiesceHooksPath = "/etc/incus-agent/quiesce.d"
+
+// How long the filesystems stay frozen when the host doesn't provide a timeout.
+const quiesceDefaultTimeout = 5 * time.Minute
+
+// How long a single quiesce hook is allowed to run.
+const quiesceHookTimeout = time.Minute
+
+// FIFREEZE and FITHAW ioctls, respectively _IOWR('X', 119, int) and _IOWR('X', 120, int).
+const (
+	ioctlFIFREEZE = 0xc0045877
+	ioctlFITHAW   = 0xc0045878
+)
+
+// Escape sequences used by the kernel for mount points in /proc/mounts.
+var mountPathUnescaper = strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`)
+
+var quiesceCmd = APIEndpoint{
+	Name: "quiesce",
+	Path: "quiesce",
+
+	Get:    APIEndpointAction{Handler: quiesceGet},
+	Post:   APIEndpointAction{Handler: quiescePost},
+	Delete: APIEndpointAction{Handler: quiesceDelete},
+}
+
+func quiesceGet(d *Daemon, r *http.Request) response.Response {
+	d.quiesceMu.Lock()
+	defer d.quiesceMu.Unlock()
+
+	return response.SyncResponse(true, agentAPI.Quiesce{Filesystems: append([]string{}, d.quiesced...)})
+}
+
+func quiescePost(d *Daemon, r *http.Request) response.Response {
+	req := agentAPI.QuiescePost{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	timeout := quiesceDefaultTimeout
+	if req.Timeout > 0 {
+		timeout = time.Duration(req.Timeout) * time.Second
+	}
+
+	d.quiesceMu.Lock()
+	defer d.quiesceMu.Unlock()
+
+	if d.quiesced != nil {
+		return response.Conflict(fmt.Errorf("Filesystems are already frozen"))
+	}
+
+	err = quiesceHooksRun("freeze")
+	if err != nil {
+		_ = quiesceHooksRun("thaw")
+		return response.InternalError(err)
+	}
+
+	frozen, err := filesystemsFreeze()
+	if err != nil {
+		_ = quiesceHooksRun("thaw")
+		return response.InternalError(err)
+	}
+
+	logger.Info("Froze filesystems", logger.Ctx{"filesystems": frozen, "timeout": timeout})
+
+	// Make sure the guest doesn't stay frozen if the host never comes back to thaw it.
+	var timer *time.Timer
+	timer = time.AfterFunc(timeout, func() {
+		d.quiesceMu.Lock()
+		defer d.quiesceMu.Unlock()
+
+		// Skip if the filesystems got thawed (and possibly frozen again) in the meantime.
+		if d.quiesceTimer != timer {
+			return
+		}
+
+		logger.Warn("Thawing filesystems after freeze timeout", logger.Ctx{"timeout": timeout})
+
+		err := quiesceThaw(d)
+		if err != nil {
+			logger.Error("Failed thawing filesystems", logger.Ctx{"err": err})
+		}
+	})
+
+	d.quiesced = frozen
+	d.quiesceTimer = timer
+
+	return response.SyncResponse(true, agentAPI.Quiesce{Filesystems: frozen})
+}
+
+func quiesceDelete(d *Daemon, r *http.Request) response.Response {
+	d.quiesceMu.Lock()
+	defer d.quiesceMu.Unlock()
+
+	if d.quiesced == nil {
+		return response.EmptySyncResponse
+	}
+
+	err := quiesceThaw(d)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// quiesceThaw thaws the frozen filesystems and runs the thaw hooks.
+// The caller must hold d.quiesceMu.
+func quiesceThaw(d *Daemon) error {
+	if d.quiesceTimer != nil {
+		d.quiesceTimer.Stop()
+		d.quiesceTimer = nil
+	}
+
+	err := filesystemsThaw(d.quiesced)
+	d.quiesced = nil
+
+	hookErr := quiesceHooksRun("thaw")
+
+	logger.Info("Thawed filesystems")
+
+	return errors.Join(err, hookErr)
+}
+
+// quiesceHooksRun calls every executable in the hooks directory with the provided action.
+// Freeze hooks are run in lexical order and stop at the first failure while thaw hooks are all run in
+// reverse order.
+func quiesceHooksRun(action string) error {
+	entries, err := os.ReadDir(quiesceHooksPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("Failed listing quiesce hooks: %w", err)
+	}
+
+	if action == "thaw" {
+		slices.Reverse(entries)
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), quiesceHookTimeout)
+		_, err = subprocess.RunCommandContext(ctx, filepath.Join(quiesceHooksPath, entry.Name()), action)
+		cancel()
+		if err != nil {
+			err = fmt.Errorf("Failed running %s hook %q: %w", action, entry.Name(), err)
+			if action == "freeze" {
+				return err
+			}
+
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// filesystemsFreeze freezes all the guest filesystems supporting it and returns their mount points.
+func filesystemsFreeze() ([]string, error) {
+	content, err := os.ReadFile("/proc/mounts")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read /proc/mounts: %w", err)
+	}
+
+	mountpoints := []string{}
+	devices := map[uint64]bool{}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		mountpoint := mountPathUnescaper.Replace(fields[1])
+
+		// Skip uninteresting mounts.
+		if slices.Contains(defFSTypesExcluded, fields[2]) || defMountPointsExcluded.MatchString(mountpoint) {
+			continue
+		}
+
+		// Only freeze each filesystem once, even if mounted multiple times.
+		var stat unix.Stat_t
+		err := unix.Stat(mountpoint, &stat)
+		if err != nil || devices[stat.Dev] {
+			continue
+		}
+
+		devices[stat.Dev] = true
+		mountpoints = append(mountpoints, mountpoint)
+	}
+
+	// Freeze the most recent (nested) mounts first.
+	frozen := []string{}
+	for i := len(mountpoints) - 1; i >= 0; i-- {
+		err := filesystemIoctl(mountpoints[i], ioctlFIFREEZE)
+		if err != nil {
+			// Skip filesystems without freeze support (tmpfs, virtiofs, ...).
+			if errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.ENOTTY) {
+				continue
+			}
+
+			_ = filesystemsThaw(frozen)
+			return nil, fmt.Errorf("Failed freezing %q: %w", mountpoints[i], err)
+		}
+
+		frozen = append(frozen, mountpoints[i])
+	}
+
+	return frozen, nil
+}
+
+// filesystemsThaw thaws the provided filesystems in the reverse order in which they got frozen.
+func filesystemsThaw(mountpoints []string) error {
+	var errs []error
+	for i := len(mountpoints) - 1; i >= 0; i-- {
+		err := filesystemIoctl(mountpoints[i], ioctlFITHAW)
+
+		// EINVAL is returned when the filesystem isn't frozen.
+		if err != nil && !errors.Is(err, unix.EINVAL) {
+			errs = append(errs, fmt.Errorf("Failed thawing %q: %w", mountpoints[i], err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func filesystemIoctl(mountpoint string, req uint) error {
+	f, err := os.Open(mountpoint)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	return unix.IoctlSetInt(int(f.Fd()), req, 0)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+
+	"github.com/lxc/incus/v6/internal/server/response"
+	agentAPI "github.com/lxc/incus/v6/shared/api/agent"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+var shutdownCmd = APIEndpoint{
+	Name: "shutdown",
+	Path: "shutdown",
+
+	Post: APIEndpointAction{Handler: shutdownPost},
+}
+
+func shutdownPost(d *Daemon, r *http.Request) response.Response {
+	req := agentAPI.ShutdownPost{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	command := req.Command
+	if len(command) == 0 {
+		command = shutdownDefaultCommand()
+	}
+
+	logger.Info("Shutting down the guest", logger.Ctx{"command": command})
+
+	// Don't tie the command to the request as the connection goes away with the guest.
+	cmd := exec.Command(command[0], command[1:]...)
+	err = cmd.Start()
+	if err != nil {
+		return response.InternalError(fmt.Errorf("Failed running shutdown command: %w", err))
+	}
+
+	// Reply right away as the command may only return once the guest is going down. A failure is
+	// only logged as the host is then left to time out waiting for the guest to stop.
+	go func() {
+		err := cmd.Wait()
+		if err != nil {
+			logger.Error("Shutdown command failed", logger.Ctx{"command": command, "err": err})
+		}
+	}()
+
+	return response.EmptySyncResponse
+}
+
+// shutdownDefaultCommand returns the command powering off the guest through its init system.
+func shutdownDefaultCommand() []string {
+	if util.PathExists("/run/systemd/system") {
+		return []string{"systemctl", "poweroff"}
+	}
+
+	return []string{"poweroff"}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+var sshKeysCmd = APIEndpoint{
+	Name: "sshKeys",
+	Path: "ssh-keys/{user}",
+
+	Get: APIEndpointAction{Handler: sshKeysGet},
+	Put: APIEndpointAction{Handler: sshKeysPut},
+}
+
+func sshKeysGet(d *Daemon, r *http.Request) response.Response {
+	username, err := url.PathUnescape(mux.Vars(r)["user"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = internalInstance.ValidGuestUser(username)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	u, err := user.Lookup(username)
+	if err != nil {
+		if errors.As(err, new(user.UnknownUserError)) {
+			return response.NotFound(fmt.Errorf("User %q not found", username))
+		}
+
+		return response.InternalError(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(u.HomeDir, internalInstance.SSHAuthorizedKeysPath))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return response.InternalError(err)
+	}
+
+	keys := api.InstanceSSHKeys{User: username}
+	keys.Keys = internalInstance.ParseAuthorizedKeys(content)
+
+	return response.SyncResponse(true, keys)
+}
+
+func sshKeysPut(d *Daemon, r *http.Request) response.Response {
+	username, err := url.PathUnescape(mux.Vars(r)["user"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = internalInstance.ValidGuestUser(username)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	req := api.InstanceSSHKeysPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	for _, key := range req.Keys {
+		err = internalInstance.ValidSSHKey(key)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	u, err := user.Lookup(username)
+	if err != nil {
+		if !errors.As(err, new(user.UnknownUserError)) {
+			return response.InternalError(err)
+		}
+
+		// Create the missing user, falling back to busybox's adduser when useradd isn't available.
+		_, err = subprocess.RunCommand("useradd", "--create-home", username)
+		if err != nil {
+			_, err = subprocess.RunCommand("adduser", "-D", username)
+			if err != nil {
+				return response.InternalError(fmt.Errorf("Failed creating user %q: %w", username, err))
+			}
+		}
+
+		logger.Info("Created guest user", logger.Ctx{"user": username})
+
+		u, err = user.Lookup(username)
+		if err != nil {
+			return response.InternalError(err)
+		}
+	}
+
+	err = sshKeysWrite(u, req.Keys)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// sshKeysWrite replaces the authorized keys file of the user.
+func sshKeysWrite(u *user.User, keys []string) error {
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return err
+	}
+
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return err
+	}
+
+	return internalInstance.WriteAuthorizedKeys(u.HomeDir, uid, gid, keys)
+}
@@ -0,0 +1,592 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/lxc/incus/v6/client"
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// applyManifest is the desired state of a server, as read from a manifest file.
+type applyManifest struct {
+	Projects     []api.ProjectsPost      `yaml:"projects"`
+	StoragePools []api.StoragePoolsPost  `yaml:"storage_pools"`
+	NetworkACLs  []applyManifestACL      `yaml:"network_acls"`
+	Networks     []applyManifestNetwork  `yaml:"networks"`
+	Profiles     []applyManifestProfile  `yaml:"profiles"`
+	Instances    []applyManifestInstance `yaml:"instances"`
+}
+
+// applyManifestACL is a network ACL of a manifest, along with its project.
+type applyManifestACL struct {
+	api.NetworkACLsPost `yaml:",inline"`
+
+	Project string `yaml:"project"`
+}
+
+// applyManifestNetwork is a network of a manifest, along with its project.
+type applyManifestNetwork struct {
+	api.NetworksPost `yaml:",inline"`
+
+	Project string `yaml:"project"`
+}
+
+// applyManifestProfile is a profile of a manifest, along with its project.
+type applyManifestProfile struct {
+	api.ProfilesPost `yaml:",inline"`
+
+	Project string `yaml:"project"`
+}
+
+// applyManifestInstance is an instance of a manifest, along with its project.
+type applyManifestInstance struct {
+	api.InstancesPost `yaml:",inline"`
+
+	Project string `yaml:"project"`
+}
+
+// applyChange is a change of the plan to reconcile the server with a manifest.
+type applyChange struct {
+	create  bool
+	entity  string
+	details []string
+	apply   func() error
+}
+
+// String returns the description of the change as shown in the plan.
+func (c applyChange) String() string {
+	prefix := "~"
+	if c.create {
+		prefix = "+"
+	}
+
+	lines := []string{fmt.Sprintf("%s %s", prefix, c.entity)}
+	for _, detail := range c.details {
+		lines = append(lines, "    "+detail)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+type cmdApply struct {
+	global *cmdGlobal
+
+	flagFile   string
+	flagDryRun bool
+}
+
+func (c *cmdApply) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("apply", i18n.G("[<remote>:] -f <manifest>"))
+	cmd.Short = i18n.G("Apply a declarative manifest")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Apply a declarative manifest
+
+The manifest lists projects, storage pools, network ACLs, networks, profiles and instances.
+Those that don't exist on the server are created, and the existing ones are updated
+to match the manifest. The changes are shown before being applied.
+
+Configuration keys that aren't in the manifest are left untouched, and so are the entities
+that aren't in the manifest. Devices and the profiles of the instances are replaced when
+set in the manifest. A value of "auto" matches any value that the server already has.
+
+Entities without a project are in the default project.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus apply -f manifest.yaml
+    Reconcile the server with manifest.yaml.
+
+incus apply -f manifest.yaml --dry-run
+    Only show the changes needed to reconcile the server with manifest.yaml.
+
+Example manifest:
+
+    projects:
+    - name: web
+      config:
+        features.profiles: "true"
+        limits.instances: "10"
+    profiles:
+    - name: default
+      project: web
+      devices:
+        root:
+          type: disk
+          path: /
+          pool: default
+    instances:
+    - name: web01
+      project: web
+      source:
+        type: image
+        alias: debian/12
+        server: https://images.linuxcontainers.org
+        protocol: simplestreams
+      start: true`))
+
+	cmd.Flags().StringVarP(&c.flagFile, "file", "f", "", i18n.G("Manifest file (- for stdin)")+"``")
+	cmd.Flags().BoolVar(&c.flagDryRun, "dry-run", false, i18n.G("Only show the changes without applying them"))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdApply) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	if c.flagFile == "" {
+		return fmt.Errorf(i18n.G("A manifest file must be provided with --file"))
+	}
+
+	remote := ""
+	if len(args) > 0 {
+		if !strings.HasSuffix(args[0], ":") {
+			return fmt.Errorf(i18n.G("Invalid remote %q"), args[0])
+		}
+
+		remote = args[0]
+	}
+
+	// Read the manifest.
+	var content []byte
+	if c.flagFile == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(c.flagFile)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	manifest := applyManifest{}
+	err = yaml.UnmarshalStrict(content, &manifest)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed parsing the manifest: %w"), err)
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	d := resources[0].server
+
+	// Compute the plan.
+	changes, err := c.plan(d, manifest)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		fmt.Println(i18n.G("No changes, the server matches the manifest"))
+		return nil
+	}
+
+	fmt.Println(i18n.G("Plan:"))
+	for _, change := range changes {
+		fmt.Println(change.String())
+	}
+
+	if c.flagDryRun {
+		return nil
+	}
+
+	// Apply it.
+	for _, change := range changes {
+		err := change.apply()
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed applying change to %s: %w"), change.entity, err)
+		}
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Applied %d changes")+"\n", len(changes))
+	}
+
+	return nil
+}
+
+// plan computes the changes needed to reconcile the server with the manifest, in the order they must be applied.
+func (c *cmdApply) plan(d incus.InstanceServer, manifest applyManifest) ([]applyChange, error) {
+	changes := []applyChange{}
+
+	for _, target := range manifest.Projects {
+		change, err := c.planProject(d, target)
+		if err != nil {
+			return nil, err
+		}
+
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	for _, target := range manifest.StoragePools {
+		change, err := c.planStoragePool(d, target)
+		if err != nil {
+			return nil, err
+		}
+
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	for _, target := range manifest.NetworkACLs {
+		change, err := c.planNetworkACL(d.UseProject(applyProject(target.Project)), target)
+		if err != nil {
+			return nil, err
+		}
+
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	for _, target := range manifest.Networks {
+		change, err := c.planNetwork(d.UseProject(applyProject(target.Project)), target)
+		if err != nil {
+			return nil, err
+		}
+
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	for _, target := range manifest.Profiles {
+		change, err := c.planProfile(d.UseProject(applyProject(target.Project)), target)
+		if err != nil {
+			return nil, err
+		}
+
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	for _, target := range manifest.Instances {
+		change, err := c.planInstance(d.UseProject(applyProject(target.Project)), target)
+		if err != nil {
+			return nil, err
+		}
+
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	return changes, nil
+}
+
+func (c *cmdApply) planProject(d incus.InstanceServer, target api.ProjectsPost) (*applyChange, error) {
+	entity := fmt.Sprintf(i18n.G("project %q"), target.Name)
+
+	current, etag, err := d.GetProject(target.Name)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, err
+		}
+
+		return &applyChange{create: true, entity: entity, apply: func() error {
+			return d.CreateProject(target)
+		}}, nil
+	}
+
+	put := current.Writable()
+	details := applyDescription(&put.Description, target.Description)
+	details = append(details, applyConfig(&put.Config, target.Config)...)
+	if len(details) == 0 {
+		return nil, nil
+	}
+
+	return &applyChange{entity: entity, details: details, apply: func() error {
+		return d.UpdateProject(target.Name, put, etag)
+	}}, nil
+}
+
+func (c *cmdApply) planStoragePool(d incus.InstanceServer, target api.StoragePoolsPost) (*applyChange, error) {
+	entity := fmt.Sprintf(i18n.G("storage pool %q"), target.Name)
+
+	current, etag, err := d.GetStoragePool(target.Name)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, err
+		}
+
+		return &applyChange{create: true, entity: entity, apply: func() error {
+			return d.CreateStoragePool(target)
+		}}, nil
+	}
+
+	if target.Driver != "" && current.Driver != target.Driver {
+		return nil, fmt.Errorf(i18n.G("Storage pool %q uses the %q driver instead of %q"), target.Name, current.Driver, target.Driver)
+	}
+
+	put := current.Writable()
+	details := applyDescription(&put.Description, target.Description)
+	details = append(details, applyConfig(&put.Config, target.Config)...)
+	if len(details) == 0 {
+		return nil, nil
+	}
+
+	return &applyChange{entity: entity, details: details, apply: func() error {
+		return d.UpdateStoragePool(target.Name, put, etag)
+	}}, nil
+}
+
+func (c *cmdApply) planNetworkACL(d incus.InstanceServer, target applyManifestACL) (*applyChange, error) {
+	entity := fmt.Sprintf(i18n.G("network ACL %q in project %q"), target.Name, applyProject(target.Project))
+
+	current, etag, err := d.GetNetworkACL(target.Name)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, err
+		}
+
+		return &applyChange{create: true, entity: entity, apply: func() error {
+			return d.CreateNetworkACL(target.NetworkACLsPost)
+		}}, nil
+	}
+
+	put := current.Writable()
+	details := applyDescription(&put.Description, target.Description)
+	details = append(details, applyConfig(&put.Config, target.Config)...)
+
+	if target.Ingress != nil && !reflect.DeepEqual(put.Ingress, target.Ingress) {
+		put.Ingress = target.Ingress
+		details = append(details, i18n.G("ingress rules"))
+	}
+
+	if target.Egress != nil && !reflect.DeepEqual(put.Egress, target.Egress) {
+		put.Egress = target.Egress
+		details = append(details, i18n.G("egress rules"))
+	}
+
+	if len(details) == 0 {
+		return nil, nil
+	}
+
+	return &applyChange{entity: entity, details: details, apply: func() error {
+		return d.UpdateNetworkACL(target.Name, put, etag)
+	}}, nil
+}
+
+func (c *cmdApply) planNetwork(d incus.InstanceServer, target applyManifestNetwork) (*applyChange, error) {
+	entity := fmt.Sprintf(i18n.G("network %q in project %q"), target.Name, applyProject(target.Project))
+
+	current, etag, err := d.GetNetwork(target.Name)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, err
+		}
+
+		return &applyChange{create: true, entity: entity, apply: func() error {
+			return d.CreateNetwork(target.NetworksPost)
+		}}, nil
+	}
+
+	if !current.Managed {
+		return nil, fmt.Errorf(i18n.G("Network %q isn't managed"), target.Name)
+	}
+
+	if target.Type != "" && current.Type != target.Type {
+		return nil, fmt.Errorf(i18n.G("Network %q is of type %q instead of %q"), target.Name, current.Type, target.Type)
+	}
+
+	put := current.Writable()
+	details := applyDescription(&put.Description, target.Description)
+	details = append(details, applyConfig(&put.Config, target.Config)...)
+	if len(details) == 0 {
+		return nil, nil
+	}
+
+	return &applyChange{entity: entity, details: details, apply: func() error {
+		return d.UpdateNetwork(target.Name, put, etag)
+	}}, nil
+}
+
+func (c *cmdApply) planProfile(d incus.InstanceServer, target applyManifestProfile) (*applyChange, error) {
+	entity := fmt.Sprintf(i18n.G("profile %q in project %q"), target.Name, applyProject(target.Project))
+
+	current, etag, err := d.GetProfile(target.Name)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, err
+		}
+
+		// The default profile comes with the project, so it's only updated once the project is created.
+		if target.Name == "default" {
+			return &applyChange{create: true, entity: entity, apply: func() error {
+				current, etag, err := d.GetProfile(target.Name)
+				if err != nil {
+					return err
+				}
+
+				put := current.Writable()
+				applyDescription(&put.Description, target.Description)
+				applyConfig(&put.Config, target.Config)
+				applyDevices(&put.Devices, target.Devices)
+
+				return d.UpdateProfile(target.Name, put, etag)
+			}}, nil
+		}
+
+		return &applyChange{create: true, entity: entity, apply: func() error {
+			return d.CreateProfile(target.ProfilesPost)
+		}}, nil
+	}
+
+	put := current.Writable()
+	details := applyDescription(&put.Description, target.Description)
+	details = append(details, applyConfig(&put.Config, target.Config)...)
+	details = append(details, applyDevices(&put.Devices, target.Devices)...)
+	if len(details) == 0 {
+		return nil, nil
+	}
+
+	return &applyChange{entity: entity, details: details, apply: func() error {
+		return d.UpdateProfile(target.Name, put, etag)
+	}}, nil
+}
+
+func (c *cmdApply) planInstance(d incus.InstanceServer, target applyManifestInstance) (*applyChange, error) {
+	entity := fmt.Sprintf(i18n.G("instance %q in project %q"), target.Name, applyProject(target.Project))
+
+	current, etag, err := d.GetInstance(target.Name)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, err
+		}
+
+		return &applyChange{create: true, entity: entity, apply: func() error {
+			op, err := d.CreateInstance(target.InstancesPost)
+			if err != nil {
+				return err
+			}
+
+			return op.Wait()
+		}}, nil
+	}
+
+	put := current.Writable()
+	details := applyDescription(&put.Description, target.Description)
+	details = append(details, applyConfig(&put.Config, target.Config)...)
+	details = append(details, applyDevices(&put.Devices, target.Devices)...)
+
+	if target.Profiles != nil && !slices.Equal(put.Profiles, target.Profiles) {
+		details = append(details, fmt.Sprintf(i18n.G("profiles: %s -> %s"), strings.Join(put.Profiles, ", "), strings.Join(target.Profiles, ", ")))
+		put.Profiles = target.Profiles
+	}
+
+	if len(details) == 0 {
+		return nil, nil
+	}
+
+	return &applyChange{entity: entity, details: details, apply: func() error {
+		op, err := d.UpdateInstance(target.Name, put, etag)
+		if err != nil {
+			return err
+		}
+
+		return op.Wait()
+	}}, nil
+}
+
+// applyProject returns the project of a manifest entity.
+func applyProject(project string) string {
+	if project == "" {
+		return api.ProjectDefaultName
+	}
+
+	return project
+}
+
+// applyDescription sets the description if the manifest has one that differs, and returns the matching plan details.
+func applyDescription(current *string, target string) []string {
+	if target == "" || *current == target {
+		return nil
+	}
+
+	detail := fmt.Sprintf(i18n.G("description: %q -> %q"), *current, target)
+	*current = target
+
+	return []string{detail}
+}
+
+// applyConfig sets the keys of the current configuration that differ from the manifest, and returns the
+// matching plan details. A manifest value of "auto" matches any value already set.
+func applyConfig(current *map[string]string, target map[string]string) []string {
+	if *current == nil {
+		*current = map[string]string{}
+	}
+
+	keys := make([]string, 0, len(target))
+	for k := range target {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	details := []string{}
+	for _, k := range keys {
+		value, ok := (*current)[k]
+		if ok && (value == target[k] || (target[k] == "auto" && value != "")) {
+			continue
+		}
+
+		if ok {
+			details = append(details, fmt.Sprintf("%s: %q -> %q", k, value, target[k]))
+		} else {
+			details = append(details, fmt.Sprintf("%s: %q", k, target[k]))
+		}
+
+		(*current)[k] = target[k]
+	}
+
+	return details
+}
+
+// applyDevices replaces the current devices with those of the manifest if they differ, and returns the
+// matching plan details.
+func applyDevices(current *map[string]map[string]string, target map[string]map[string]string) []string {
+	if target == nil || (len(*current) == 0 && len(target) == 0) || reflect.DeepEqual(*current, target) {
+		return nil
+	}
+
+	names := []string{}
+	for name, device := range target {
+		if !reflect.DeepEqual((*current)[name], device) {
+			names = append(names, name)
+		}
+	}
+
+	for name := range *current {
+		_, ok := target[name]
+		if !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	*current = target
+
+	return []string{fmt.Sprintf(i18n.G("devices: %s"), strings.Join(names, ", "))}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type applyTestSuite struct {
+	suite.Suite
+}
+
+func TestApplyTestSuite(t *testing.T) {
+	suite.Run(t, new(applyTestSuite))
+}
+
+func (s *applyTestSuite) TestApplyConfig() {
+	tests := []struct {
+		name     string
+		current  map[string]string
+		target   map[string]string
+		expected map[string]string
+		changes  int
+	}{
+		{
+			name:     "unchanged",
+			current:  map[string]string{"limits.cpu": "2", "user.foo": "bar"},
+			target:   map[string]string{"limits.cpu": "2"},
+			expected: map[string]string{"limits.cpu": "2", "user.foo": "bar"},
+		},
+		{
+			name:     "changed and added keys",
+			current:  map[string]string{"limits.cpu": "2", "user.foo": "bar"},
+			target:   map[string]string{"limits.cpu": "4", "limits.memory": "1GiB"},
+			expected: map[string]string{"limits.cpu": "4", "limits.memory": "1GiB", "user.foo": "bar"},
+			changes:  2,
+		},
+		{
+			name:     "auto matches existing values",
+			current:  map[string]string{"ipv4.address": "10.0.0.1/24"},
+			target:   map[string]string{"ipv4.address": "auto", "ipv6.address": "auto"},
+			expected: map[string]string{"ipv4.address": "10.0.0.1/24", "ipv6.address": "auto"},
+			changes:  1,
+		},
+		{
+			name:     "nil current config",
+			target:   map[string]string{"user.foo": "bar"},
+			expected: map[string]string{"user.foo": "bar"},
+			changes:  1,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			current := test.current
+			details := applyConfig(&current, test.target)
+			s.Len(details, test.changes)
+			s.Equal(test.expected, current)
+		})
+	}
+}
+
+func (s *applyTestSuite) TestApplyDevices() {
+	current := map[string]map[string]string{
+		"root": {"type": "disk", "path": "/", "pool": "default"},
+		"eth0": {"type": "nic", "network": "incusbr0"},
+	}
+
+	// Devices that aren't in the manifest are left untouched.
+	s.Empty(applyDevices(&current, nil))
+	s.Len(current, 2)
+
+	// Identical devices don't cause a change.
+	same := map[string]map[string]string{
+		"root": {"type": "disk", "path": "/", "pool": "default"},
+		"eth0": {"type": "nic", "network": "incusbr0"},
+	}
+
+	s.Empty(applyDevices(&current, same))
+
+	// Devices are replaced as a whole.
+	target := map[string]map[string]string{
+		"root": {"type": "disk", "path": "/", "pool": "fast"},
+	}
+
+	s.Equal([]string{"devices: eth0, root"}, applyDevices(&current, target))
+	s.Equal(target, current)
+}
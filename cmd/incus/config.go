@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -63,6 +64,10 @@ func (c *cmdConfig) Command() *cobra.Command {
 	configShowCmd := cmdConfigShow{global: c.global, config: c}
 	cmd.AddCommand(configShowCmd.Command())
 
+	// SSH key
+	configSSHKeyCmd := cmdConfigSSHKey{global: c.global, config: c}
+	cmd.AddCommand(configSSHKeyCmd.Command())
+
 	// Template
 	configTemplateCmd := cmdConfigTemplate{global: c.global, config: c}
 	cmd.AddCommand(configTemplateCmd.Command())
@@ -741,6 +746,7 @@ type cmdConfigShow struct {
 	config *cmdConfig
 
 	flagExpanded bool
+	flagOrigin   bool
 }
 
 // Command sets up the "show" command, which displays instance or server configurations based on the provided arguments.
@@ -752,6 +758,7 @@ func (c *cmdConfigShow) Command() *cobra.Command {
 		`Show instance or server configurations`))
 
 	cmd.Flags().BoolVarP(&c.flagExpanded, "expanded", "e", false, i18n.G("Show the expanded configuration"))
+	cmd.Flags().BoolVar(&c.flagOrigin, "origin", false, i18n.G("Show where each key and device of the expanded configuration comes from"))
 	cmd.Flags().StringVar(&c.config.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
 	cmd.RunE = c.Run
 
@@ -796,6 +803,10 @@ func (c *cmdConfigShow) Run(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf(i18n.G("--expanded cannot be used with a server"))
 		}
 
+		if c.flagOrigin {
+			return fmt.Errorf(i18n.G("--origin cannot be used with a server"))
+		}
+
 		// Targeting
 		if c.config.flagTarget != "" {
 			if !resource.server.IsClustered() {
@@ -822,6 +833,26 @@ func (c *cmdConfigShow) Run(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf(i18n.G("--target cannot be used with instances"))
 		}
 
+		// Instance config along with its origin
+		if c.flagOrigin {
+			if instance.IsSnapshot(resource.name) {
+				return fmt.Errorf(i18n.G("--origin cannot be used with snapshots"))
+			}
+
+			inst, _, err := resource.server.GetInstanceWithOrigin(resource.name)
+			if err != nil {
+				return err
+			}
+
+			data, err := renderConfigOrigin(inst)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%s", data)
+			return nil
+		}
+
 		// Instance or snapshot config
 		var brief any
 
@@ -866,6 +897,103 @@ func (c *cmdConfigShow) Run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// renderConfigOrigin renders the expanded configuration of an instance as YAML, with a comment above each
+// configuration key and device telling where it comes from.
+func renderConfigOrigin(inst *api.Instance) ([]byte, error) {
+	writable := inst.Writable()
+	writable.Config = nil
+	writable.Devices = nil
+
+	data, err := yaml.Marshal(&writable)
+	if err != nil {
+		return nil, err
+	}
+
+	out := string(data)
+
+	config := map[string]any{}
+	for k, v := range inst.ExpandedConfig {
+		config[k] = v
+	}
+
+	section, err := renderConfigOriginSection(config, inst.ExpandedConfigOrigin, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if section != "" {
+		out = strings.Replace(out, "config: {}\n", "config:\n"+section, 1)
+	}
+
+	devices := map[string]any{}
+	for k, v := range inst.ExpandedDevices {
+		devices[k] = v
+	}
+
+	section, err = renderConfigOriginSection(devices, inst.ExpandedDevicesOrigin, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if section != "" {
+		out = strings.Replace(out, "devices: {}\n", "devices:\n"+section, 1)
+	}
+
+	return []byte(out), nil
+}
+
+// renderConfigOriginSection renders the given configuration keys or devices as indented YAML, each preceded
+// by a comment telling where it comes from.
+func renderConfigOriginSection(values map[string]any, origins map[string]api.InstanceConfigOrigin, isDevice bool) (string, error) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		data, err := yaml.Marshal(map[string]any{k: values[k]})
+		if err != nil {
+			return "", err
+		}
+
+		origin, ok := origins[k]
+		if ok {
+			sb.WriteString("  # " + configOriginDescription(origin, isDevice) + "\n")
+		}
+
+		for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+			sb.WriteString("  " + line + "\n")
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// configOriginDescription describes where a configuration key or device comes from, along with the values it overrides.
+func configOriginDescription(origin api.InstanceConfigOrigin, isDevice bool) string {
+	describe := func(source string, profile string) string {
+		if source == "profile" {
+			return fmt.Sprintf(i18n.G("profile %q"), profile)
+		}
+
+		return i18n.G("instance")
+	}
+
+	description := fmt.Sprintf(i18n.G("From %s"), describe(origin.Source, origin.Profile))
+	for _, override := range origin.Overridden {
+		if isDevice {
+			description += fmt.Sprintf(i18n.G(", overriding the device from %s"), describe(override.Source, override.Profile))
+		} else {
+			description += fmt.Sprintf(i18n.G(", overriding %q from %s"), override.Value, describe(override.Source, override.Profile))
+		}
+	}
+
+	return description
+}
+
 // Unset.
 type cmdConfigUnset struct {
 	global    *cmdGlobal
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+type cmdConfigSSHKey struct {
+	global *cmdGlobal
+	config *cmdConfig
+}
+
+func (c *cmdConfigSSHKey) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("ssh-key")
+	cmd.Short = i18n.G("Manage guest users' SSH keys")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage guest users' SSH keys
+
+The keys are applied by the agent in virtual machines and directly in the root filesystem of containers.
+Missing users are created when adding keys to a running instance.`))
+
+	// Add
+	configSSHKeyAddCmd := cmdConfigSSHKeyAdd{global: c.global, config: c.config, configSSHKey: c}
+	cmd.AddCommand(configSSHKeyAddCmd.Command())
+
+	// List
+	configSSHKeyListCmd := cmdConfigSSHKeyList{global: c.global, config: c.config, configSSHKey: c}
+	cmd.AddCommand(configSSHKeyListCmd.Command())
+
+	// Remove
+	configSSHKeyRemoveCmd := cmdConfigSSHKeyRemove{global: c.global, config: c.config, configSSHKey: c}
+	cmd.AddCommand(configSSHKeyRemoveCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// sshKeyFingerprint returns the SHA256 fingerprint of an authorized key entry.
+func sshKeyFingerprint(key string) string {
+	pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+	if err != nil {
+		return ""
+	}
+
+	return ssh.FingerprintSHA256(pubKey)
+}
+
+// Add.
+type cmdConfigSSHKeyAdd struct {
+	global       *cmdGlobal
+	config       *cmdConfig
+	configSSHKey *cmdConfigSSHKey
+}
+
+func (c *cmdConfigSSHKeyAdd) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("add", i18n.G("[<remote>:]<instance> <user> <key>"))
+	cmd.Short = i18n.G("Add an authorized SSH key for a guest user")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Add an authorized SSH key for a guest user
+
+The key can either be provided directly or as the path to a public key file.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus config ssh-key add v1 ubuntu ~/.ssh/id_ed25519.pub
+    Authorize the local ed25519 key to log in as "ubuntu" in instance v1`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		if len(args) == 2 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdConfigSSHKeyAdd) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 3, 3)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing instance name"))
+	}
+
+	// Read the key from a file if provided.
+	key := args[2]
+	if util.PathExists(key) {
+		content, err := os.ReadFile(key)
+		if err != nil {
+			return err
+		}
+
+		key = string(content)
+	}
+
+	key = strings.TrimSpace(key)
+
+	keys, etag, err := resource.server.GetInstanceSSHKeys(resource.name, args[1])
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	req := api.InstanceSSHKeysPut{}
+	if keys != nil {
+		req = keys.Writable()
+	}
+
+	for _, existing := range req.Keys {
+		if existing == key {
+			return nil
+		}
+	}
+
+	req.Keys = append(req.Keys, key)
+
+	return resource.server.UpdateInstanceSSHKeys(resource.name, args[1], req, etag)
+}
+
+// List.
+type cmdConfigSSHKeyList struct {
+	global       *cmdGlobal
+	config       *cmdConfig
+	configSSHKey *cmdConfigSSHKey
+
+	flagFormat string
+}
+
+func (c *cmdConfigSSHKeyList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]<instance> <user>"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List the authorized SSH keys of a guest user")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List the authorized SSH keys of a guest user`))
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G("Format (csv|json|table|yaml|compact)")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdConfigSSHKeyList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing instance name"))
+	}
+
+	// List the keys
+	keys, _, err := resource.server.GetInstanceSSHKeys(resource.name, args[1])
+	if err != nil {
+		return err
+	}
+
+	// Render the table
+	data := [][]string{}
+	for _, key := range keys.Keys {
+		fields := strings.Fields(key)
+		keyType := fields[0]
+		comment := ""
+		if len(fields) > 2 {
+			comment = strings.Join(fields[2:], " ")
+		}
+
+		data = append(data, []string{keyType, sshKeyFingerprint(key), comment})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("TYPE"),
+		i18n.G("FINGERPRINT"),
+		i18n.G("COMMENT"),
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, keys.Keys)
+}
+
+// Remove.
+type cmdConfigSSHKeyRemove struct {
+	global       *cmdGlobal
+	config       *cmdConfig
+	configSSHKey *cmdConfigSSHKey
+}
+
+func (c *cmdConfigSSHKeyRemove) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("remove", i18n.G("[<remote>:]<instance> <user> <key>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Remove an authorized SSH key of a guest user")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Remove an authorized SSH key of a guest user
+
+The key can be provided directly, as the path to a public key file or as its fingerprint.`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		if len(args) == 2 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdConfigSSHKeyRemove) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 3, 3)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing instance name"))
+	}
+
+	// Read the key from a file if provided.
+	key := args[2]
+	if util.PathExists(key) {
+		content, err := os.ReadFile(key)
+		if err != nil {
+			return err
+		}
+
+		key = string(content)
+	}
+
+	key = strings.TrimSpace(key)
+
+	keys, etag, err := resource.server.GetInstanceSSHKeys(resource.name, args[1])
+	if err != nil {
+		return err
+	}
+
+	req := keys.Writable()
+	req.Keys = []string{}
+	for _, existing := range keys.Keys {
+		if existing == key || sshKeyFingerprint(existing) == key {
+			continue
+		}
+
+		req.Keys = append(req.Keys, existing)
+	}
+
+	if len(req.Keys) == len(keys.Keys) {
+		return fmt.Errorf(i18n.G("SSH key %q not found"), key)
+	}
+
+	return resource.server.UpdateInstanceSSHKeys(resource.name, args[1], req, etag)
+}
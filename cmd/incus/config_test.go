@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestRenderConfigOrigin(t *testing.T) {
+	inst := &api.Instance{
+		InstancePut: api.InstancePut{
+			Architecture: "x86_64",
+			Config:       map[string]string{"limits.cpu": "8"},
+			Profiles:     []string{"default", "big"},
+		},
+		ExpandedConfig: map[string]string{"limits.cpu": "8", "limits.memory": "1GiB"},
+		ExpandedDevices: map[string]map[string]string{
+			"root": {"type": "disk", "path": "/", "pool": "fast"},
+		},
+		ExpandedConfigOrigin: map[string]api.InstanceConfigOrigin{
+			"limits.cpu": {
+				Source: "instance",
+				Overridden: []api.InstanceConfigOverride{
+					{Source: "profile", Profile: "big", Value: "4"},
+				},
+			},
+			"limits.memory": {Source: "profile", Profile: "default"},
+		},
+		ExpandedDevicesOrigin: map[string]api.InstanceConfigOrigin{
+			"root": {
+				Source:  "profile",
+				Profile: "big",
+				Overridden: []api.InstanceConfigOverride{
+					{Source: "profile", Profile: "default", Device: map[string]string{"type": "disk", "path": "/", "pool": "default"}},
+				},
+			},
+		},
+	}
+
+	data, err := renderConfigOrigin(inst)
+	require.NoError(t, err)
+
+	assert.Equal(t, `architecture: x86_64
+config:
+  # From instance, overriding "4" from profile "big"
+  limits.cpu: "8"
+  # From profile "default"
+  limits.memory: 1GiB
+devices:
+  # From profile "big", overriding the device from profile "default"
+  root:
+    path: /
+    pool: fast
+    type: disk
+ephemeral: false
+profiles:
+- default
+- big
+stateful: false
+description: ""
+`, string(data))
+}
@@ -30,6 +30,8 @@ type cmdExec struct {
 	flagUser                uint32
 	flagGroup               uint32
 	flagCwd                 string
+	flagDetachable          bool
+	flagAttach              string
 
 	interactive bool
 }
@@ -48,12 +50,21 @@ executable, passing the shell commands as arguments, for example:
 
   incus exec <instance> -- sh -c "cd /tmp && pwd"
 
-Mode defaults to non-interactive, interactive mode is selected if both stdin AND stdout are terminals (stderr is ignored).`))
+Mode defaults to non-interactive, interactive mode is selected if both stdin AND stdout are terminals (stderr is ignored).
+
+Detachable commands keep running when the client disconnects. Their output is kept on the server
+and the session can be re-attached with --attach, which first shows the output produced so far.`))
 	cmd.Example = cli.FormatSection("", i18n.G(`incus exec c1 bash
 	Run the "bash" command in instance "c1"
 
 incus exec c1 -- ls -lh /
-	Run the "ls -lh /" command in instance "c1"`))
+	Run the "ls -lh /" command in instance "c1"
+
+incus exec c1 --detachable -- apt-get dist-upgrade -y
+	Run a command which keeps running if the connection is lost
+
+incus exec --attach 1f5a3a3e-5b4d-4a1b-9e0e-1ad2d1bc0b41
+	Re-attach to a detachable command`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().StringArrayVar(&c.flagEnvironment, "env", nil, i18n.G("Environment variable to set (e.g. HOME=/home/foo)")+"``")
@@ -64,6 +75,8 @@ incus exec c1 -- ls -lh /
 	cmd.Flags().Uint32Var(&c.flagUser, "user", 0, i18n.G("User ID to run the command as (default 0)")+"``")
 	cmd.Flags().Uint32Var(&c.flagGroup, "group", 0, i18n.G("Group ID to run the command as (default 0)")+"``")
 	cmd.Flags().StringVar(&c.flagCwd, "cwd", "", i18n.G("Directory to run the command in (default /root)")+"``")
+	cmd.Flags().BoolVar(&c.flagDetachable, "detachable", false, i18n.G("Keep the command running when disconnected"))
+	cmd.Flags().StringVar(&c.flagAttach, "attach", "", i18n.G("Re-attach to a detachable command using its operation ID")+"``")
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -97,6 +110,15 @@ func (c *cmdExec) Run(cmd *cobra.Command, args []string) error {
 	conf := c.global.conf
 
 	// Quick checks.
+	if c.flagAttach != "" {
+		exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+		if exit {
+			return err
+		}
+
+		return c.attach(args)
+	}
+
 	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
 	if exit {
 		return err
@@ -198,6 +220,7 @@ func (c *cmdExec) Run(cmd *cobra.Command, args []string) error {
 		User:        c.flagUser,
 		Group:       c.flagGroup,
 		Cwd:         c.flagCwd,
+		Detachable:  c.flagDetachable,
 	}
 
 	execArgs := incus.InstanceExecArgs{
@@ -214,8 +237,70 @@ func (c *cmdExec) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if c.flagDetachable && !c.global.flagQuiet {
+		fmt.Fprintf(os.Stderr, i18n.G("Re-attach with: incus exec --attach %s")+"\r\n", op.Get().ID)
+	}
+
+	return c.wait(op, &execArgs)
+}
+
+// attach re-attaches to a running detachable command.
+func (c *cmdExec) attach(args []string) error {
+	conf := c.global.conf
+
+	remote := conf.DefaultRemote
+	if len(args) > 0 {
+		var err error
+
+		remote, _, err = conf.ParseRemote(args[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	d, err := conf.GetInstanceServer(remote)
+	if err != nil {
+		return err
+	}
+
+	opAPI, _, err := d.GetOperation(c.flagAttach)
+	if err != nil {
+		return err
+	}
+
+	// Use the terminal mode of the command.
+	c.interactive, _ = opAPI.Metadata["interactive"].(bool)
+
+	stdinFd := getStdinFd()
+	if c.interactive && termios.IsTerminal(stdinFd) {
+		oldttystate, err := termios.MakeRaw(stdinFd)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = termios.Restore(stdinFd, oldttystate) }()
+	}
+
+	execArgs := incus.InstanceExecArgs{
+		Stdin:    os.Stdin,
+		Stdout:   getStdout(),
+		Stderr:   os.Stderr,
+		Control:  c.controlSocketHandler,
+		DataDone: make(chan bool),
+	}
+
+	op, err := d.AttachInstanceExec(c.flagAttach, &execArgs)
+	if err != nil {
+		return err
+	}
+
+	return c.wait(op, &execArgs)
+}
+
+// wait waits for the command to be done and records its exit code.
+func (c *cmdExec) wait(op incus.Operation, execArgs *incus.InstanceExecArgs) error {
 	// Wait for the operation to complete
-	err = op.Wait()
+	err := op.Wait()
 	opAPI := op.Get()
 	if opAPI.Metadata != nil {
 		exitStatusRaw, ok := opAPI.Metadata["return"].(float64)
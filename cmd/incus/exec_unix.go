@@ -63,6 +63,13 @@ func (c *cmdExec) controlSocketHandler(control *websocket.Conn) {
 			}
 
 		case unix.SIGHUP:
+			if c.flagDetachable || c.flagAttach != "" {
+				// Leave detachable commands running when the terminal goes away.
+				logger.Debugf("Received '%s signal', detaching from the command.", sig)
+				_ = control.WriteMessage(websocket.CloseMessage, closeMsg)
+				os.Exit(129)
+			}
+
 			file, err := os.OpenFile("/dev/tty", os.O_RDONLY|unix.O_NOCTTY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0666)
 			if err == nil {
 				_ = file.Close()
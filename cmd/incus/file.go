@@ -16,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/pkg/sftp"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh"
 
@@ -105,6 +106,10 @@ func (c *cmdFile) Command() *cobra.Command {
 	filePushCmd := cmdFilePush{global: c.global, file: c}
 	cmd.AddCommand(filePushCmd.Command())
 
+	// Sync
+	fileSyncCmd := cmdFileSync{global: c.global, file: c}
+	cmd.AddCommand(fileSyncCmd.Command())
+
 	// Edit
 	fileEditCmd := cmdFileEdit{global: c.global, file: c, filePull: &filePullCmd, filePush: &filePushCmd}
 	cmd.AddCommand(fileEditCmd.Command())
@@ -639,6 +644,7 @@ type cmdFilePush struct {
 
 	edit         bool
 	noModeChange bool
+	flagResume   bool
 }
 
 func (c *cmdFilePush) Command() *cobra.Command {
@@ -656,6 +662,7 @@ func (c *cmdFilePush) Command() *cobra.Command {
 	cmd.Flags().IntVar(&c.file.flagUID, "uid", -1, i18n.G("Set the file's uid on push")+"``")
 	cmd.Flags().IntVar(&c.file.flagGID, "gid", -1, i18n.G("Set the file's gid on push")+"``")
 	cmd.Flags().StringVar(&c.file.flagMode, "mode", "", i18n.G("Set the file's perms on push")+"``")
+	cmd.Flags().BoolVar(&c.flagResume, "resume", false, i18n.G("Resume interrupted transfers of files already partially present in the instance"))
 	cmd.RunE = c.Run
 
 	return cmd
@@ -723,6 +730,10 @@ func (c *cmdFilePush) Run(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf(i18n.G("Can't supply uid/gid/mode in recursive mode"))
 		}
 
+		if c.flagResume {
+			return fmt.Errorf(i18n.G("Can't resume transfers in recursive mode, use \"incus file sync\" instead"))
+		}
+
 		// Create needed paths if requested
 		if c.file.flagMkdir {
 			f, err := os.Open(sourcefilenames[0])
@@ -776,6 +787,10 @@ func (c *cmdFilePush) Run(cmd *cobra.Command, args []string) error {
 	for _, f := range sourcefilenames {
 		var file *os.File
 		if f == "-" {
+			if c.flagResume {
+				return fmt.Errorf(i18n.G("Can't resume transfers from standard input"))
+			}
+
 			file = os.Stdin
 		} else {
 			file, err = os.Open(f)
@@ -788,6 +803,17 @@ func (c *cmdFilePush) Run(cmd *cobra.Command, args []string) error {
 		files = append(files, file)
 	}
 
+	// Get a SFTP client to look for partially transferred files.
+	var sftpConn *sftp.Client
+	if c.flagResume {
+		sftpConn, err = resource.server.GetInstanceFileSFTP(resource.name)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = sftpConn.Close() }()
+	}
+
 	// Push the files
 	for _, f := range files {
 		fpath := targetPath
@@ -869,17 +895,37 @@ func (c *cmdFilePush) Run(cmd *cobra.Command, args []string) error {
 			Quiet:  c.global.flagQuiet,
 		}
 
+		// Only send what's missing from a partially transferred file, provided the content already
+		// present matches the start of the local file.
+		var content io.ReadSeeker = f
+		if sftpConn != nil {
+			targetStat, err := sftpConn.Lstat(fpath)
+			if err == nil && targetStat.Mode().IsRegular() && targetStat.Size() <= fstat.Size() {
+				samePrefix, err := filePushSamePrefix(sftpConn, fpath, f, targetStat.Size())
+				if err != nil {
+					return err
+				}
+
+				if samePrefix {
+					args.Offset = targetStat.Size()
+					content = io.NewSectionReader(f, args.Offset, fstat.Size()-args.Offset)
+				} else {
+					logger.Infof("Content of %s doesn't match %s, pushing the whole file", fpath, f.Name())
+				}
+			}
+		}
+
 		args.Content = internalIO.NewReadSeeker(&ioprogress.ProgressReader{
-			ReadCloser: f,
+			ReadCloser: io.NopCloser(content),
 			Tracker: &ioprogress.ProgressTracker{
-				Length: fstat.Size(),
+				Length: fstat.Size() - args.Offset,
 				Handler: func(percent int64, speed int64) {
 					progress.UpdateProgress(ioprogress.ProgressData{
 						Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2)),
 					})
 				},
 			},
-		}, f)
+		}, content)
 
 		logger.Infof("Pushing %s to %s (%s)", f.Name(), fpath, args.Type)
 		err = resource.server.CreateInstanceFile(resource.name, fpath, args)
@@ -894,6 +940,78 @@ func (c *cmdFilePush) Run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// filePushSamePrefix returns whether the remote file has the same content as the first size bytes of the local file.
+func filePushSamePrefix(sftpConn *sftp.Client, remotePath string, local io.ReaderAt, size int64) (bool, error) {
+	remote, err := sftpConn.Open(remotePath)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() { _ = remote.Close() }()
+
+	return fileSameContent(remote, local, size)
+}
+
+const (
+	// fileCompareChunks is the number of chunks compared when checking whether two files have the same content.
+	fileCompareChunks = 16
+
+	// fileCompareChunkSize is the size of the chunks compared when checking whether two files have the same content.
+	fileCompareChunkSize = 64 * 1024
+)
+
+// fileSameContent returns whether the first size bytes of the remote and local files match.
+// To bound how much is read from the instance, only fileCompareChunks chunks spread evenly between the start
+// and the end of larger files are compared.
+func fileSameContent(remote io.ReaderAt, local io.ReaderAt, size int64) (bool, error) {
+	var offsets []int64
+	if size <= fileCompareChunks*fileCompareChunkSize {
+		for offset := int64(0); offset < size; offset += fileCompareChunkSize {
+			offsets = append(offsets, offset)
+		}
+	} else {
+		step := (size - fileCompareChunkSize) / (fileCompareChunks - 1)
+		for i := int64(0); i < fileCompareChunks-1; i++ {
+			offsets = append(offsets, i*step)
+		}
+
+		// Always compare the end, where an interrupted transfer stopped.
+		offsets = append(offsets, size-fileCompareChunkSize)
+	}
+
+	remoteBuf := make([]byte, fileCompareChunkSize)
+	localBuf := make([]byte, fileCompareChunkSize)
+
+	for _, offset := range offsets {
+		length := min(fileCompareChunkSize, size-offset)
+
+		n, err := remote.ReadAt(remoteBuf[:length], offset)
+		if int64(n) < length {
+			if err == nil || err == io.EOF {
+				// The remote file is shorter.
+				return false, nil
+			}
+
+			return false, err
+		}
+
+		n, err = local.ReadAt(localBuf[:length], offset)
+		if int64(n) < length {
+			if err == nil {
+				err = io.ErrUnexpectedEOF
+			}
+
+			return false, err
+		}
+
+		if !bytes.Equal(remoteBuf[:length], localBuf[:length]) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func (c *cmdFile) recursivePullFile(d incus.InstanceServer, inst string, p string, targetDir string) error {
 	buf, resp, err := d.GetInstanceFile(inst, p)
 	if err != nil {
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/sftp"
+	"github.com/spf13/cobra"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	internalIO "github.com/lxc/incus/v6/internal/io"
+	"github.com/lxc/incus/v6/shared/ioprogress"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+// Sync.
+type cmdFileSync struct {
+	global *cmdGlobal
+	file   *cmdFile
+
+	flagChecksum bool
+	flagDelete   bool
+	flagDryRun   bool
+}
+
+func (c *cmdFileSync) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("sync", i18n.G("<source path> [<remote>:]<instance>/<path>"))
+	cmd.Short = i18n.G("Synchronize a directory into instances")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Synchronize a directory into instances
+
+The content of the source directory is recursively copied into the target directory of the instance,
+only transferring the files which differ (by type, size and modification time, or by content when requested).
+The content of large files is only compared at evenly spread chunks.
+Ownership, permissions and modification times are copied along with the content.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus file sync ./build foo/srv/app --delete
+   To make /srv/app in the instance "foo" an exact copy of the local build directory.`))
+
+	cmd.Flags().BoolVarP(&c.flagChecksum, "checksum", "c", false, i18n.G("Compare file content rather than size and modification time"))
+	cmd.Flags().BoolVar(&c.flagDelete, "delete", false, i18n.G("Delete files from the target which aren't in the source"))
+	cmd.Flags().BoolVarP(&c.flagDryRun, "dry-run", "n", false, i18n.G("Only show what would be changed"))
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdFileSync) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	source := filepath.Clean(args[0])
+	sourceInfo, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+
+	if !sourceInfo.IsDir() {
+		return fmt.Errorf(i18n.G("%s is not a directory"), source)
+	}
+
+	// Parse the destination
+	pathSpec := strings.SplitN(args[1], "/", 2)
+	if len(pathSpec) != 2 {
+		return fmt.Errorf(i18n.G("Invalid target %s"), args[1])
+	}
+
+	targetPath := path.Clean("/" + pathSpec[1])
+
+	// Parse remote
+	resources, err := c.global.ParseServers(pathSpec[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing instance name"))
+	}
+
+	sftpConn, err := resource.server.GetInstanceFileSFTP(resource.name)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = sftpConn.Close() }()
+
+	if !c.flagDryRun {
+		err = sftpConn.MkdirAll(targetPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Copy what differs.
+	seen := map[string]bool{targetPath: true}
+	transferred := 0
+
+	err = filepath.Walk(source, func(p string, fInfo os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed to walk path for %s: %s"), p, err)
+		}
+
+		rel, err := filepath.Rel(source, p)
+		if err != nil {
+			return err
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		remotePath := path.Join(targetPath, filepath.ToSlash(rel))
+		seen[remotePath] = true
+
+		remoteInfo, err := sftpConn.Lstat(remotePath)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+
+			remoteInfo = nil
+		}
+
+		changed, err := c.syncEntry(sftpConn, p, fInfo, remotePath, remoteInfo)
+		if err != nil {
+			return err
+		}
+
+		if changed {
+			transferred++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Remove what's gone from the source.
+	deleted := 0
+	if c.flagDelete {
+		toDelete := []string{}
+
+		walker := sftpConn.Walk(targetPath)
+		for walker.Step() {
+			err := walker.Err()
+			if err != nil {
+				// The target doesn't exist yet when doing a dry run.
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+
+				return err
+			}
+
+			if seen[walker.Path()] {
+				continue
+			}
+
+			toDelete = append(toDelete, walker.Path())
+			if walker.Stat().IsDir() {
+				walker.SkipDir()
+			}
+		}
+
+		sort.Strings(toDelete)
+
+		for _, p := range toDelete {
+			if c.flagDryRun {
+				fmt.Printf(i18n.G("Would delete %s")+"\n", p)
+			} else {
+				logger.Infof("Deleting %s", p)
+				err := sftpConn.RemoveAll(p)
+				if err != nil {
+					return err
+				}
+			}
+
+			deleted++
+		}
+	}
+
+	if !c.global.flagQuiet && !c.flagDryRun {
+		fmt.Printf(i18n.G("Synchronized %d entries, deleted %d")+"\n", transferred, deleted)
+	}
+
+	return nil
+}
+
+// syncEntry makes the remote path match the local one and returns whether anything needed changing.
+func (c *cmdFileSync) syncEntry(sftpConn *sftp.Client, localPath string, local os.FileInfo, remotePath string, remote os.FileInfo) (bool, error) {
+	mode, uid, gid := internalIO.GetOwnerMode(local)
+
+	// Replace entries of a different type.
+	if remote != nil && remote.Mode().Type() != local.Mode().Type() {
+		if c.flagDryRun {
+			fmt.Printf(i18n.G("Would replace %s")+"\n", remotePath)
+			return true, nil
+		}
+
+		err := sftpConn.RemoveAll(remotePath)
+		if err != nil {
+			return false, err
+		}
+
+		remote = nil
+	}
+
+	switch {
+	case local.IsDir():
+		if remote == nil {
+			if c.flagDryRun {
+				fmt.Printf(i18n.G("Would create %s")+"\n", remotePath)
+				return true, nil
+			}
+
+			logger.Infof("Creating %s (directory)", remotePath)
+			err := sftpConn.Mkdir(remotePath)
+			if err != nil {
+				return false, err
+			}
+
+			err = sftpConn.Chown(remotePath, uid, gid)
+			if err != nil {
+				return false, err
+			}
+		} else if remote.Mode().Perm() == mode.Perm() {
+			return false, nil
+		}
+
+		if c.flagDryRun {
+			fmt.Printf(i18n.G("Would update %s")+"\n", remotePath)
+			return true, nil
+		}
+
+		return true, sftpConn.Chmod(remotePath, mode.Perm())
+
+	case local.Mode()&os.ModeSymlink == os.ModeSymlink:
+		target, err := os.Readlink(localPath)
+		if err != nil {
+			return false, err
+		}
+
+		if remote != nil {
+			remoteTarget, err := sftpConn.ReadLink(remotePath)
+			if err == nil && remoteTarget == target {
+				return false, nil
+			}
+		}
+
+		if c.flagDryRun {
+			fmt.Printf(i18n.G("Would link %s to %s")+"\n", remotePath, target)
+			return true, nil
+		}
+
+		if remote != nil {
+			err = sftpConn.Remove(remotePath)
+			if err != nil {
+				return false, err
+			}
+		}
+
+		logger.Infof("Linking %s to %s", remotePath, target)
+		return true, sftpConn.Symlink(target, remotePath)
+
+	case local.Mode().IsRegular():
+		changed := fileSyncChanged(local, remote, c.flagChecksum)
+		if !changed && c.flagChecksum {
+			var err error
+
+			changed, err = fileSyncContentChanged(sftpConn, localPath, remotePath, local.Size())
+			if err != nil {
+				return false, err
+			}
+		}
+
+		if !changed {
+			if remote.Mode().Perm() == mode.Perm() {
+				return false, nil
+			}
+
+			if c.flagDryRun {
+				fmt.Printf(i18n.G("Would update %s")+"\n", remotePath)
+				return true, nil
+			}
+
+			return true, sftpConn.Chmod(remotePath, mode.Perm())
+		}
+
+		if c.flagDryRun {
+			fmt.Printf(i18n.G("Would transfer %s")+"\n", remotePath)
+			return true, nil
+		}
+
+		return true, c.syncFile(sftpConn, localPath, local, remotePath)
+	}
+
+	return false, fmt.Errorf(i18n.G("'%s' isn't a supported file type"), localPath)
+}
+
+// syncFile transfers the content, ownership, permissions and modification time of a local file.
+func (c *cmdFileSync) syncFile(sftpConn *sftp.Client, localPath string, local os.FileInfo, remotePath string) error {
+	mode, uid, gid := internalIO.GetOwnerMode(local)
+
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	target, err := sftpConn.OpenFile(remotePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = target.Close() }()
+
+	progress := cli.ProgressRenderer{
+		Format: fmt.Sprintf(i18n.G("Pushing %s to %s: %%s"), localPath, remotePath),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	reader := &ioprogress.ProgressReader{
+		ReadCloser: f,
+		Tracker: &ioprogress.ProgressTracker{
+			Length: local.Size(),
+			Handler: func(percent int64, speed int64) {
+				progress.UpdateProgress(ioprogress.ProgressData{
+					Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2)),
+				})
+			},
+		},
+	}
+
+	logger.Infof("Pushing %s to %s (file)", localPath, remotePath)
+	_, err = io.Copy(target, reader)
+	progress.Done("")
+	if err != nil {
+		return err
+	}
+
+	err = target.Close()
+	if err != nil {
+		return err
+	}
+
+	err = sftpConn.Chmod(remotePath, mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	err = sftpConn.Chown(remotePath, uid, gid)
+	if err != nil {
+		return err
+	}
+
+	// Keep the modification time so the file is considered unchanged by the next sync.
+	return sftpConn.Chtimes(remotePath, local.ModTime(), local.ModTime())
+}
+
+// fileSyncChanged returns whether a remote file differs from the local one based on its type, size
+// and (unless comparing checksums) modification time.
+func fileSyncChanged(local os.FileInfo, remote os.FileInfo, checksum bool) bool {
+	if remote == nil || !remote.Mode().IsRegular() {
+		return true
+	}
+
+	if remote.Size() != local.Size() {
+		return true
+	}
+
+	// SFTP only carries the modification time with a second precision.
+	return !checksum && remote.ModTime().Unix() != local.ModTime().Unix()
+}
+
+// fileSyncContentChanged compares the content of a local and a remote file of the given size.
+func fileSyncContentChanged(sftpConn *sftp.Client, localPath string, remotePath string, size int64) (bool, error) {
+	localFile, err := os.Open(localPath)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() { _ = localFile.Close() }()
+
+	remoteFile, err := sftpConn.Open(remotePath)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() { _ = remoteFile.Close() }()
+
+	same, err := fileSameContent(remoteFile, localFile, size)
+	if err != nil {
+		return false, err
+	}
+
+	return !same, nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type fileSyncTestSuite struct {
+	suite.Suite
+}
+
+func TestFileSyncTestSuite(t *testing.T) {
+	suite.Run(t, new(fileSyncTestSuite))
+}
+
+type fakeFileInfo struct {
+	mode    fs.FileMode
+	size    int64
+	modTime time.Time
+}
+
+func (f fakeFileInfo) Name() string       { return "file" }
+func (f fakeFileInfo) Size() int64        { return f.size }
+func (f fakeFileInfo) Mode() fs.FileMode  { return f.mode }
+func (f fakeFileInfo) ModTime() time.Time { return f.modTime }
+func (f fakeFileInfo) IsDir() bool        { return f.mode.IsDir() }
+func (f fakeFileInfo) Sys() any           { return nil }
+
+func (s *fileSyncTestSuite) TestFileSyncChanged() {
+	now := time.Now()
+	local := fakeFileInfo{mode: 0o644, size: 10, modTime: now}
+
+	tests := []struct {
+		name     string
+		remote   os.FileInfo
+		checksum bool
+		changed  bool
+	}{
+		{"missing", nil, false, true},
+		{"directory", fakeFileInfo{mode: fs.ModeDir | 0o755, modTime: now}, false, true},
+		{"identical", fakeFileInfo{mode: 0o600, size: 10, modTime: now.Truncate(time.Second)}, false, false},
+		{"size", fakeFileInfo{mode: 0o644, size: 11, modTime: now}, false, true},
+		{"mtime", fakeFileInfo{mode: 0o644, size: 10, modTime: now.Add(-time.Hour)}, false, true},
+		{"mtime with checksum", fakeFileInfo{mode: 0o644, size: 10, modTime: now.Add(-time.Hour)}, true, false},
+		{"size with checksum", fakeFileInfo{mode: 0o644, size: 11, modTime: now}, true, true},
+	}
+
+	for _, test := range tests {
+		s.Equal(test.changed, fileSyncChanged(local, test.remote, test.checksum), test.name)
+	}
+}
+
+func (s *fileSyncTestSuite) TestFileSameContent() {
+	small := bytes.Repeat([]byte("a"), 1000)
+	large := make([]byte, 4*fileCompareChunks*fileCompareChunkSize)
+	for i := range large {
+		large[i] = byte(i)
+	}
+
+	changed := func(content []byte, offset int) []byte {
+		content = bytes.Clone(content)
+		content[offset]++
+		return content
+	}
+
+	tests := []struct {
+		name   string
+		remote []byte
+		local  []byte
+		size   int64
+		same   bool
+	}{
+		{"small identical", small, small, int64(len(small)), true},
+		{"small changed", changed(small, 500), small, int64(len(small)), false},
+		{"small prefix", small[:100], small, 100, true},
+		{"remote shorter", small[:100], small, 200, false},
+		{"large identical", large, large, int64(len(large)), true},
+		{"large changed start", changed(large, 0), large, int64(len(large)), false},
+		{"large changed end", changed(large, len(large)-1), large, int64(len(large)), false},
+		{"large prefix", large[:len(large)/2+1], large, int64(len(large)/2 + 1), true},
+		{"large prefix changed end", changed(large[:len(large)/2+1], len(large)/2), large, int64(len(large)/2 + 1), false},
+	}
+
+	for _, test := range tests {
+		same, err := fileSameContent(bytes.NewReader(test.remote), bytes.NewReader(test.local), test.size)
+		s.NoError(err, test.name)
+		s.Equal(test.same, same, test.name)
+	}
+}
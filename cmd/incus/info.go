@@ -634,6 +634,10 @@ func (c *cmdInfo) instanceInfo(d incus.InstanceServer, remote config.Remote, nam
 
 	fmt.Printf(i18n.G("Status: %s")+"\n", strings.ToUpper(inst.Status))
 
+	if inst.State.Health != "" {
+		fmt.Printf(i18n.G("Health: %s")+"\n", strings.ToUpper(inst.State.Health))
+	}
+
 	instType := inst.Type
 	if instType == "" {
 		instType = "container"
@@ -121,6 +121,10 @@ Custom commands can be defined through aliases, use "incus alias" to control tho
 	adminCmd := cmdAdmin{global: &globalCmd}
 	app.AddCommand(adminCmd.Command())
 
+	// apply sub-command
+	applyCmd := cmdApply{global: &globalCmd}
+	app.AddCommand(applyCmd.Command())
+
 	// cluster sub-command
 	clusterCmd := cmdCluster{global: &globalCmd}
 	app.AddCommand(clusterCmd.Command())
@@ -205,6 +209,10 @@ Custom commands can be defined through aliases, use "incus alias" to control tho
 	publishCmd := cmdPublish{global: &globalCmd}
 	app.AddCommand(publishCmd.Command())
 
+	// port-forward sub-command
+	portForwardCmd := cmdPortForward{global: &globalCmd}
+	app.AddCommand(portForwardCmd.Command())
+
 	// profile sub-command
 	profileCmd := cmdProfile{global: &globalCmd}
 	app.AddCommand(profileCmd.Command())
@@ -261,10 +269,18 @@ Custom commands can be defined through aliases, use "incus alias" to control tho
 	topCmd := cmdTop{global: &globalCmd}
 	app.AddCommand(topCmd.Command())
 
+	// wait sub-command
+	waitCmd := cmdWait{global: &globalCmd}
+	app.AddCommand(waitCmd.Command())
+
 	// warning sub-command
 	warningCmd := cmdWarning{global: &globalCmd}
 	app.AddCommand(warningCmd.Command())
 
+	// webhook sub-command
+	webhookCmd := cmdWebhook{global: &globalCmd}
+	app.AddCommand(webhookCmd.Command())
+
 	// Get help command
 	app.InitDefaultHelpCmd()
 	var help *cobra.Command
@@ -24,6 +24,7 @@ type cmdMonitor struct {
 	flagLogLevel    string
 	flagAllProjects bool
 	flagFormat      string
+	flagSince       int64
 }
 
 func (c *cmdMonitor) Command() *cobra.Command {
@@ -42,7 +43,10 @@ incus monitor --pretty --type=logging --loglevel=info
     Show a pretty log of messages with info level or higher.
 
 incus monitor --type=lifecycle
-    Only show lifecycle events.`))
+    Only show lifecycle events.
+
+incus monitor --type=lifecycle --since=1234
+    Show the recorded lifecycle events following event 1234, then the new ones.`))
 	cmd.Hidden = true
 
 	cmd.RunE = c.Run
@@ -51,6 +55,7 @@ incus monitor --type=lifecycle
 	cmd.Flags().StringArrayVar(&c.flagType, "type", nil, i18n.G("Event type to listen for")+"``")
 	cmd.Flags().StringVar(&c.flagLogLevel, "loglevel", "", i18n.G("Minimum level for log messages (only available when using pretty format)")+"``")
 	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "yaml", i18n.G("Format (json|pretty|yaml)")+"``")
+	cmd.Flags().Int64Var(&c.flagSince, "since", -1, i18n.G("Replay the recorded events following the event with this ID")+"``")
 
 	return cmd
 }
@@ -99,7 +104,13 @@ func (c *cmdMonitor) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	var listener *incus.EventListener
-	if c.flagAllProjects {
+	if c.flagSince >= 0 {
+		if c.flagAllProjects {
+			listener, err = d.GetEventsAllProjectsSince(c.flagSince)
+		} else {
+			listener, err = d.GetEventsSince(c.flagSince)
+		}
+	} else if c.flagAllProjects {
 		listener, err = d.GetEventsAllProjects()
 	} else {
 		listener, err = d.GetEvents()
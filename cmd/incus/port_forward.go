@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	incus "github.com/lxc/incus/v6/client"
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/ws"
+)
+
+type cmdPortForward struct {
+	global *cmdGlobal
+
+	flagAddress string
+}
+
+func (c *cmdPortForward) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("port-forward", i18n.G("[<remote>:]<instance> [<local port>:]<instance port>..."))
+	cmd.Short = i18n.G("Forward local ports to an instance")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Forward local ports to an instance
+
+Connections to the local ports are tunneled through the API to TCP ports inside the instance.
+This works without any network device or configuration change on the instance.
+
+The forwarding runs until interrupted.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus port-forward c1 8080:80
+    Make port 80 of instance c1 reachable on local port 8080
+
+incus port-forward v1 5432 --address=0.0.0.0
+    Make port 5432 of instance v1 reachable on port 5432 of all local addresses`))
+
+	cmd.Flags().StringVar(&c.flagAddress, "address", "127.0.0.1", i18n.G("Local address to listen on")+"``")
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdPortForward) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing instance name"))
+	}
+
+	// Check the instance exists before listening.
+	_, _, err = resource.server.GetInstance(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// Set up all the listeners first so that a failure doesn't leave some ports forwarded.
+	listeners := []net.Listener{}
+	defer func() {
+		for _, listener := range listeners {
+			_ = listener.Close()
+		}
+	}()
+
+	targetPorts := []int{}
+	for _, arg := range args[1:] {
+		localPort, targetPort, err := portForwardParse(arg)
+		if err != nil {
+			return err
+		}
+
+		listener, err := net.Listen("tcp", net.JoinHostPort(c.flagAddress, strconv.Itoa(localPort)))
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed to listen for connection: %w"), err)
+		}
+
+		listeners = append(listeners, listener)
+		targetPorts = append(targetPorts, targetPort)
+	}
+
+	chErr := make(chan error, len(listeners))
+	for i, listener := range listeners {
+		if !c.global.flagQuiet {
+			fmt.Printf(i18n.G("Forwarding %s to port %d of instance %s")+"\n", listener.Addr(), targetPorts[i], resource.name)
+		}
+
+		go func(listener net.Listener, port int) {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					chErr <- fmt.Errorf(i18n.G("Failed to accept incoming connection: %w"), err)
+					return
+				}
+
+				go c.forward(resource.server, resource.name, port, conn)
+			}
+		}(listener, targetPorts[i])
+	}
+
+	return <-chErr
+}
+
+// forward relays a local connection to a port of the instance until either side is done.
+func (c *cmdPortForward) forward(server incus.InstanceServer, name string, port int, conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	wsConn, err := server.GetInstancePortForward(name, port)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.G("Failed forwarding connection from %s: %v")+"\n", conn.RemoteAddr(), err)
+		return
+	}
+
+	defer func() { _ = wsConn.Close() }()
+
+	chRead, chWrite := ws.Mirror(wsConn, conn)
+
+	select {
+	case <-chWrite:
+		// The instance closed the connection.
+	case err := <-chRead:
+		// Wait for the rest of the response if the local client only stopped sending.
+		if err == nil {
+			<-chWrite
+		}
+	}
+}
+
+// portForwardParse parses a "[<local port>:]<instance port>" argument.
+func portForwardParse(value string) (int, int, error) {
+	localValue, targetValue, found := strings.Cut(value, ":")
+	if !found {
+		targetValue = localValue
+	}
+
+	localPort, err := strconv.ParseUint(localValue, 10, 16)
+	if err != nil {
+		return -1, -1, fmt.Errorf(i18n.G("Invalid port specification %q"), value)
+	}
+
+	targetPort, err := strconv.ParseUint(targetValue, 10, 16)
+	if err != nil || targetPort == 0 {
+		return -1, -1, fmt.Errorf(i18n.G("Invalid port specification %q"), value)
+	}
+
+	return int(localPort), int(targetPort), nil
+}
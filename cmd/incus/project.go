@@ -8,6 +8,7 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -48,10 +49,18 @@ func (c *cmdProject) Command() *cobra.Command {
 	projectEditCmd := cmdProjectEdit{global: c.global, project: c}
 	cmd.AddCommand(projectEditCmd.Command())
 
+	// Export
+	projectExportCmd := cmdProjectExport{global: c.global, project: c}
+	cmd.AddCommand(projectExportCmd.Command())
+
 	// Get
 	projectGetCmd := cmdProjectGet{global: c.global, project: c}
 	cmd.AddCommand(projectGetCmd.Command())
 
+	// Import
+	projectImportCmd := cmdProjectImport{global: c.global, project: c}
+	cmd.AddCommand(projectImportCmd.Command())
+
 	// List
 	projectListCmd := cmdProjectList{global: c.global, project: c}
 	cmd.AddCommand(projectListCmd.Command())
@@ -76,6 +85,14 @@ func (c *cmdProject) Command() *cobra.Command {
 	projectGetInfo := cmdProjectInfo{global: c.global, project: c}
 	cmd.AddCommand(projectGetInfo.Command())
 
+	// Template
+	projectTemplateCmd := cmdProjectTemplate{global: c.global, project: c}
+	cmd.AddCommand(projectTemplateCmd.Command())
+
+	// Usage
+	projectUsageCmd := cmdProjectUsage{global: c.global, project: c}
+	cmd.AddCommand(projectUsageCmd.Command())
+
 	// Set default
 	projectSwitchCmd := cmdProjectSwitch{global: c.global, project: c}
 	cmd.AddCommand(projectSwitchCmd.Command())
@@ -88,9 +105,10 @@ func (c *cmdProject) Command() *cobra.Command {
 
 // Create.
 type cmdProjectCreate struct {
-	global     *cmdGlobal
-	project    *cmdProject
-	flagConfig []string
+	global       *cmdGlobal
+	project      *cmdProject
+	flagConfig   []string
+	flagTemplate string
 }
 
 func (c *cmdProjectCreate) Command() *cobra.Command {
@@ -103,9 +121,13 @@ func (c *cmdProjectCreate) Command() *cobra.Command {
     Create a project named p1
 
 incus project create p1 < config.yaml
-    Create a project named p1 with configuration from config.yaml`))
+    Create a project named p1 with configuration from config.yaml
+
+incus project create p1 --template tenant
+    Create a project named p1 from the "tenant" project template`))
 
 	cmd.Flags().StringArrayVarP(&c.flagConfig, "config", "c", nil, i18n.G("Config key/value to apply to the new project")+"``")
+	cmd.Flags().StringVarP(&c.flagTemplate, "template", "t", "", i18n.G("Project template to create the project from")+"``")
 
 	cmd.RunE = c.Run
 
@@ -158,6 +180,7 @@ func (c *cmdProjectCreate) Run(cmd *cobra.Command, args []string) error {
 	project := api.ProjectsPost{}
 	project.Name = resource.name
 	project.ProjectPut = stdinData
+	project.Template = c.flagTemplate
 
 	if project.Config == nil {
 		project.Config = map[string]string{}
@@ -1106,6 +1129,7 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 
 	// Render the output
 	byteLimits := []string{"disk", "memory"}
+	bitLimits := []string{"network-egress", "network-ingress"}
 	data := [][]string{}
 	for k, v := range projectState.Resources {
 		shortKey := strings.SplitN(k, ".", 2)[0]
@@ -1114,6 +1138,8 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 		if v.Limit >= 0 {
 			if slices.Contains(byteLimits, shortKey) {
 				limit = units.GetByteSizeStringIEC(v.Limit, 2)
+			} else if slices.Contains(bitLimits, shortKey) {
+				limit = units.GetBitSizeString(v.Limit, 2)
 			} else {
 				limit = fmt.Sprintf("%d", v.Limit)
 			}
@@ -1122,6 +1148,8 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 		usage := ""
 		if slices.Contains(byteLimits, shortKey) {
 			usage = units.GetByteSizeStringIEC(v.Usage, 2)
+		} else if slices.Contains(bitLimits, shortKey) {
+			usage = units.GetBitSizeString(v.Usage, 2)
 		} else {
 			usage = fmt.Sprintf("%d", v.Usage)
 		}
@@ -1145,3 +1173,180 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 
 	return cli.RenderTable(c.flagFormat, header, data, projectState)
 }
+
+// Usage.
+type cmdProjectUsage struct {
+	global  *cmdGlobal
+	project *cmdProject
+
+	flagFrom   string
+	flagTo     string
+	flagHourly bool
+	flagFormat string
+}
+
+func (c *cmdProjectUsage) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("usage", i18n.G("[<remote>:]<project>"))
+	cmd.Short = i18n.G("Show the resource usage history of a project")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show the resource usage history of a project
+
+By default, the usage of each instance is summed over the period.
+Memory and disk usage are shown in GiB-hours.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus project usage default
+    Show the usage of the "default" project since the start of the month.
+
+incus project usage default --from 2026-09-01 --to 2026-10-01 --hourly --format csv
+    Export the hourly usage records of September as CSV.`))
+	cmd.Flags().StringVar(&c.flagFrom, "from", "", i18n.G("Start of the period (YYYY-MM-DD or RFC3339)")+"``")
+	cmd.Flags().StringVar(&c.flagTo, "to", "", i18n.G("End of the period (YYYY-MM-DD or RFC3339)")+"``")
+	cmd.Flags().BoolVar(&c.flagHourly, "hourly", false, i18n.G("Show the hourly records instead of the totals"))
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G("Format (csv|json|table|yaml|compact)")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpProjects(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// parseTime parses a date or an RFC3339 timestamp.
+func (c *cmdProjectUsage) parseTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.DateOnly, value)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(i18n.G("Invalid time %q, expected YYYY-MM-DD or RFC3339"), value)
+	}
+
+	return t, nil
+}
+
+func (c *cmdProjectUsage) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing project name"))
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := now
+
+	if c.flagFrom != "" {
+		from, err = c.parseTime(c.flagFrom)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.flagTo != "" {
+		to, err = c.parseTime(c.flagTo)
+		if err != nil {
+			return err
+		}
+	}
+
+	records, err := resource.server.GetProjectUsage(resource.name, from, to)
+	if err != nil {
+		return err
+	}
+
+	gibHours := func(bytes int64) string {
+		return fmt.Sprintf("%.2f", float64(bytes)/float64(1024*1024*1024))
+	}
+
+	data := [][]string{}
+	if c.flagHourly {
+		for _, record := range records {
+			data = append(data, []string{
+				record.Instance,
+				record.Type,
+				record.Date.Local().Format(time.DateTime),
+				fmt.Sprintf("%.0f", record.CPUSeconds),
+				gibHours(record.MemoryBytes),
+				gibHours(record.DiskBytes),
+				units.GetByteSizeStringIEC(record.NetworkReceivedBytes, 2),
+				units.GetByteSizeStringIEC(record.NetworkSentBytes, 2),
+			})
+		}
+
+		header := []string{
+			i18n.G("INSTANCE"),
+			i18n.G("TYPE"),
+			i18n.G("DATE"),
+			i18n.G("CPU SECONDS"),
+			i18n.G("MEMORY (GiB-HOURS)"),
+			i18n.G("DISK (GiB-HOURS)"),
+			i18n.G("RECEIVED"),
+			i18n.G("SENT"),
+		}
+
+		return cli.RenderTable(c.flagFormat, header, data, records)
+	}
+
+	// Sum the hourly records of each instance.
+	totals := map[string]*api.ProjectUsageRecord{}
+	for _, record := range records {
+		total, ok := totals[record.Instance]
+		if !ok {
+			total = &api.ProjectUsageRecord{Instance: record.Instance, Type: record.Type}
+			totals[record.Instance] = total
+		}
+
+		total.CPUSeconds += record.CPUSeconds
+		total.MemoryBytes += record.MemoryBytes
+		total.DiskBytes += record.DiskBytes
+		total.NetworkReceivedBytes += record.NetworkReceivedBytes
+		total.NetworkSentBytes += record.NetworkSentBytes
+	}
+
+	for _, total := range totals {
+		data = append(data, []string{
+			total.Instance,
+			total.Type,
+			fmt.Sprintf("%.0f", total.CPUSeconds),
+			gibHours(total.MemoryBytes),
+			gibHours(total.DiskBytes),
+			units.GetByteSizeStringIEC(total.NetworkReceivedBytes, 2),
+			units.GetByteSizeStringIEC(total.NetworkSentBytes, 2),
+		})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("INSTANCE"),
+		i18n.G("TYPE"),
+		i18n.G("CPU SECONDS"),
+		i18n.G("MEMORY (GiB-HOURS)"),
+		i18n.G("DISK (GiB-HOURS)"),
+		i18n.G("RECEIVED"),
+		i18n.G("SENT"),
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, totals)
+}
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/lxc/incus/v6/client"
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/revert"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// projectArchiveIndex is the name of the file describing the project at the start of a project archive.
+const projectArchiveIndex = "project.yaml"
+
+// projectArchive describes the content of a project archive.
+// The instance and volume backups follow the index in the archive, volumes first.
+type projectArchive struct {
+	Project      api.Project                 `yaml:"project"`
+	Profiles     []api.Profile               `yaml:"profiles,omitempty"`
+	Networks     []api.Network               `yaml:"networks,omitempty"`
+	NetworkACLs  []api.NetworkACL            `yaml:"network_acls,omitempty"`
+	NetworkZones []projectArchiveNetworkZone `yaml:"network_zones,omitempty"`
+	Volumes      []projectArchiveVolume      `yaml:"volumes,omitempty"`
+	Instances    []projectArchiveInstance    `yaml:"instances,omitempty"`
+}
+
+// projectArchiveNetworkZone is a network zone of a project archive, along with its records.
+type projectArchiveNetworkZone struct {
+	Zone    api.NetworkZone         `yaml:"zone"`
+	Records []api.NetworkZoneRecord `yaml:"records,omitempty"`
+}
+
+// projectArchiveVolume is a custom storage volume of a project archive.
+type projectArchiveVolume struct {
+	Pool string `yaml:"pool"`
+	Name string `yaml:"name"`
+	File string `yaml:"file"`
+}
+
+// projectArchiveInstance is an instance of a project archive.
+type projectArchiveInstance struct {
+	Name string `yaml:"name"`
+	Pool string `yaml:"pool"`
+	File string `yaml:"file"`
+
+	// Pools of the custom volumes attached through the instance's own disk devices.
+	DiskPools []string `yaml:"disk_pools,omitempty"`
+}
+
+// projectHasFeature returns true if the project has its own set of the entities covered by the feature.
+func projectHasFeature(project api.Project, feature string) bool {
+	return project.Name == api.ProjectDefaultName || util.IsTrue(project.Config[feature])
+}
+
+type cmdProjectExport struct {
+	global  *cmdGlobal
+	project *cmdProject
+
+	flagInstanceOnly         bool
+	flagOptimizedStorage     bool
+	flagCompressionAlgorithm string
+}
+
+func (c *cmdProjectExport) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("export", i18n.G("[<remote>:]<project> <target>"))
+	cmd.Short = i18n.G("Export projects")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Export projects as a single archive
+
+The archive contains the project configuration, its profiles, networks, network ACLs
+and network zones, along with backups of its custom storage volumes and instances.
+
+Entities shared with the default project because the matching project feature is
+disabled aren't included.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus project export tenant1 tenant1.tar
+    Export the tenant1 project and all of its instances and volumes.`))
+
+	cmd.Flags().BoolVar(&c.flagInstanceOnly, "instance-only", false, i18n.G("Whether or not to only backup the instances and volumes (without snapshots)"))
+	cmd.Flags().BoolVar(&c.flagOptimizedStorage, "optimized-storage", false, i18n.G("Use storage driver optimized format (can only be restored on a similar pool)"))
+	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", i18n.G("Compression algorithm to use (none for uncompressed)")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpProjects(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	return cmd
+}
+
+func (c *cmdProjectExport) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote, name, err := conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		return fmt.Errorf(i18n.G("Missing project name"))
+	}
+
+	d, err := conf.GetInstanceServer(remote)
+	if err != nil {
+		return err
+	}
+
+	project, _, err := d.GetProject(name)
+	if err != nil {
+		return err
+	}
+
+	d = d.UseProject(name)
+
+	index, err := c.index(d, *project)
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	target, err := os.Create(args[1])
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = target.Close() }()
+	reverter.Add(func() { _ = os.Remove(args[1]) })
+
+	tw := tar.NewWriter(target)
+
+	data, err := yaml.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{Name: projectArchiveIndex, Mode: 0600, Size: int64(len(data)), ModTime: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	_, err = tw.Write(data)
+	if err != nil {
+		return err
+	}
+
+	for _, vol := range index.Volumes {
+		req := api.StoragePoolVolumeBackupsPost{
+			ExpiresAt:            time.Now().Add(24 * time.Hour),
+			VolumeOnly:           c.flagInstanceOnly,
+			OptimizedStorage:     c.flagOptimizedStorage,
+			CompressionAlgorithm: c.flagCompressionAlgorithm,
+		}
+
+		err = c.writeBackup(tw, vol.File, fmt.Sprintf(i18n.G("Backing up volume %s/%s: %%s"), vol.Pool, vol.Name),
+			func() (incus.Operation, error) {
+				return d.CreateStoragePoolVolumeBackup(vol.Pool, vol.Name, req)
+			},
+			func(backupName string, req *incus.BackupFileRequest) error {
+				_, err := d.GetStoragePoolVolumeBackupFile(vol.Pool, vol.Name, backupName, req)
+				return err
+			},
+			func(backupName string) (incus.Operation, error) {
+				return d.DeleteStoragePoolVolumeBackup(vol.Pool, vol.Name, backupName)
+			})
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed exporting volume %q in pool %q: %w"), vol.Name, vol.Pool, err)
+		}
+	}
+
+	for _, inst := range index.Instances {
+		req := api.InstanceBackupsPost{
+			ExpiresAt:            time.Now().Add(24 * time.Hour),
+			InstanceOnly:         c.flagInstanceOnly,
+			OptimizedStorage:     c.flagOptimizedStorage,
+			CompressionAlgorithm: c.flagCompressionAlgorithm,
+		}
+
+		err = c.writeBackup(tw, inst.File, fmt.Sprintf(i18n.G("Backing up instance %s: %%s"), inst.Name),
+			func() (incus.Operation, error) {
+				return d.CreateInstanceBackup(inst.Name, req)
+			},
+			func(backupName string, req *incus.BackupFileRequest) error {
+				_, err := d.GetInstanceBackupFile(inst.Name, backupName, req)
+				return err
+			},
+			func(backupName string) (incus.Operation, error) {
+				return d.DeleteInstanceBackup(inst.Name, backupName)
+			})
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed exporting instance %q: %w"), inst.Name, err)
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+
+	err = target.Close()
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed to close export file: %w"), err)
+	}
+
+	reverter.Success()
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Project %s exported successfully!")+"\n", name)
+	}
+
+	return nil
+}
+
+// index gathers the entities of the project to export.
+func (c *cmdProjectExport) index(d incus.InstanceServer, project api.Project) (*projectArchive, error) {
+	var err error
+
+	index := &projectArchive{Project: project}
+
+	if projectHasFeature(project, "features.profiles") {
+		index.Profiles, err = d.GetProfiles()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if projectHasFeature(project, "features.networks") {
+		networks, err := d.GetNetworks()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, network := range networks {
+			if !network.Managed {
+				continue
+			}
+
+			index.Networks = append(index.Networks, network)
+		}
+
+		index.NetworkACLs, err = d.GetNetworkACLs()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if projectHasFeature(project, "features.networks.zones") {
+		zones, err := d.GetNetworkZones()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, zone := range zones {
+			records, err := d.GetNetworkZoneRecords(zone.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			index.NetworkZones = append(index.NetworkZones, projectArchiveNetworkZone{Zone: zone, Records: records})
+		}
+	}
+
+	if projectHasFeature(project, "features.storage.volumes") {
+		pools, err := d.GetStoragePoolNames()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pool := range pools {
+			volumes, err := d.GetStoragePoolVolumes(pool)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, vol := range volumes {
+				if vol.Type != "custom" || strings.Contains(vol.Name, "/") {
+					continue
+				}
+
+				index.Volumes = append(index.Volumes, projectArchiveVolume{
+					Pool: pool,
+					Name: vol.Name,
+					File: path.Join("volumes", pool, vol.Name),
+				})
+			}
+		}
+	}
+
+	instances, err := d.GetInstances(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, inst := range instances {
+		_, rootDisk, _ := instance.GetRootDiskDevice(inst.ExpandedDevices)
+
+		var diskPools []string
+		for _, device := range inst.Devices {
+			if device["type"] == "disk" && device["pool"] != "" && device["path"] != "/" && !slices.Contains(diskPools, device["pool"]) {
+				diskPools = append(diskPools, device["pool"])
+			}
+		}
+
+		slices.Sort(diskPools)
+
+		index.Instances = append(index.Instances, projectArchiveInstance{
+			Name:      inst.Name,
+			Pool:      rootDisk["pool"],
+			File:      path.Join("instances", inst.Name),
+			DiskPools: diskPools,
+		})
+	}
+
+	return index, nil
+}
+
+// writeBackup creates a backup, downloads it to a temporary file and then adds it to the archive.
+func (c *cmdProjectExport) writeBackup(tw *tar.Writer, fileName string, format string, create func() (incus.Operation, error), download func(backupName string, req *incus.BackupFileRequest) error, remove func(backupName string) (incus.Operation, error)) error {
+	op, err := create()
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: format,
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	uStr := op.Get().Resources["backups"][0]
+	u, err := url.Parse(uStr)
+	if err != nil {
+		progress.Done("")
+		return fmt.Errorf(i18n.G("Invalid URL %q: %w"), uStr, err)
+	}
+
+	backupName, err := url.PathUnescape(path.Base(u.EscapedPath()))
+	if err != nil {
+		progress.Done("")
+		return fmt.Errorf(i18n.G("Invalid backup name segment in path %q: %w"), u.EscapedPath(), err)
+	}
+
+	defer func() {
+		op, err := remove(backupName)
+		if err == nil {
+			_ = op.Wait()
+		}
+	}()
+
+	tmpFile, err := os.CreateTemp("", "incus_project_export_")
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	defer func() {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	err = download(backupName, &incus.BackupFileRequest{
+		BackupFile:      io.WriteSeeker(tmpFile),
+		ProgressHandler: progress.UpdateProgress,
+	})
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	size, err := tmpFile.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	_, err = tmpFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{Name: fileName, Mode: 0600, Size: size, ModTime: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, tmpFile)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/lxc/incus/v6/client"
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/internal/revert"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/ioprogress"
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+type cmdProjectImport struct {
+	global  *cmdGlobal
+	project *cmdProject
+
+	flagStorage  []string
+	flagInstance []string
+}
+
+func (c *cmdProjectImport) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("import", i18n.G("[<remote>:] <archive> [<project>]"))
+	cmd.Short = i18n.G("Import projects")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Import projects from an archive created by "incus project export"
+
+The project is created under its original name unless a new one is given.
+Storage pools can be remapped with --storage, either all at once by giving
+a pool name, or one by one with <source pool>=<target pool>. Pools used by the
+disk devices of the instances themselves (rather than of their profiles) can't
+be remapped.
+
+Instances can be renamed with --instance <source name>=<target name>.
+
+If the import fails, the partially imported project is deleted.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus project import tenant1.tar
+    Create the tenant1 project and all of its instances and volumes from tenant1.tar.
+
+incus project import remote: tenant1.tar tenant2 --storage default=fast
+    Import the project as tenant2 on "remote", moving the content of the "default" pool to the "fast" pool.
+
+incus project import tenant1.tar --instance web=web-old
+    Import the project, renaming its "web" instance to "web-old".`))
+
+	cmd.Flags().StringArrayVarP(&c.flagStorage, "storage", "s", nil, i18n.G("Storage pool to use, or <source pool>=<target pool> mapping")+"``")
+	cmd.Flags().StringArrayVar(&c.flagInstance, "instance", nil, i18n.G("Instance renaming, as <source name>=<target name>")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdProjectImport) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 3)
+	if exit {
+		return err
+	}
+
+	srcFilePosition := 0
+
+	remote := ""
+	if len(args) > 1 && strings.HasSuffix(args[0], ":") {
+		remote = args[0]
+		srcFilePosition = 1
+	}
+
+	if len(args) <= srcFilePosition {
+		return fmt.Errorf(i18n.G("Missing archive file"))
+	}
+
+	srcFile := args[srcFilePosition]
+
+	projectName := ""
+	if len(args) >= srcFilePosition+2 {
+		projectName = args[srcFilePosition+1]
+	}
+
+	// Parse the storage pool mapping.
+	poolDefault := ""
+	poolMap := map[string]string{}
+	for _, entry := range c.flagStorage {
+		source, target, found := strings.Cut(entry, "=")
+		if !found {
+			poolDefault = entry
+			continue
+		}
+
+		if source == "" || target == "" {
+			return fmt.Errorf(i18n.G("Invalid storage pool mapping %q"), entry)
+		}
+
+		poolMap[source] = target
+	}
+
+	mapPool := func(pool string) string {
+		target, ok := poolMap[pool]
+		if ok {
+			return target
+		}
+
+		if poolDefault != "" {
+			return poolDefault
+		}
+
+		return pool
+	}
+
+	// Parse the instance renaming.
+	instanceMap := map[string]string{}
+	for _, entry := range c.flagInstance {
+		source, target, found := strings.Cut(entry, "=")
+		if !found || source == "" || target == "" {
+			return fmt.Errorf(i18n.G("Invalid instance renaming %q"), entry)
+		}
+
+		instanceMap[source] = target
+	}
+
+	mapInstance := func(name string) string {
+		target, ok := instanceMap[name]
+		if ok {
+			return target
+		}
+
+		return name
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	file, err := os.Open(srcFile)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	tr := tar.NewReader(file)
+
+	// Read the index.
+	hdr, err := tr.Next()
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed reading the archive: %w"), err)
+	}
+
+	if hdr.Name != projectArchiveIndex {
+		return fmt.Errorf(i18n.G("Invalid project archive, %q must be its first file"), projectArchiveIndex)
+	}
+
+	data, err := io.ReadAll(tr)
+	if err != nil {
+		return err
+	}
+
+	index := projectArchive{}
+	err = yaml.Unmarshal(data, &index)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed parsing %q: %w"), projectArchiveIndex, err)
+	}
+
+	if projectName == "" {
+		projectName = index.Project.Name
+	}
+
+	// The devices of the instances are part of their backups, so only the pools of their root disks can be changed.
+	for _, inst := range index.Instances {
+		for _, pool := range inst.DiskPools {
+			if mapPool(pool) != pool {
+				return fmt.Errorf(i18n.G("Instance %q has disk devices using storage pool %q, which can't be remapped"), inst.Name, pool)
+			}
+		}
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Create the project and its entities.
+	err = resource.server.CreateProject(api.ProjectsPost{Name: projectName, ProjectPut: index.Project.Writable()})
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed creating project %q: %w"), projectName, err)
+	}
+
+	reverter.Add(func() { _ = resource.server.DeleteProjectForce(projectName) })
+
+	d := resource.server.UseProject(projectName)
+
+	// Network ACLs are created without rules first, as rules may refer to other ACLs.
+	for _, acl := range index.NetworkACLs {
+		err = d.CreateNetworkACL(api.NetworkACLsPost{
+			NetworkACLPost: api.NetworkACLPost{Name: acl.Name},
+			NetworkACLPut:  api.NetworkACLPut{Description: acl.Description, Config: acl.Config},
+		})
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed creating network ACL %q: %w"), acl.Name, err)
+		}
+	}
+
+	for _, acl := range index.NetworkACLs {
+		err = d.UpdateNetworkACL(acl.Name, acl.Writable(), "")
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed updating network ACL %q: %w"), acl.Name, err)
+		}
+	}
+
+	for _, zone := range index.NetworkZones {
+		err = d.CreateNetworkZone(api.NetworkZonesPost{Name: zone.Zone.Name, NetworkZonePut: zone.Zone.Writable()})
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed creating network zone %q: %w"), zone.Zone.Name, err)
+		}
+
+		for _, record := range zone.Records {
+			err = d.CreateNetworkZoneRecord(zone.Zone.Name, api.NetworkZoneRecordsPost{Name: record.Name, NetworkZoneRecordPut: record.Writable()})
+			if err != nil {
+				return fmt.Errorf(i18n.G("Failed creating record %q of network zone %q: %w"), record.Name, zone.Zone.Name, err)
+			}
+		}
+	}
+
+	for _, network := range index.Networks {
+		put := network.Writable()
+		for k := range put.Config {
+			if strings.HasPrefix(k, "volatile.") {
+				delete(put.Config, k)
+			}
+		}
+
+		err = d.CreateNetwork(api.NetworksPost{Name: network.Name, Type: network.Type, NetworkPut: put})
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed creating network %q: %w"), network.Name, err)
+		}
+	}
+
+	for _, profile := range index.Profiles {
+		put := profile.Writable()
+		for _, device := range put.Devices {
+			if device["type"] == "disk" && device["pool"] != "" {
+				device["pool"] = mapPool(device["pool"])
+			}
+		}
+
+		if profile.Name == "default" {
+			err = d.UpdateProfile(profile.Name, put, "")
+		} else {
+			err = d.CreateProfile(api.ProfilesPost{Name: profile.Name, ProfilePut: put})
+		}
+
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed creating profile %q: %w"), profile.Name, err)
+		}
+	}
+
+	// Import the backups of the volumes and instances.
+	volumes := map[string]projectArchiveVolume{}
+	for _, vol := range index.Volumes {
+		volumes[vol.File] = vol
+	}
+
+	instances := map[string]projectArchiveInstance{}
+	for _, inst := range index.Instances {
+		instances[inst.File] = inst
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed reading the archive: %w"), err)
+		}
+
+		vol, isVolume := volumes[hdr.Name]
+		inst, isInstance := instances[hdr.Name]
+		if !isVolume && !isInstance {
+			continue
+		}
+
+		var format string
+		if isVolume {
+			format = fmt.Sprintf(i18n.G("Importing volume %s/%s: %%s"), mapPool(vol.Pool), vol.Name)
+		} else {
+			format = fmt.Sprintf(i18n.G("Importing instance %s: %%s"), mapInstance(inst.Name))
+		}
+
+		progress := cli.ProgressRenderer{
+			Format: format,
+			Quiet:  c.global.flagQuiet,
+		}
+
+		backupFile := &ioprogress.ProgressReader{
+			ReadCloser: io.NopCloser(tr),
+			Tracker: &ioprogress.ProgressTracker{
+				Length: hdr.Size,
+				Handler: func(percent int64, speed int64) {
+					progress.UpdateProgress(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
+				},
+			},
+		}
+
+		var op incus.Operation
+		if isVolume {
+			op, err = d.CreateStoragePoolVolumeFromBackup(mapPool(vol.Pool), incus.StoragePoolVolumeBackupArgs{BackupFile: backupFile, Name: vol.Name})
+			delete(volumes, hdr.Name)
+		} else {
+			pool := ""
+			if inst.Pool != mapPool(inst.Pool) {
+				pool = mapPool(inst.Pool)
+			}
+
+			op, err = d.CreateInstanceFromBackup(incus.InstanceBackupArgs{BackupFile: backupFile, PoolName: pool, Name: mapInstance(inst.Name)})
+			delete(instances, hdr.Name)
+		}
+
+		if err == nil {
+			err = cli.CancelableWait(op, &progress)
+		}
+
+		progress.Done("")
+
+		if err != nil {
+			if isVolume {
+				return fmt.Errorf(i18n.G("Failed importing volume %q in pool %q: %w"), vol.Name, mapPool(vol.Pool), err)
+			}
+
+			return fmt.Errorf(i18n.G("Failed importing instance %q: %w"), mapInstance(inst.Name), err)
+		}
+	}
+
+	if len(volumes) > 0 || len(instances) > 0 {
+		return fmt.Errorf(i18n.G("Invalid project archive, some backups are missing"))
+	}
+
+	reverter.Success()
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Project %s imported successfully!")+"\n", projectName)
+	}
+
+	return nil
+}
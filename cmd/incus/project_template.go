@@ -0,0 +1,490 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+type cmdProjectTemplate struct {
+	global  *cmdGlobal
+	project *cmdProject
+}
+
+func (c *cmdProjectTemplate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("template")
+	cmd.Short = i18n.G("Manage project templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage project templates
+
+Project templates hold the configuration, profiles and networks used to create new projects.`))
+
+	// Create
+	projectTemplateCreateCmd := cmdProjectTemplateCreate{global: c.global, projectTemplate: c}
+	cmd.AddCommand(projectTemplateCreateCmd.Command())
+
+	// Delete
+	projectTemplateDeleteCmd := cmdProjectTemplateDelete{global: c.global, projectTemplate: c}
+	cmd.AddCommand(projectTemplateDeleteCmd.Command())
+
+	// Edit
+	projectTemplateEditCmd := cmdProjectTemplateEdit{global: c.global, projectTemplate: c}
+	cmd.AddCommand(projectTemplateEditCmd.Command())
+
+	// List
+	projectTemplateListCmd := cmdProjectTemplateList{global: c.global, projectTemplate: c}
+	cmd.AddCommand(projectTemplateListCmd.Command())
+
+	// Show
+	projectTemplateShowCmd := cmdProjectTemplateShow{global: c.global, projectTemplate: c}
+	cmd.AddCommand(projectTemplateShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdProjectTemplateCreate struct {
+	global          *cmdGlobal
+	projectTemplate *cmdProjectTemplate
+
+	flagDescription string
+}
+
+func (c *cmdProjectTemplateCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<template>"))
+	cmd.Short = i18n.G("Create project templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create project templates`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus project template create tenant < tenant.yaml
+    Create a project template named tenant from the content of tenant.yaml`))
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Template description")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdProjectTemplateCreate) Run(cmd *cobra.Command, args []string) error {
+	var stdinData api.ProjectTemplatePut
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.Unmarshal(contents, &stdinData)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing project template name"))
+	}
+
+	// Create the project template
+	template := api.ProjectTemplatesPost{}
+	template.Name = resource.name
+	template.ProjectTemplatePut = stdinData
+
+	if c.flagDescription != "" {
+		template.Description = c.flagDescription
+	}
+
+	err = resource.server.CreateProjectTemplate(template)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Project template %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdProjectTemplateDelete struct {
+	global          *cmdGlobal
+	projectTemplate *cmdProjectTemplate
+}
+
+func (c *cmdProjectTemplateDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<template>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete project templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete project templates
+
+Projects previously created from the template aren't affected.`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpProjectTemplates(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdProjectTemplateDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing project template name"))
+	}
+
+	err = resource.server.DeleteProjectTemplate(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Project template %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdProjectTemplateEdit struct {
+	global          *cmdGlobal
+	projectTemplate *cmdProjectTemplate
+}
+
+func (c *cmdProjectTemplateEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<template>"))
+	cmd.Short = i18n.G("Edit project templates as YAML")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit project templates as YAML`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus project template edit <template> < template.yaml
+    Update a project template using the content of template.yaml`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpProjectTemplates(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdProjectTemplateEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the project template.
+### Any line starting with a '# will be ignored.
+###
+### A project template consists of the configuration of the projects
+### created from it, along with profiles and networks to create in them.
+###
+### An example would look like:
+### description: Default setup for tenant projects
+### config:
+###   features.networks: "true"
+###   limits.instances: "10"
+###   restricted: "true"
+### profiles:
+### - name: default
+###   devices:
+###     eth0:
+###       type: nic
+###       network: internal
+### networks:
+### - name: internal
+###   type: ovn
+###   config:
+###     network: UPLINK
+### name: tenant
+###
+### Note that the name is shown but cannot be changed`)
+}
+
+func (c *cmdProjectTemplateEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing project template name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.ProjectTemplatePut{}
+		err = yaml.Unmarshal(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateProjectTemplate(resource.name, newdata, "")
+	}
+
+	// Extract the current value
+	template, etag, err := resource.server.GetProjectTemplate(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&template)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.ProjectTemplatePut{}
+		err = yaml.Unmarshal(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateProjectTemplate(resource.name, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// List.
+type cmdProjectTemplateList struct {
+	global          *cmdGlobal
+	projectTemplate *cmdProjectTemplate
+
+	flagFormat string
+}
+
+func (c *cmdProjectTemplateList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List project templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List project templates`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G("Format (csv|json|table|yaml|compact)")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdProjectTemplateList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	templates, err := resource.server.GetProjectTemplates()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, template := range templates {
+		profiles := make([]string, 0, len(template.Profiles))
+		for _, profile := range template.Profiles {
+			profiles = append(profiles, profile.Name)
+		}
+
+		networks := make([]string, 0, len(template.Networks))
+		for _, network := range template.Networks {
+			networks = append(networks, network.Name)
+		}
+
+		data = append(data, []string{template.Name, template.Description, strings.Join(profiles, "\n"), strings.Join(networks, "\n")})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("PROFILES"),
+		i18n.G("NETWORKS"),
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, templates)
+}
+
+// Show.
+type cmdProjectTemplateShow struct {
+	global          *cmdGlobal
+	projectTemplate *cmdProjectTemplate
+}
+
+func (c *cmdProjectTemplateShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<template>"))
+	cmd.Short = i18n.G("Show project templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show project templates`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpProjectTemplates(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdProjectTemplateShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing project template name"))
+	}
+
+	template, _, err := resource.server.GetProjectTemplate(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&template)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+)
+
+type cmdWait struct {
+	global *cmdGlobal
+
+	flagFor     string
+	flagTimeout int
+}
+
+func (c *cmdWait) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("wait", i18n.G("[<remote>:]<instance>"))
+	cmd.Short = i18n.G("Wait for an instance to reach a condition")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Wait for an instance to reach a condition
+
+Supported conditions are:
+ - running: The instance is running
+ - stopped: The instance is stopped
+ - agent: The agent is running in the instance (always true for running containers)
+ - ip: The instance has a global IP address (the addresses get printed)
+ - ready: The instance reported itself as ready (and not unhealthy) through the guest API`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus wait v1 --for=ready --timeout=300
+    Wait up to 5 minutes for instance v1 to report itself as ready`))
+
+	cmd.Flags().StringVar(&c.flagFor, "for", "running", i18n.G("Condition to wait for (running, stopped, agent, ip or ready)")+"``")
+	cmd.Flags().IntVar(&c.flagTimeout, "timeout", -1, i18n.G("Maximum time to wait in seconds (-1 means forever)")+"``")
+	cmd.RunE = c.Run
+
+	_ = cmd.RegisterFlagCompletionFunc("for", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"running", "stopped", "agent", "ip", "ready"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWait) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing instance name"))
+	}
+
+	state, err := resource.server.WaitInstance(resource.name, c.flagFor, c.flagTimeout)
+	if err != nil {
+		return err
+	}
+
+	if c.flagFor != "ip" {
+		return nil
+	}
+
+	// Print the addresses which satisfied the condition.
+	addresses := []string{}
+	for netName, network := range state.Network {
+		if netName == "lo" {
+			continue
+		}
+
+		for _, address := range network.Addresses {
+			if address.Scope == "global" {
+				addresses = append(addresses, address.Address)
+			}
+		}
+	}
+
+	sort.Strings(addresses)
+
+	for _, address := range addresses {
+		fmt.Println(address)
+	}
+
+	return nil
+}
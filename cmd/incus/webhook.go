@@ -0,0 +1,654 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+type cmdWebhook struct {
+	global *cmdGlobal
+}
+
+func (c *cmdWebhook) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("webhook")
+	cmd.Short = i18n.G("Manage webhooks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage webhooks
+
+Webhooks receive the lifecycle events and the new warnings of the server as HTTP POST requests.`))
+
+	// Create
+	webhookCreateCmd := cmdWebhookCreate{global: c.global, webhook: c}
+	cmd.AddCommand(webhookCreateCmd.Command())
+
+	// Dead letters
+	webhookDeadLetterCmd := cmdWebhookDeadLetter{global: c.global, webhook: c}
+	cmd.AddCommand(webhookDeadLetterCmd.Command())
+
+	// Delete
+	webhookDeleteCmd := cmdWebhookDelete{global: c.global, webhook: c}
+	cmd.AddCommand(webhookDeleteCmd.Command())
+
+	// Edit
+	webhookEditCmd := cmdWebhookEdit{global: c.global, webhook: c}
+	cmd.AddCommand(webhookEditCmd.Command())
+
+	// List
+	webhookListCmd := cmdWebhookList{global: c.global, webhook: c}
+	cmd.AddCommand(webhookListCmd.Command())
+
+	// Show
+	webhookShowCmd := cmdWebhookShow{global: c.global, webhook: c}
+	cmd.AddCommand(webhookShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdWebhookCreate struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+
+	flagDescription string
+	flagSecret      string
+	flagTypes       []string
+	flagProjects    []string
+}
+
+func (c *cmdWebhookCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<webhook> [<url>]"))
+	cmd.Short = i18n.G("Create webhooks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create webhooks`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus webhook create chatops https://chat.example.net/hooks/incus --type=lifecycle --project=default
+    Create a webhook named chatops receiving the lifecycle events of the default project
+
+incus webhook create tickets < tickets.yaml
+    Create a webhook named tickets from the content of tickets.yaml`))
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Webhook description")+"``")
+	cmd.Flags().StringVar(&c.flagSecret, "secret", "", i18n.G("Secret used to sign the deliveries")+"``")
+	cmd.Flags().StringArrayVar(&c.flagTypes, "type", nil, i18n.G("Type of events to send (lifecycle or warning), can be repeated")+"``")
+	cmd.Flags().StringArrayVar(&c.flagProjects, "project", nil, i18n.G("Project to send the events of, can be repeated")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookCreate) Run(cmd *cobra.Command, args []string) error {
+	var stdinData api.WebhookPut
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.Unmarshal(contents, &stdinData)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing webhook name"))
+	}
+
+	// Create the webhook
+	webhook := api.WebhooksPost{}
+	webhook.Name = resource.name
+	webhook.WebhookPut = stdinData
+
+	if len(args) > 1 {
+		webhook.URL = args[1]
+	}
+
+	if c.flagDescription != "" {
+		webhook.Description = c.flagDescription
+	}
+
+	if c.flagSecret != "" {
+		webhook.Secret = c.flagSecret
+	}
+
+	if len(c.flagTypes) > 0 {
+		webhook.Types = c.flagTypes
+	}
+
+	if len(c.flagProjects) > 0 {
+		webhook.Projects = c.flagProjects
+	}
+
+	err = resource.server.CreateWebhook(webhook)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Webhook %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdWebhookDelete struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+func (c *cmdWebhookDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<webhook>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete webhooks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete webhooks`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpWebhooks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing webhook name"))
+	}
+
+	err = resource.server.DeleteWebhook(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Webhook %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdWebhookEdit struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+func (c *cmdWebhookEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<webhook>"))
+	cmd.Short = i18n.G("Edit webhooks as YAML")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit webhooks as YAML`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus webhook edit <webhook> < webhook.yaml
+    Update a webhook using the content of webhook.yaml`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpWebhooks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the webhook.
+### Any line starting with a '# will be ignored.
+###
+### A webhook consists of the URL the events are sent to, an optional
+### secret used to sign them and filters on the event types and projects.
+###
+### An example would look like:
+### description: Notify the operations channel
+### url: https://chat.example.net/hooks/incus
+### secret: s3cr3t
+### types:
+### - lifecycle
+### - warning
+### projects:
+### - default
+### name: chatops
+###
+### Note that the name is shown but cannot be changed`)
+}
+
+func (c *cmdWebhookEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing webhook name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.WebhookPut{}
+		err = yaml.Unmarshal(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateWebhook(resource.name, newdata, "")
+	}
+
+	// Extract the current value
+	webhook, etag, err := resource.server.GetWebhook(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&webhook)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.WebhookPut{}
+		err = yaml.Unmarshal(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateWebhook(resource.name, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// List.
+type cmdWebhookList struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+
+	flagFormat string
+}
+
+func (c *cmdWebhookList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List webhooks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List webhooks`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G("Format (csv|json|table|yaml|compact)")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	webhooks, err := resource.server.GetWebhooks()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, webhook := range webhooks {
+		data = append(data, []string{webhook.Name, webhook.URL, webhook.Description, strings.Join(webhook.Types, "\n"), strings.Join(webhook.Projects, "\n")})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("URL"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("TYPES"),
+		i18n.G("PROJECTS"),
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, webhooks)
+}
+
+// Show.
+type cmdWebhookShow struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+func (c *cmdWebhookShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<webhook>"))
+	cmd.Short = i18n.G("Show webhooks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show webhooks`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpWebhooks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing webhook name"))
+	}
+
+	webhook, _, err := resource.server.GetWebhook(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&webhook)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+// Dead letters.
+type cmdWebhookDeadLetter struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+func (c *cmdWebhookDeadLetter) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("dead-letter")
+	cmd.Short = i18n.G("Manage the events that couldn't be delivered to webhooks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage the events that couldn't be delivered to webhooks`))
+
+	// Clear
+	webhookDeadLetterClearCmd := cmdWebhookDeadLetterClear{global: c.global, webhookDeadLetter: c}
+	cmd.AddCommand(webhookDeadLetterClearCmd.Command())
+
+	// List
+	webhookDeadLetterListCmd := cmdWebhookDeadLetterList{global: c.global, webhookDeadLetter: c}
+	cmd.AddCommand(webhookDeadLetterListCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Clear.
+type cmdWebhookDeadLetterClear struct {
+	global            *cmdGlobal
+	webhookDeadLetter *cmdWebhookDeadLetter
+}
+
+func (c *cmdWebhookDeadLetterClear) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("clear", i18n.G("[<remote>:]<webhook>"))
+	cmd.Short = i18n.G("Delete the events that couldn't be delivered to a webhook")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete the events that couldn't be delivered to a webhook`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpWebhooks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookDeadLetterClear) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing webhook name"))
+	}
+
+	return resource.server.DeleteWebhookDeadLetters(resource.name)
+}
+
+// List.
+type cmdWebhookDeadLetterList struct {
+	global            *cmdGlobal
+	webhookDeadLetter *cmdWebhookDeadLetter
+
+	flagFormat string
+}
+
+func (c *cmdWebhookDeadLetterList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]<webhook>"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List the events that couldn't be delivered to a webhook")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List the events that couldn't be delivered to a webhook`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G("Format (csv|json|table|yaml|compact)")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpWebhooks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookDeadLetterList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing webhook name"))
+	}
+
+	letters, err := resource.server.GetWebhookDeadLetters(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, letter := range letters {
+		// Describe lifecycle events by their action and source.
+		event := letter.Event.Type
+		lifecycle := api.EventLifecycle{}
+		if letter.Event.Type == api.EventTypeLifecycle && json.Unmarshal(letter.Event.Metadata, &lifecycle) == nil {
+			event = fmt.Sprintf("%s (%s)", lifecycle.Action, lifecycle.Source)
+		}
+
+		data = append(data, []string{letter.FailedAt.Local().Format(dateLayout), event, letter.Event.Project, strconv.Itoa(letter.Attempts), letter.Error})
+	}
+
+	header := []string{
+		i18n.G("FAILED AT"),
+		i18n.G("EVENT"),
+		i18n.G("PROJECT"),
+		i18n.G("ATTEMPTS"),
+		i18n.G("ERROR"),
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, letters)
+}
@@ -60,14 +60,18 @@ var api10 = []APIEndpoint{
 	instanceExecOutputsCmd,
 	instanceLogCmd,
 	instanceLogsCmd,
+	instanceInventoryCmd,
 	instanceMetadataCmd,
 	instanceMetadataTemplatesCmd,
 	instancesCmd,
+	instancePortForwardCmd,
 	instanceRebuildCmd,
 	instanceSFTPCmd,
+	instanceSSHKeysCmd,
 	instanceSnapshotCmd,
 	instanceSnapshotsCmd,
 	instanceStateCmd,
+	instanceWaitCmd,
 	instanceAccessCmd,
 	eventsCmd,
 	imageAliasCmd,
@@ -107,6 +111,9 @@ var api10 = []APIEndpoint{
 	projectCmd,
 	projectsCmd,
 	projectStateCmd,
+	projectUsageCmd,
+	projectTemplatesCmd,
+	projectTemplateCmd,
 	projectAccessCmd,
 	storagePoolCmd,
 	storagePoolResourcesCmd,
@@ -129,6 +136,9 @@ var api10 = []APIEndpoint{
 	storagePoolVolumeTypeStateCmd,
 	warningsCmd,
 	warningCmd,
+	webhooksCmd,
+	webhookCmd,
+	webhookDeadLettersCmd,
 	metricsCmd,
 }
 
@@ -798,6 +808,10 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	bgpChanged := false
 	dnsChanged := false
 	lokiChanged := false
+	loggingOTLPChanged := false
+	loggingSyslogChanged := false
+	loggingGELFChanged := false
+	tracingChanged := false
 	oidcChanged := false
 	openFGAChanged := false
 	ovnChanged := false
@@ -834,6 +848,14 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 
 		case "loki.api.url", "loki.auth.username", "loki.auth.password", "loki.api.ca_cert", "loki.instance", "loki.labels", "loki.loglevel", "loki.types":
 			lokiChanged = true
+		case "logging.otlp.api.url", "logging.otlp.api.ca_cert", "logging.otlp.api.headers", "logging.otlp.instance", "logging.otlp.labels", "logging.otlp.loglevel", "logging.otlp.types":
+			loggingOTLPChanged = true
+		case "logging.syslog.address", "logging.syslog.protocol", "logging.syslog.ca_cert", "logging.syslog.instance", "logging.syslog.labels", "logging.syslog.loglevel", "logging.syslog.types":
+			loggingSyslogChanged = true
+		case "logging.gelf.address", "logging.gelf.protocol", "logging.gelf.ca_cert", "logging.gelf.instance", "logging.gelf.labels", "logging.gelf.loglevel", "logging.gelf.types":
+			loggingGELFChanged = true
+		case "tracing.otlp.api.url", "tracing.otlp.api.ca_cert", "tracing.otlp.api.headers", "tracing.sample_ratio":
+			tracingChanged = true
 
 		case "network.ovn.northbound_connection", "network.ovn.ca_cert", "network.ovn.client_cert", "network.ovn.client_key":
 			ovnChanged = true
@@ -954,13 +976,45 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	if lokiChanged {
 		lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes := clusterConfig.LokiServer()
 
-		if lokiURL == "" || lokiLoglevel == "" || len(lokiTypes) == 0 {
-			d.internalListener.RemoveHandler("loki")
-		} else {
-			err := d.setupLoki(lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes)
-			if err != nil {
-				return err
-			}
+		err := d.setupLoki(lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if loggingOTLPChanged {
+		otlpURL, otlpCACert, otlpHeaders, otlpInstance, otlpLoglevel, otlpLabels, otlpTypes := clusterConfig.LoggingOTLP()
+
+		err := d.setupLoggingOTLP(otlpURL, otlpCACert, otlpHeaders, otlpInstance, otlpLoglevel, otlpLabels, otlpTypes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if loggingSyslogChanged {
+		syslogAddress, syslogProtocol, syslogCACert, syslogInstance, syslogLoglevel, syslogLabels, syslogTypes := clusterConfig.LoggingSyslog()
+
+		err := d.setupLoggingSyslog(syslogAddress, syslogProtocol, syslogCACert, syslogInstance, syslogLoglevel, syslogLabels, syslogTypes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if loggingGELFChanged {
+		gelfAddress, gelfProtocol, gelfCACert, gelfInstance, gelfLoglevel, gelfLabels, gelfTypes := clusterConfig.LoggingGELF()
+
+		err := d.setupLoggingGELF(gelfAddress, gelfProtocol, gelfCACert, gelfInstance, gelfLoglevel, gelfLabels, gelfTypes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if tracingChanged {
+		tracingURL, tracingCACert, tracingHeaders, tracingSampleRatio := clusterConfig.TracingServer()
+
+		err := d.setupTracing(tracingURL, tracingCACert, tracingHeaders, tracingSampleRatio)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"runtime"
 	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -118,7 +119,7 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 		}
 
 		// Add internal metrics.
-		metricSet.Merge(internalMetrics(ctx, s.StartTime, tx))
+		metricSet.Merge(internalMetrics(ctx, s, tx))
 
 		return nil
 	})
@@ -312,7 +313,7 @@ func getFilteredMetrics(s *state.State, r *http.Request, compress bool, metricSe
 	return response.SyncResponsePlain(true, compress, metricSet.String())
 }
 
-func internalMetrics(ctx context.Context, daemonStartTime time.Time, tx *db.ClusterTx) *metrics.MetricSet {
+func internalMetrics(ctx context.Context, s *state.State, tx *db.ClusterTx) *metrics.MetricSet {
 	out := metrics.NewMetricSet(nil)
 
 	warnings, err := dbCluster.GetWarnings(ctx, tx.Tx())
@@ -332,7 +333,23 @@ func internalMetrics(ctx context.Context, daemonStartTime time.Time, tx *db.Clus
 	}
 
 	// Daemon uptime
-	out.AddSamples(metrics.UptimeSeconds, metrics.Sample{Value: time.Since(daemonStartTime).Seconds()})
+	out.AddSamples(metrics.UptimeSeconds, metrics.Sample{Value: time.Since(s.StartTime).Seconds()})
+
+	// Event listeners
+	listenerCounts := s.Events.ListenerCounts()
+	eventTypes := make([]string, 0, len(listenerCounts))
+	for eventType := range listenerCounts {
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	sort.Strings(eventTypes)
+
+	for _, eventType := range eventTypes {
+		out.AddSamples(metrics.EventListeners, metrics.Sample{Value: float64(listenerCounts[eventType]), Labels: map[string]string{"type": eventType}})
+	}
+
+	// API requests, database transactions, operations and transfers
+	out.AddDaemonSamples()
 
 	// Number of goroutines
 	out.AddSamples(metrics.GoGoroutines, metrics.Sample{Value: float64(runtime.NumGoroutine())})
@@ -364,6 +381,8 @@ func internalMetrics(ctx context.Context, daemonStartTime time.Time, tx *db.Clus
 	out.AddSamples(metrics.GoStackInuseBytes, metrics.Sample{Value: float64(ms.StackInuse)})
 	out.AddSamples(metrics.GoStackSysBytes, metrics.Sample{Value: float64(ms.StackSys)})
 	out.AddSamples(metrics.GoSysBytes, metrics.Sample{Value: float64(ms.Sys)})
+	out.AddSamples(metrics.GoGCTotal, metrics.Sample{Value: float64(ms.NumGC)})
+	out.AddSamples(metrics.GoGCPauseSecondsTotal, metrics.Sample{Value: float64(ms.PauseTotalNs) / float64(time.Second)})
 
 	return out
 }
@@ -16,6 +16,7 @@ import (
 
 	"github.com/lxc/incus/v6/client"
 	"github.com/lxc/incus/v6/internal/jmap"
+	"github.com/lxc/incus/v6/internal/revert"
 	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
@@ -31,6 +32,7 @@ import (
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
 	"github.com/lxc/incus/v6/shared/validate"
 )
@@ -305,11 +307,45 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 	// Parse the request.
 	project := api.ProjectsPost{}
 
-	// Set default features.
+	err := json.NewDecoder(r.Body).Decode(&project)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = projectValidateName(project.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	if project.Config == nil {
 		project.Config = map[string]string{}
 	}
 
+	// Apply the template, the request config taking precedence over it.
+	var template *api.ProjectTemplate
+	if project.Template != "" {
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			template, err = tx.GetProjectTemplate(ctx, project.Template)
+			return err
+		})
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed loading project template %q: %w", project.Template, err))
+		}
+
+		for key, value := range template.Config {
+			_, ok := project.Config[key]
+			if !ok {
+				project.Config[key] = value
+			}
+		}
+
+		if project.Description == "" {
+			project.Description = template.Description
+		}
+	}
+
+	// Set default features.
 	for featureName, featureInfo := range cluster.ProjectFeatures {
 		_, ok := project.Config[featureName]
 		if !ok && featureInfo.DefaultEnabled {
@@ -317,25 +353,24 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
-	err := json.NewDecoder(r.Body).Decode(&project)
-	if err != nil {
-		return response.BadRequest(err)
-	}
-
-	// Quick checks.
-	err = projectValidateName(project.Name)
+	// Validate the configuration.
+	err = projectValidateConfig(s, project.Config)
 	if err != nil {
 		return response.BadRequest(err)
 	}
 
-	// Validate the configuration.
-	err = projectValidateConfig(s, project.Config)
+	err = projecthelpers.CheckProjectParentPermission(s.Authorizer, r, "", project.Config["parent"])
 	if err != nil {
-		return response.BadRequest(err)
+		return response.SmartError(err)
 	}
 
 	var id int64
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err := projecthelpers.CheckProjectParent(ctx, tx, project.Name, project.Config["parent"])
+		if err != nil {
+			return err
+		}
+
 		id, err = cluster.CreateProject(ctx, tx.Tx(), cluster.Project{Description: project.Description, Name: project.Name})
 		if err != nil {
 			return fmt.Errorf("Failed adding database record: %w", err)
@@ -371,13 +406,88 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 		logger.Error("Failed to add project to authorizer", logger.Ctx{"name": project.Name, "error": err})
 	}
 
+	var lcCtx map[string]any
+	if template != nil {
+		err = projectApplyTemplate(r.Context(), s, id, project.Name, template)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed creating project %q from template %q: %w", project.Name, template.Name, err))
+		}
+
+		lcCtx = map[string]any{"template": template.Name}
+	}
+
 	requestor := request.CreateRequestor(r)
-	lc := lifecycle.ProjectCreated.Event(project.Name, requestor, nil)
+	lc := lifecycle.ProjectCreated.Event(project.Name, requestor, lcCtx)
 	s.Events.SendLifecycle(project.Name, lc)
 
 	return response.SyncResponseLocation(true, nil, lc.Source)
 }
 
+// projectApplyTemplate creates the profiles and networks of the template in the newly created project.
+// On failure, the project and anything created in it are removed.
+func projectApplyTemplate(ctx context.Context, s *state.State, id int64, projectName string, template *api.ProjectTemplate) error {
+	if len(template.Profiles) == 0 && len(template.Networks) == 0 {
+		return nil
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	reverter.Add(func() {
+		err := s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return cluster.DeleteProject(ctx, tx.Tx(), projectName)
+		})
+		if err != nil {
+			logger.Error("Failed deleting project after template failure", logger.Ctx{"project": projectName, "err": err})
+		}
+
+		err = s.Authorizer.DeleteProject(context.Background(), id, projectName)
+		if err != nil {
+			logger.Error("Failed to remove project from authorizer", logger.Ctx{"name": projectName, "error": err})
+		}
+	})
+
+	// Connect to the local server.
+	target, err := incus.ConnectIncusUnix(s.OS.GetUnixSocket(), nil)
+	if err != nil {
+		return err
+	}
+
+	target = target.UseProject(projectName)
+
+	for _, profile := range template.Profiles {
+		if profile.Name == api.ProjectDefaultName {
+			err = target.UpdateProfile(profile.Name, profile.ProfilePut, "")
+			if err != nil {
+				return fmt.Errorf("Failed updating profile %q: %w", profile.Name, err)
+			}
+
+			continue
+		}
+
+		err = target.CreateProfile(profile)
+		if err != nil {
+			return fmt.Errorf("Failed creating profile %q: %w", profile.Name, err)
+		}
+
+		profileName := profile.Name
+		reverter.Add(func() { _ = target.DeleteProfile(profileName) })
+	}
+
+	for _, network := range template.Networks {
+		err = target.CreateNetwork(network)
+		if err != nil {
+			return fmt.Errorf("Failed creating network %q: %w", network.Name, err)
+		}
+
+		networkName := network.Name
+		reverter.Add(func() { _ = target.DeleteNetwork(networkName) })
+	}
+
+	reverter.Success()
+	return nil
+}
+
 // Create the default profile of a project.
 func projectCreateDefaultProfile(ctx context.Context, tx *db.ClusterTx, project string) error {
 	// Create a default profile
@@ -544,6 +654,11 @@ func projectPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = projecthelpers.CheckProjectParentPermission(s.Authorizer, r, project.Config["parent"], req.Config["parent"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(project.Name, lifecycle.ProjectUpdated.Event(project.Name, requestor, nil))
 
@@ -660,6 +775,11 @@ func projectPatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	err = projecthelpers.CheckProjectParentPermission(s.Authorizer, r, project.Config["parent"], req.Config["parent"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(project.Name, lifecycle.ProjectUpdated.Event(project.Name, requestor, nil))
 
@@ -854,7 +974,12 @@ func projectPost(d *Daemon, r *http.Request) response.Response {
 				return err
 			}
 
-			return cluster.RenameProject(ctx, tx.Tx(), name, req.Name)
+			err = cluster.RenameProject(ctx, tx.Tx(), name, req.Name)
+			if err != nil {
+				return err
+			}
+
+			return cluster.RenameProjectParent(ctx, tx.Tx(), name, req.Name)
 		})
 		if err != nil {
 			return err
@@ -925,6 +1050,15 @@ func projectDelete(d *Daemon, r *http.Request) response.Response {
 			return fmt.Errorf("Fetch project %q: %w", name, err)
 		}
 
+		children, err := projecthelpers.GetProjectChildren(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+
+		if len(children) > 0 {
+			return api.StatusErrorf(http.StatusBadRequest, "Project %q is the parent of projects %s", name, strings.Join(children, ", "))
+		}
+
 		if !force {
 			empty, err := projectIsEmpty(ctx, project, tx)
 			if err != nil {
@@ -1290,6 +1424,15 @@ func isEitherAllowOrBlockOrManaged(value string) error {
 	return validate.Optional(validate.IsOneOf("block", "allow", "managed"))(value)
 }
 
+func isEitherAllowOrBlockOrReadOnly(value string) error {
+	return validate.Optional(validate.IsOneOf("block", "allow", "read-only"))(value)
+}
+
+func projectValidateBitRate(value string) error {
+	_, err := units.ParseBitSizeString(value)
+	return err
+}
+
 func projectValidateConfig(s *state.State, config map[string]string) error {
 	// Validate the project configuration.
 	projectConfigKeys := map[string]func(value string) error{
@@ -1301,6 +1444,16 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Compression algorithm to use for backups
 		"backups.compression_algorithm": validate.IsCompressionAlgorithm,
 
+		// gendoc:generate(entity=project, group=specific, key=parent)
+		// The limits set on the parent project apply to the combined usage of
+		// the parent project and of all of its descendants.
+		// Setting, changing or clearing it requires the permission to edit both the current and the new
+		// parent project.
+		// ---
+		//  type: string
+		//  shortdesc: Name of the parent project
+		"parent": validate.Optional(projectValidateName),
+
 		// gendoc:generate(entity=project, group=features, key=features.profiles)
 		//
 		// ---
@@ -1440,6 +1593,30 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Maximum disk space used by the project
 		"limits.disk": validate.Optional(validate.IsSize),
 
+		// gendoc:generate(entity=project, group=limits, key=limits.disk.iops)
+		// This value is the maximum value for the sum of the read and write IOPS limits of the disk devices of the instances of the project.
+		// When set, each disk device of the project's instances must have its `limits.read` and `limits.write` (or `limits.max`) set in IOPS.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum disk IOPS of the project
+		"limits.disk.iops": validate.Optional(validate.IsUint32),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.network.egress)
+		// This value is the maximum value for the sum of the egress limits of the NIC devices of the instances of the project.
+		// When set, each NIC device of the project's instances must have its `limits.egress` (or `limits.max`) set.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum outgoing bandwidth of the project (in bit/s)
+		"limits.network.egress": validate.Optional(projectValidateBitRate),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.network.ingress)
+		// This value is the maximum value for the sum of the ingress limits of the NIC devices of the instances of the project.
+		// When set, each NIC device of the project's instances must have its `limits.ingress` (or `limits.max`) set.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum incoming bandwidth of the project (in bit/s)
+		"limits.network.ingress": validate.Optional(projectValidateBitRate),
+
 		// gendoc:generate(entity=project, group=limits, key=limits.networks)
 		//
 		// ---
@@ -1626,6 +1803,49 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Which `source` can be used for `disk` devices
 		"restricted.devices.disk.paths": validate.Optional(validate.IsListOf(validate.IsAbsFilePath)),
 
+		// gendoc:generate(entity=project, group=restricted, key=restricted.exec)
+		// Possible values are `allow` or `block`.
+		// When set to `block`, users other than server administrators can't run commands in instances of the
+		// project, which includes forwarding ports to them and setting their SSH keys.
+		// Setting the `snapshots.hooks.pre`, `snapshots.hooks.post` and `boot.shutdown_command` options is
+		// then forbidden for all users.
+		// ---
+		//  type: string
+		//  defaultdesc: `allow`
+		//  shortdesc: Whether to prevent running commands in instances
+		"restricted.exec": isEitherAllowOrBlock,
+
+		// gendoc:generate(entity=project, group=restricted, key=restricted.console)
+		// Possible values are `allow` or `block`.
+		// When set to `block`, users other than server administrators can't attach to the consoles of instances
+		// of the project or retrieve their console logs.
+		// ---
+		//  type: string
+		//  defaultdesc: `allow`
+		//  shortdesc: Whether to prevent accessing instance consoles
+		"restricted.console": isEitherAllowOrBlock,
+
+		// gendoc:generate(entity=project, group=restricted, key=restricted.files)
+		// Possible values are `allow`, `block` or `read-only`.
+		// When set to `block`, users other than server administrators can't access the files of instances of
+		// the project. When set to `read-only`, they can read files but not create, modify or delete them.
+		// SFTP access is only available with `allow`.
+		// ---
+		//  type: string
+		//  defaultdesc: `allow`
+		//  shortdesc: Whether to prevent accessing instance files
+		"restricted.files": isEitherAllowOrBlockOrReadOnly,
+
+		// gendoc:generate(entity=project, group=restricted, key=restricted.exec.record)
+		// When enabled, the output of all interactive `exec` and text console sessions of the project's
+		// instances is recorded in the asciicast v2 format. The recordings are made available as
+		// `session_<operation>.cast` files in the instance logs.
+		// ---
+		//  type: bool
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to record interactive exec and console sessions
+		"restricted.exec.record": validate.Optional(validate.IsBool),
+
 		// gendoc:generate(entity=project, group=restricted, key=restricted.idmap.uid)
 		// This option specifies the host UID ranges that are allowed in the instance's {config:option}`instance-raw:raw.idmap` setting.
 		// ---
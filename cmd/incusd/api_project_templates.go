@@ -0,0 +1,424 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+var projectTemplatesCmd = APIEndpoint{
+	Path: "project-templates",
+
+	Get:  APIEndpointAction{Handler: projectTemplatesGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanCreateProjects)},
+	Post: APIEndpointAction{Handler: projectTemplatesPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var projectTemplateCmd = APIEndpoint{
+	Path: "project-templates/{name}",
+
+	Delete: APIEndpointAction{Handler: projectTemplateDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: projectTemplateGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanCreateProjects)},
+	Put:    APIEndpointAction{Handler: projectTemplatePut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation GET /1.0/project-templates project-templates project_templates_get
+//
+//  Get the project templates
+//
+//  Returns a list of project templates (URLs).
+//
+//  ---
+//  produces:
+//    - application/json
+//  responses:
+//    "200":
+//      description: API endpoints
+//      schema:
+//        type: object
+//        description: Sync response
+//        properties:
+//          type:
+//            type: string
+//            description: Response type
+//            example: sync
+//          status:
+//            type: string
+//            description: Status description
+//            example: Success
+//          status_code:
+//            type: integer
+//            description: Status code
+//            example: 200
+//          metadata:
+//            type: array
+//            description: List of endpoints
+//            items:
+//              type: string
+//            example: |-
+//              [
+//                "/1.0/project-templates/tenant"
+//              ]
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/project-templates?recursion=1 project-templates project_templates_get_recursion1
+//
+//	Get the project templates
+//
+//	Returns a list of project templates (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of project templates
+//	          items:
+//	            $ref: "#/definitions/ProjectTemplate"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var templates []api.ProjectTemplate
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		templates, err = tx.GetProjectTemplates(ctx)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion {
+		return response.SyncResponse(true, templates)
+	}
+
+	urls := make([]string, 0, len(templates))
+	for _, template := range templates {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "project-templates", template.Name).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/project-templates project-templates project_templates_post
+//
+//	Add a project template
+//
+//	Creates a new project template.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: template
+//	    description: Project template
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProjectTemplatesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.ProjectTemplatesPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = projectValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = projectTemplateValidate(s, req.ProjectTemplatePut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateProjectTemplate(ctx, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	lc := lifecycle.ProjectTemplateCreated.Event(req.Name, requestor, nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/project-templates/{name} project-templates project_template_get
+//
+//	Get the project template
+//
+//	Gets a specific project template.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Project template
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ProjectTemplate"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplateGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var template *api.ProjectTemplate
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		template, err = tx.GetProjectTemplate(ctx, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, template, template.Writable())
+}
+
+// swagger:operation PUT /1.0/project-templates/{name} project-templates project_template_put
+//
+//	Update the project template
+//
+//	Updates the entire project template.
+//	Projects previously created from the template aren't affected.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: template
+//	    description: Project template
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProjectTemplatePut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatePut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var template *api.ProjectTemplate
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		template, err = tx.GetProjectTemplate(ctx, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate ETag
+	err = localUtil.EtagCheck(r, template.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.ProjectTemplatePut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = projectTemplateValidate(s, req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateProjectTemplate(ctx, name, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ProjectTemplateUpdated.Event(name, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/project-templates/{name} project-templates project_template_delete
+//
+//	Delete the project template
+//
+//	Removes the project template.
+//	Projects previously created from the template aren't affected.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplateDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteProjectTemplate(ctx, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ProjectTemplateDeleted.Event(name, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// projectTemplateValidate checks the configuration, profiles and networks of a project template.
+func projectTemplateValidate(s *state.State, template api.ProjectTemplatePut) error {
+	if template.Config == nil {
+		template.Config = map[string]string{}
+	}
+
+	err := projectValidateConfig(s, template.Config)
+	if err != nil {
+		return err
+	}
+
+	profiles := map[string]bool{}
+	for _, profile := range template.Profiles {
+		if profile.Name == "" {
+			return fmt.Errorf("Template profiles must have a name")
+		}
+
+		if profiles[profile.Name] {
+			return fmt.Errorf("Duplicate template profile %q", profile.Name)
+		}
+
+		profiles[profile.Name] = true
+	}
+
+	if len(template.Profiles) > 0 && template.Config["features.profiles"] == "false" {
+		return fmt.Errorf("Template profiles require %q to be enabled", "features.profiles")
+	}
+
+	networks := map[string]bool{}
+	for _, network := range template.Networks {
+		if network.Name == "" {
+			return fmt.Errorf("Template networks must have a name")
+		}
+
+		if networks[network.Name] {
+			return fmt.Errorf("Duplicate template network %q", network.Name)
+		}
+
+		networks[network.Name] = true
+	}
+
+	if len(template.Networks) > 0 && template.Config["features.networks"] != "true" {
+		return fmt.Errorf("Template networks require %q to be enabled", "features.networks")
+	}
+
+	return nil
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/metrics"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// projectsUsageInterval is how often the usage of the local instances is sampled.
+var projectsUsageInterval = 5 * time.Minute
+
+var projectUsageCmd = APIEndpoint{
+	Path: "projects/{name}/usage",
+
+	Get: APIEndpointAction{Handler: projectUsageGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView, "name")},
+}
+
+// swagger:operation GET /1.0/projects/{name}/usage projects project_usage_get
+//
+//	Get the project usage history
+//
+//	Gets the hourly resource usage records of the instances of the project.
+//
+//	---
+//	produces:
+//	  - application/json
+//	  - text/csv
+//	parameters:
+//	  - in: query
+//	    name: from
+//	    description: Start of the period (RFC3339), defaults to the start of the current month
+//	    type: string
+//	    example: 2026-10-01T00:00:00Z
+//	  - in: query
+//	    name: to
+//	    description: End of the period (RFC3339), defaults to now
+//	    type: string
+//	    example: 2026-11-01T00:00:00Z
+//	  - in: query
+//	    name: format
+//	    description: Set to "csv" to export the records as CSV
+//	    type: string
+//	    example: csv
+//	responses:
+//	  "200":
+//	    description: Project usage records
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: Usage records
+//	          items:
+//	            $ref: "#/definitions/ProjectUsageRecord"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectUsageGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := now
+
+	if r.FormValue("from") != "" {
+		from, err = time.Parse(time.RFC3339, r.FormValue("from"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid %q value: %w", "from", err))
+		}
+	}
+
+	if r.FormValue("to") != "" {
+		to, err = time.Parse(time.RFC3339, r.FormValue("to"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid %q value: %w", "to", err))
+		}
+	}
+
+	format := r.FormValue("format")
+	if format != "" && format != "json" && format != "csv" {
+		return response.BadRequest(fmt.Errorf("Invalid format %q", format))
+	}
+
+	var usage []db.InstanceUsage
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check the project exists.
+		_, err := cluster.GetProject(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		usage, err = tx.GetProjectUsage(ctx, name, from, to)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	records := make([]api.ProjectUsageRecord, 0, len(usage))
+	for _, u := range usage {
+		records = append(records, api.ProjectUsageRecord{
+			Instance:             u.Instance,
+			Type:                 u.Type.String(),
+			Date:                 u.Date,
+			CPUSeconds:           u.CPUSeconds,
+			MemoryBytes:          u.MemoryBytes,
+			DiskBytes:            u.DiskBytes,
+			NetworkReceivedBytes: u.NetworkReceivedBytes,
+			NetworkSentBytes:     u.NetworkSentBytes,
+		})
+	}
+
+	if format == "csv" {
+		return response.ManualResponse(func(w http.ResponseWriter) error {
+			w.Header().Set("Content-Type", "text/csv")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s-usage.csv", name)))
+
+			return projectUsageWriteCSV(w, records)
+		})
+	}
+
+	return response.SyncResponse(true, records)
+}
+
+// projectUsageWriteCSV writes the usage records as CSV, with a header line.
+func projectUsageWriteCSV(w http.ResponseWriter, records []api.ProjectUsageRecord) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{"instance", "type", "date", "cpu_seconds", "memory_bytes", "disk_bytes", "network_received_bytes", "network_sent_bytes"})
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		err := writer.Write([]string{
+			record.Instance,
+			record.Type,
+			record.Date.Format(time.RFC3339),
+			strconv.FormatFloat(record.CPUSeconds, 'f', 3, 64),
+			strconv.FormatInt(record.MemoryBytes, 10),
+			strconv.FormatInt(record.DiskBytes, 10),
+			strconv.FormatInt(record.NetworkReceivedBytes, 10),
+			strconv.FormatInt(record.NetworkSentBytes, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// projectsUsageTask samples the resource usage of the running local instances and aggregates it into the
+// hourly usage records of their projects.
+func projectsUsageTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		instances, err := instance.LoadNodeAll(s, instancetype.Any)
+		if err != nil {
+			logger.Error("Failed loading instances to sample their usage", logger.Ctx{"err": err})
+			return
+		}
+
+		hostInterfaces, _ := net.Interfaces()
+		now := time.Now().UTC()
+
+		sampled := make([]instance.Instance, 0, len(instances))
+		sets := make([]*metrics.MetricSet, 0, len(instances))
+		for _, inst := range instances {
+			if !inst.IsRunning() {
+				continue
+			}
+
+			set, err := inst.Metrics(hostInterfaces)
+			if err != nil {
+				logger.Debug("Failed getting instance metrics to sample its usage", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+				continue
+			}
+
+			sampled = append(sampled, inst)
+			sets = append(sets, set)
+		}
+
+		if len(sampled) == 0 {
+			return
+		}
+
+		// Record all the samples in a single transaction.
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			for i, inst := range sampled {
+				last, err := tx.GetLastInstanceUsage(ctx, inst.Project().Name, inst.Name())
+				if err != nil {
+					return err
+				}
+
+				err = tx.UpsertInstanceUsage(ctx, projectUsageAggregate(last, inst.Project().Name, inst.Name(), inst.Type(), sets[i], now))
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Warn("Failed recording instance usage", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(projectsUsageInterval)
+}
+
+// pruneProjectsUsageTask deletes the project usage records older than the configured retention.
+func pruneProjectsUsageTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		retention := time.Duration(s.GlobalConfig.ProjectsUsageRetentionDays()) * 24 * time.Hour
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.DeleteInstanceUsageBefore(ctx, time.Now().Add(-retention))
+		})
+		if err != nil {
+			logger.Error("Failed pruning project usage records", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Daily()
+}
+
+// projectUsageAggregate adds a sample of the instance metrics to the usage record of the current hour.
+// Counters are turned into usage by comparing them to the last sample, so usage is accounted from the
+// first sample of the instance on.
+func projectUsageAggregate(last *db.InstanceUsage, projectName string, instanceName string, instanceType instancetype.Type, set *metrics.MetricSet, now time.Time) db.InstanceUsage {
+	cpuSeconds := set.Sum(metrics.CPUSecondsTotal, func(labels map[string]string) bool {
+		return labels["mode"] != "idle" && labels["mode"] != "iowait" && labels["mode"] != "steal"
+	})
+
+	isNotLoopback := func(labels map[string]string) bool {
+		return labels["device"] != "lo"
+	}
+
+	networkReceived := int64(set.Sum(metrics.NetworkReceiveBytesTotal, isNotLoopback))
+	networkSent := int64(set.Sum(metrics.NetworkTransmitBytesTotal, isNotLoopback))
+
+	memory := int64(set.Sum(metrics.MemoryMemTotalBytes, nil) - set.Sum(metrics.MemoryMemAvailableBytes, nil))
+	if memory <= 0 {
+		memory = int64(set.Sum(metrics.MemoryRSSBytes, nil))
+	}
+
+	isRoot := func(labels map[string]string) bool {
+		return labels["mountpoint"] == "/"
+	}
+
+	disk := int64(set.Sum(metrics.FilesystemSizeBytes, isRoot) - set.Sum(metrics.FilesystemFreeBytes, isRoot))
+
+	usage := db.InstanceUsage{
+		Project:                projectName,
+		Instance:               instanceName,
+		Type:                   instanceType,
+		Date:                   now.Truncate(time.Hour),
+		CPUSecondsCounter:      cpuSeconds,
+		NetworkReceivedCounter: networkReceived,
+		NetworkSentCounter:     networkSent,
+	}
+
+	// Counters are reset when the instance restarts, in which case all of the current value is new usage.
+	var cpuDelta float64
+	var receivedDelta, sentDelta int64
+	if last != nil {
+		cpuDelta = cpuSeconds - last.CPUSecondsCounter
+		if cpuDelta < 0 {
+			cpuDelta = cpuSeconds
+		}
+
+		receivedDelta = networkReceived - last.NetworkReceivedCounter
+		if receivedDelta < 0 {
+			receivedDelta = networkReceived
+		}
+
+		sentDelta = networkSent - last.NetworkSentCounter
+		if sentDelta < 0 {
+			sentDelta = networkSent
+		}
+	}
+
+	if last != nil && last.Date.Equal(usage.Date) {
+		usage.Samples = last.Samples + 1
+		usage.CPUSeconds = last.CPUSeconds + cpuDelta
+		usage.NetworkReceivedBytes = last.NetworkReceivedBytes + receivedDelta
+		usage.NetworkSentBytes = last.NetworkSentBytes + sentDelta
+		usage.MemoryBytes = (last.MemoryBytes*last.Samples + memory) / usage.Samples
+		usage.DiskBytes = max(last.DiskBytes, disk)
+	} else {
+		usage.Samples = 1
+		usage.CPUSeconds = cpuDelta
+		usage.NetworkReceivedBytes = receivedDelta
+		usage.NetworkSentBytes = sentDelta
+		usage.MemoryBytes = memory
+		usage.DiskBytes = disk
+	}
+
+	return usage
+}
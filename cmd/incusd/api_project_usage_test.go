@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/metrics"
+)
+
+// projectUsageMetrics returns the metrics of an instance with the given counters and memory usage.
+func projectUsageMetrics(cpuSeconds float64, received float64, sent float64, memory float64) *metrics.MetricSet {
+	set := metrics.NewMetricSet(nil)
+	set.AddSamples(metrics.CPUSecondsTotal,
+		metrics.Sample{Labels: map[string]string{"cpu": "0", "mode": "user"}, Value: cpuSeconds},
+		metrics.Sample{Labels: map[string]string{"cpu": "0", "mode": "idle"}, Value: 1000})
+	set.AddSamples(metrics.NetworkReceiveBytesTotal,
+		metrics.Sample{Labels: map[string]string{"device": "eth0"}, Value: received},
+		metrics.Sample{Labels: map[string]string{"device": "lo"}, Value: 1000})
+	set.AddSamples(metrics.NetworkTransmitBytesTotal, metrics.Sample{Labels: map[string]string{"device": "eth0"}, Value: sent})
+	set.AddSamples(metrics.MemoryMemTotalBytes, metrics.Sample{Value: 1000})
+	set.AddSamples(metrics.MemoryMemAvailableBytes, metrics.Sample{Value: 1000 - memory})
+	set.AddSamples(metrics.FilesystemSizeBytes, metrics.Sample{Labels: map[string]string{"mountpoint": "/"}, Value: 500})
+	set.AddSamples(metrics.FilesystemFreeBytes, metrics.Sample{Labels: map[string]string{"mountpoint": "/"}, Value: 200})
+
+	return set
+}
+
+func TestProjectUsageAggregate(t *testing.T) {
+	hour := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	aggregate := func(last *db.InstanceUsage, set *metrics.MetricSet, now time.Time) db.InstanceUsage {
+		return projectUsageAggregate(last, "p1", "c1", instancetype.Container, set, now)
+	}
+
+	// The first sample only records the counters.
+	usage := aggregate(nil, projectUsageMetrics(10, 100, 50, 400), hour.Add(5*time.Minute))
+	assert.Equal(t, hour, usage.Date)
+	assert.Equal(t, int64(1), usage.Samples)
+	assert.Equal(t, 0.0, usage.CPUSeconds)
+	assert.Equal(t, int64(0), usage.NetworkReceivedBytes)
+	assert.Equal(t, int64(400), usage.MemoryBytes)
+	assert.Equal(t, int64(300), usage.DiskBytes)
+	assert.Equal(t, 10.0, usage.CPUSecondsCounter)
+
+	// Samples of the same hour are added to the record.
+	usage = aggregate(&usage, projectUsageMetrics(15, 160, 70, 200), hour.Add(10*time.Minute))
+	assert.Equal(t, hour, usage.Date)
+	assert.Equal(t, int64(2), usage.Samples)
+	assert.Equal(t, 5.0, usage.CPUSeconds)
+	assert.Equal(t, int64(60), usage.NetworkReceivedBytes)
+	assert.Equal(t, int64(20), usage.NetworkSentBytes)
+	assert.Equal(t, int64(300), usage.MemoryBytes)
+
+	// Counters going backwards were reset by a restart, so all of their value is new usage.
+	usage = aggregate(&usage, projectUsageMetrics(3, 40, 10, 300), hour.Add(15*time.Minute))
+	assert.Equal(t, int64(3), usage.Samples)
+	assert.Equal(t, 8.0, usage.CPUSeconds)
+	assert.Equal(t, int64(100), usage.NetworkReceivedBytes)
+	assert.Equal(t, int64(30), usage.NetworkSentBytes)
+	assert.Equal(t, 3.0, usage.CPUSecondsCounter)
+
+	// A sample of the next hour starts a new record with the usage since the last sample.
+	usage = aggregate(&usage, projectUsageMetrics(5, 50, 15, 100), hour.Add(65*time.Minute))
+	assert.Equal(t, hour.Add(time.Hour), usage.Date)
+	assert.Equal(t, int64(1), usage.Samples)
+	assert.Equal(t, 2.0, usage.CPUSeconds)
+	assert.Equal(t, int64(10), usage.NetworkReceivedBytes)
+	assert.Equal(t, int64(5), usage.NetworkSentBytes)
+	assert.Equal(t, int64(100), usage.MemoryBytes)
+	assert.Equal(t, "p1", usage.Project)
+	assert.Equal(t, "c1", usage.Instance)
+}
@@ -0,0 +1,644 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/task"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/server/webhook"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+var webhooksCmd = APIEndpoint{
+	Path: "webhooks",
+
+	Get:  APIEndpointAction{Handler: webhooksGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Post: APIEndpointAction{Handler: webhooksPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var webhookCmd = APIEndpoint{
+	Path: "webhooks/{name}",
+
+	Delete: APIEndpointAction{Handler: webhookDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: webhookGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Put:    APIEndpointAction{Handler: webhookPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var webhookDeadLettersCmd = APIEndpoint{
+	Path: "webhooks/{name}/dead-letters",
+
+	Delete: APIEndpointAction{Handler: webhookDeadLettersDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: webhookDeadLettersGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation GET /1.0/webhooks webhooks webhooks_get
+//
+//  Get the webhooks
+//
+//  Returns a list of webhooks (URLs).
+//
+//  ---
+//  produces:
+//    - application/json
+//  responses:
+//    "200":
+//      description: API endpoints
+//      schema:
+//        type: object
+//        description: Sync response
+//        properties:
+//          type:
+//            type: string
+//            description: Response type
+//            example: sync
+//          status:
+//            type: string
+//            description: Status description
+//            example: Success
+//          status_code:
+//            type: integer
+//            description: Status code
+//            example: 200
+//          metadata:
+//            type: array
+//            description: List of endpoints
+//            items:
+//              type: string
+//            example: |-
+//              [
+//                "/1.0/webhooks/chatops"
+//              ]
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/webhooks?recursion=1 webhooks webhooks_get_recursion1
+//
+//	Get the webhooks
+//
+//	Returns a list of webhooks (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of webhooks
+//	          items:
+//	            $ref: "#/definitions/Webhook"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhooksGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var webhooks []api.Webhook
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		webhooks, err = tx.GetWebhooks(ctx)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion {
+		return response.SyncResponse(true, webhooks)
+	}
+
+	urls := make([]string, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "webhooks", webhook.Name).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/webhooks webhooks webhooks_post
+//
+//	Add a webhook
+//
+//	Creates a new webhook.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: webhook
+//	    description: Webhook
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/WebhooksPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhooksPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.WebhooksPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Name == "" {
+		return response.BadRequest(fmt.Errorf("No name provided"))
+	}
+
+	err = validate.IsURLSegmentSafe(req.Name)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid webhook name %q: %w", req.Name, err))
+	}
+
+	err = webhookValidate(req.WebhookPut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateWebhook(ctx, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	d.webhooksRefresh()
+
+	requestor := request.CreateRequestor(r)
+	lc := lifecycle.WebhookCreated.Event(req.Name, requestor, nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/webhooks/{name} webhooks webhook_get
+//
+//	Get the webhook
+//
+//	Gets a specific webhook.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Webhook
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/Webhook"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var webhook *api.Webhook
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		webhook, err = tx.GetWebhook(ctx, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, webhook, webhook.Writable())
+}
+
+// swagger:operation PUT /1.0/webhooks/{name} webhooks webhook_put
+//
+//	Update the webhook
+//
+//	Updates the entire webhook.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: webhook
+//	    description: Webhook
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/WebhookPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var webhook *api.Webhook
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		webhook, err = tx.GetWebhook(ctx, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate ETag
+	err = localUtil.EtagCheck(r, webhook.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.WebhookPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = webhookValidate(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateWebhook(ctx, name, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	d.webhooksRefresh()
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.WebhookUpdated.Event(name, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/webhooks/{name} webhooks webhook_delete
+//
+//	Delete the webhook
+//
+//	Removes the webhook, along with the events that couldn't be delivered to it.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteWebhook(ctx, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	d.webhooksRefresh()
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.WebhookDeleted.Event(name, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/webhooks/{name}/dead-letters webhooks webhook_dead_letters_get
+//
+//	Get the undelivered events
+//
+//	Returns the most recent events that couldn't be delivered to the webhook, oldest first.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Undelivered events
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of undelivered events
+//	          items:
+//	            $ref: "#/definitions/WebhookDeadLetter"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookDeadLettersGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var letters []api.WebhookDeadLetter
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.GetWebhook(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		letters, err = tx.GetWebhookDeadLetters(ctx, name)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, letters)
+}
+
+// swagger:operation DELETE /1.0/webhooks/{name}/dead-letters webhooks webhook_dead_letters_delete
+//
+//	Delete the undelivered events
+//
+//	Removes the events that couldn't be delivered to the webhook.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookDeadLettersDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteWebhookDeadLetters(ctx, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.WebhookDeadLettersDeleted.Event(name, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// webhookValidate checks the URL and filters of a webhook.
+func webhookValidate(put api.WebhookPut) error {
+	err := validate.IsRequestURL(put.URL)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(put.URL)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("Webhook URL must use http or https")
+	}
+
+	for _, eventType := range put.Types {
+		if !slices.Contains(webhook.EventTypes, eventType) {
+			return fmt.Errorf("Unsupported event type %q", eventType)
+		}
+	}
+
+	for _, projectName := range put.Projects {
+		if projectName == "" || strings.Contains(projectName, ",") {
+			return fmt.Errorf("Invalid project name %q", projectName)
+		}
+	}
+
+	return nil
+}
+
+// webhooksLoad returns the configured webhooks.
+func (d *Daemon) webhooksLoad() ([]api.Webhook, error) {
+	var webhooks []api.Webhook
+	err := d.db.Cluster.Transaction(d.shutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		webhooks, err = tx.GetWebhooks(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// webhooksDeadLetter records an event that couldn't be delivered to a webhook.
+func (d *Daemon) webhooksDeadLetter(name string, letter api.WebhookDeadLetter) {
+	err := d.db.Cluster.Transaction(d.shutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateWebhookDeadLetter(ctx, name, letter)
+	})
+	if err != nil {
+		logger.Warn("Failed recording undelivered webhook event", logger.Ctx{"webhook": name, "err": err})
+	}
+}
+
+// webhooksRefresh reloads the webhooks, only listening to the lifecycle events when a webhook is subscribed to them.
+func (d *Daemon) webhooksRefresh() {
+	d.webhooks.Reload()
+
+	if !d.webhooks.Subscribed(api.EventTypeLifecycle) {
+		d.internalListener.RemoveHandler("webhooks")
+		return
+	}
+
+	d.internalListener.AddHandler("webhooks", func(event api.Event) {
+		// Events of other cluster members are sent by those members.
+		if event.Location != d.serverName {
+			return
+		}
+
+		d.webhooks.HandleEvent(event)
+	})
+}
+
+// webhooksTask refreshes the webhooks, to pick up changes made on other cluster members, and sends the warnings
+// newly raised on this member to the webhooks subscribed to them.
+func webhooksTask(d *Daemon) (task.Func, task.Schedule) {
+	last := time.Now().UTC()
+
+	f := func(ctx context.Context) {
+		d.webhooksRefresh()
+
+		since := last
+		last = time.Now().UTC()
+
+		if !d.webhooks.Subscribed(webhook.EventTypeWarning) {
+			return
+		}
+
+		var warnings []api.Warning
+		err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			localName, err := tx.GetLocalNodeName(ctx)
+			if err != nil {
+				return err
+			}
+
+			dbWarnings, err := dbCluster.GetWarnings(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			for _, w := range dbWarnings {
+				if w.Node != localName || !w.FirstSeenDate.After(since) {
+					continue
+				}
+
+				warning := w.ToAPI()
+				warning.EntityURL, err = getWarningEntityURL(ctx, tx.Tx(), &w)
+				if err != nil {
+					return err
+				}
+
+				warnings = append(warnings, warning)
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Warn("Failed loading new warnings for webhooks", logger.Ctx{"err": err})
+			return
+		}
+
+		for _, warning := range warnings {
+			metadata, err := json.Marshal(warning)
+			if err != nil {
+				continue
+			}
+
+			d.webhooks.HandleEvent(api.Event{
+				Type:      webhook.EventTypeWarning,
+				Timestamp: warning.FirstSeenAt,
+				Location:  warning.Location,
+				Project:   warning.Project,
+				Metadata:  metadata,
+			})
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
@@ -52,7 +52,9 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance"
 	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/logging"
 	"github.com/lxc/incus/v6/internal/server/loki"
+	"github.com/lxc/incus/v6/internal/server/metrics"
 	"github.com/lxc/incus/v6/internal/server/network/ovn"
 	"github.com/lxc/incus/v6/internal/server/network/ovs"
 	networkZone "github.com/lxc/incus/v6/internal/server/network/zone"
@@ -69,9 +71,11 @@ import (
 	"github.com/lxc/incus/v6/internal/server/sys"
 	"github.com/lxc/incus/v6/internal/server/syslog"
 	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/internal/server/tracing"
 	"github.com/lxc/incus/v6/internal/server/ucred"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/internal/server/warnings"
+	"github.com/lxc/incus/v6/internal/server/webhook"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
@@ -154,7 +158,14 @@ type Daemon struct {
 	serverName      string
 	serverClustered bool
 
-	lokiClient *loki.Client
+	// Remote logging targets.
+	logTargets map[string]logging.Target
+
+	// Tracing.
+	tracingClient *tracing.Client
+
+	// Webhooks.
+	webhooks *webhook.Dispatcher
 
 	// HTTP-01 challenge provider for ACME
 	http01Provider acme.HTTP01Provider
@@ -202,6 +213,7 @@ func newDaemon(config *DaemonConfig, os *sys.OS) *Daemon {
 		shutdownCancel: shutdownCancel,
 		shutdownDoneCh: make(chan error),
 		apiExtensions:  len(version.APIExtensions),
+		logTargets:     map[string]logging.Target{},
 	}
 
 	d.serverCert = func() *localtls.CertInfo { return d.serverCertInt }
@@ -584,6 +596,12 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 	route := restAPI.HandleFunc(uri, func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 
+		// Record the request duration.
+		start := time.Now()
+		defer func() {
+			metrics.APIRequests.Observe(map[string]string{"method": r.Method, "endpoint": uri}, time.Since(start).Seconds())
+		}()
+
 		if !(r.RemoteAddr == "@" && version == "internal") {
 			// Block public API requests until we're done with basic
 			// initialization tasks, such setting up the cluster database.
@@ -703,6 +721,14 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			return
 		}
 
+		// Trace the request, continuing the trace of the cluster member that forwarded it if any.
+		ctx, span := tracing.Start(tracing.Extract(r.Context(), r.Header), r.Method+" "+uri, tracing.KindServer)
+		span.SetAttribute("http.request.method", r.Method)
+		span.SetAttribute("http.route", uri)
+		span.SetAttribute("url.path", r.URL.Path)
+		span.SetAttribute("incus.protocol", protocol)
+		r = r.WithContext(ctx)
+
 		handleRequest := func(action APIEndpointAction) response.Response {
 			if action.Handler == nil {
 				return response.NotImplemented(nil)
@@ -759,6 +785,13 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 				logger.Error("Failed writing error for HTTP response", logger.Ctx{"url": uri, "err": err, "writeErr": writeErr})
 			}
 		}
+
+		span.SetAttribute("http.response.status_code", resp.Code())
+		if resp.Code() >= http.StatusInternalServerError {
+			span.End(fmt.Errorf("%s", http.StatusText(resp.Code())))
+		} else {
+			span.End(err)
+		}
 	})
 
 	// If the endpoint has a canonical name then record it so it can be used to build URLS
@@ -824,14 +857,162 @@ func (d *Daemon) Init() error {
 	return nil
 }
 
+// setLogTarget replaces the remote logging target of the given name, removing it if nil.
+func (d *Daemon) setLogTarget(name string, target logging.Target) {
+	// Stop any existing target.
+	d.internalListener.RemoveHandler(name)
+
+	current := d.logTargets[name]
+	if current != nil {
+		current.Stop()
+		delete(d.logTargets, name)
+	}
+
+	if target == nil {
+		return
+	}
+
+	// Attach the new target to the log handler.
+	d.logTargets[name] = target
+	d.internalListener.AddHandler(name, target.HandleEvent)
+}
+
+// logTargetConfig returns the common configuration of the remote logging targets.
+func (d *Daemon) logTargetConfig(instanceName string, logLevel string, labels []string, types []string) (logging.Config, error) {
+	cfg := logging.Config{
+		Instance: instanceName,
+		LogLevel: logLevel,
+		Labels:   labels,
+		Types:    types,
+	}
+
+	// Handle standalone systems.
+	if !d.serverClustered {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return cfg, err
+		}
+
+		cfg.Location = hostname
+		if cfg.Instance == "" {
+			cfg.Instance = hostname
+		}
+	} else if cfg.Instance == "" {
+		cfg.Instance = d.serverName
+	}
+
+	return cfg, nil
+}
+
 func (d *Daemon) setupLoki(URL string, cert string, key string, caCert string, instanceName string, logLevel string, labels []string, types []string) error {
-	// Stop any existing loki client.
-	if d.lokiClient != nil {
-		d.lokiClient.Stop()
+	// Check basic requirements for starting a new client.
+	if URL == "" || logLevel == "" || len(types) == 0 {
+		d.setLogTarget("loki", nil)
+		return nil
+	}
+
+	// Validate the URL.
+	u, err := url.Parse(URL)
+	if err != nil {
+		return err
 	}
 
+	cfg, err := d.logTargetConfig(instanceName, logLevel, labels, types)
+	if err != nil {
+		return err
+	}
+
+	// Start a new client.
+	d.setLogTarget("loki", loki.NewClient(d.shutdownCtx, u, cert, key, caCert, cfg))
+
+	return nil
+}
+
+func (d *Daemon) setupLoggingOTLP(URL string, caCert string, headers map[string]string, instanceName string, logLevel string, labels []string, types []string) error {
 	// Check basic requirements for starting a new client.
 	if URL == "" || logLevel == "" || len(types) == 0 {
+		d.setLogTarget("otlp", nil)
+		return nil
+	}
+
+	// Validate the URL.
+	u, err := url.Parse(URL)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := d.logTargetConfig(instanceName, logLevel, labels, types)
+	if err != nil {
+		return err
+	}
+
+	// Start a new client.
+	client, err := logging.NewOTLPClient(d.shutdownCtx, u, caCert, headers, cfg)
+	if err != nil {
+		return err
+	}
+
+	d.setLogTarget("otlp", client)
+
+	return nil
+}
+
+func (d *Daemon) setupLoggingSyslog(address string, protocol string, caCert string, instanceName string, logLevel string, labels []string, types []string) error {
+	// Check basic requirements for starting a new client.
+	if address == "" || logLevel == "" || len(types) == 0 {
+		d.setLogTarget("syslog", nil)
+		return nil
+	}
+
+	cfg, err := d.logTargetConfig(instanceName, logLevel, labels, types)
+	if err != nil {
+		return err
+	}
+
+	// Start a new client.
+	client, err := logging.NewSyslogClient(d.shutdownCtx, address, protocol, caCert, cfg)
+	if err != nil {
+		return err
+	}
+
+	d.setLogTarget("syslog", client)
+
+	return nil
+}
+
+func (d *Daemon) setupLoggingGELF(address string, protocol string, caCert string, instanceName string, logLevel string, labels []string, types []string) error {
+	// Check basic requirements for starting a new client.
+	if address == "" || logLevel == "" || len(types) == 0 {
+		d.setLogTarget("gelf", nil)
+		return nil
+	}
+
+	cfg, err := d.logTargetConfig(instanceName, logLevel, labels, types)
+	if err != nil {
+		return err
+	}
+
+	// Start a new client.
+	client, err := logging.NewGELFClient(d.shutdownCtx, address, protocol, caCert, cfg)
+	if err != nil {
+		return err
+	}
+
+	d.setLogTarget("gelf", client)
+
+	return nil
+}
+
+func (d *Daemon) setupTracing(URL string, caCert string, headers map[string]string, sampleRatio float64) error {
+	// Stop any existing tracing client.
+	tracing.SetClient(nil)
+	if d.tracingClient != nil {
+		d.tracingClient.Stop()
+		d.tracingClient = nil
+	}
+
+	// Check basic requirements for starting a new client.
+	if URL == "" {
 		return nil
 	}
 
@@ -842,26 +1023,21 @@ func (d *Daemon) setupLoki(URL string, cert string, key string, caCert string, i
 	}
 
 	// Handle standalone systems.
-	var location string
+	location := d.serverName
 	if !d.serverClustered {
-		hostname, err := os.Hostname()
+		location, err = os.Hostname()
 		if err != nil {
 			return err
 		}
-
-		location = hostname
-		if instanceName == "" {
-			instanceName = hostname
-		}
-	} else if instanceName == "" {
-		instanceName = d.serverName
 	}
 
 	// Start a new client.
-	d.lokiClient = loki.NewClient(d.shutdownCtx, u, cert, key, caCert, instanceName, location, logLevel, labels, types)
+	d.tracingClient, err = tracing.NewClient(d.shutdownCtx, u, caCert, headers, location, sampleRatio)
+	if err != nil {
+		return err
+	}
 
-	// Attach the new client to the log handler.
-	d.internalListener.AddHandler("loki", d.lokiClient.HandleEvent)
+	tracing.SetClient(d.tracingClient)
 
 	return nil
 }
@@ -1402,6 +1578,11 @@ func (d *Daemon) init() error {
 
 	d.events.SetLocalLocation(d.serverName)
 
+	// Record the lifecycle events in the event history so that they can be replayed.
+	d.events.SetHistory([]string{api.EventTypeLifecycle}, func(event api.Event) (int64, error) {
+		return eventsHistoryRecord(d.State(), event)
+	})
+
 	// Get daemon configuration.
 	bgpAddress := d.localConfig.BGPAddress()
 	bgpRouterID := d.localConfig.BGPRouterID()
@@ -1416,6 +1597,10 @@ func (d *Daemon) init() error {
 
 	d.gateway.HeartbeatOfflineThreshold = d.globalConfig.OfflineThreshold()
 	lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes := d.globalConfig.LokiServer()
+	tracingURL, tracingCACert, tracingHeaders, tracingSampleRatio := d.globalConfig.TracingServer()
+	otlpURL, otlpCACert, otlpHeaders, otlpInstance, otlpLoglevel, otlpLabels, otlpTypes := d.globalConfig.LoggingOTLP()
+	syslogAddress, syslogProtocol, syslogCACert, syslogInstance, syslogLoglevel, syslogLabels, syslogTypes := d.globalConfig.LoggingSyslog()
+	gelfAddress, gelfProtocol, gelfCACert, gelfInstance, gelfLoglevel, gelfLabels, gelfTypes := d.globalConfig.LoggingGELF()
 	oidcIssuer, oidcClientID, oidcAudience, oidcClaim := d.globalConfig.OIDCServer()
 	syslogSocketEnabled := d.localConfig.SyslogSocket()
 	openfgaAPIURL, openfgaAPIToken, openfgaStoreID := d.globalConfig.OpenFGA()
@@ -1432,6 +1617,40 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Setup the other remote logging targets.
+	if otlpURL != "" {
+		err = d.setupLoggingOTLP(otlpURL, otlpCACert, otlpHeaders, otlpInstance, otlpLoglevel, otlpLabels, otlpTypes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if syslogAddress != "" {
+		err = d.setupLoggingSyslog(syslogAddress, syslogProtocol, syslogCACert, syslogInstance, syslogLoglevel, syslogLabels, syslogTypes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if gelfAddress != "" {
+		err = d.setupLoggingGELF(gelfAddress, gelfProtocol, gelfCACert, gelfInstance, gelfLoglevel, gelfLabels, gelfTypes)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Setup tracing.
+	if tracingURL != "" {
+		err = d.setupTracing(tracingURL, tracingCACert, tracingHeaders, tracingSampleRatio)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Setup webhooks.
+	d.webhooks = webhook.NewDispatcher(d.shutdownCtx, d.webhooksLoad, d.webhooksDeadLetter)
+	d.webhooksRefresh()
+
 	// Setup syslog listener.
 	if syslogSocketEnabled {
 		err = d.setupSyslogSocket(true)
@@ -1602,11 +1821,11 @@ func (d *Daemon) init() error {
 	}
 
 	err = d.db.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		// Remove volatile.last_state.ready key as we don't know if the instances are ready.
+		// Remove volatile.last_state.ready and volatile.last_state.health keys as we don't know if the instances are ready.
 		return tx.DeleteReadyStateFromLocalInstances(ctx)
 	})
 	if err != nil {
-		return fmt.Errorf("Failed deleting volatile.last_state.ready: %w", err)
+		return fmt.Errorf("Failed deleting ready state: %w", err)
 	}
 
 	close(d.setupChan)
@@ -1670,6 +1889,18 @@ func (d *Daemon) init() error {
 
 		// Remove expired tokens (hourly)
 		d.tasks.Add(autoRemoveExpiredTokensTask(d))
+
+		// Synchronize VM clocks after host suspend (every 10s)
+		d.tasks.Add(instancesClockSyncTask(d))
+
+		// Sample the resource usage of the projects (every 5 minutes)
+		d.tasks.Add(projectsUsageTask(d))
+
+		// Remove expired project usage records (daily)
+		d.tasks.Add(pruneProjectsUsageTask(d))
+
+		// Refresh the webhooks and send them the new warnings (minutely)
+		d.tasks.Add(webhooksTask(d))
 	}
 
 	// Start all background tasks
@@ -16,6 +16,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/locking"
+	"github.com/lxc/incus/v6/internal/server/metrics"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
@@ -420,6 +421,8 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 			return nil, err
 		}
 
+		metrics.ImageDownloadBytes.Add(map[string]string{"protocol": protocol}, float64(resp.MetaSize+resp.RootfsSize))
+
 		// Truncate down to size
 		if resp.RootfsSize > 0 {
 			err = destRootfs.Truncate(resp.RootfsSize)
@@ -509,6 +512,8 @@ func ImageDownload(ctx context.Context, r *http.Request, s *state.State, op *ope
 			return nil, err
 		}
 
+		metrics.ImageDownloadBytes.Add(map[string]string{"protocol": protocol}, float64(size))
+
 		// Validate hash
 		result := fmt.Sprintf("%x", sha256.Sum(nil))
 		if result != fp {
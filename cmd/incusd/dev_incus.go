@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
@@ -16,6 +17,7 @@ import (
 	"golang.org/x/sys/unix"
 
 	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/events"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
@@ -24,6 +26,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/internal/server/ucred"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	apiGuest "github.com/lxc/incus/v6/shared/api/guest"
@@ -81,6 +84,10 @@ var devIncusConfigKeyGet = devIncusHandler{"/1.0/config/{key}", func(d *Daemon,
 		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusBadRequest, "bad request"), c.Type() == instancetype.VM)
 	}
 
+	if r.Method == "PUT" || r.Method == "DELETE" {
+		return devIncusConfigKeySet(d, c, key, r)
+	}
+
 	if !strings.HasPrefix(key, "user.") && !strings.HasPrefix(key, "cloud-init.") {
 		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusForbidden, "not authorized"), c.Type() == instancetype.VM)
 	}
@@ -93,6 +100,94 @@ var devIncusConfigKeyGet = devIncusHandler{"/1.0/config/{key}", func(d *Daemon,
 	return response.DevIncusResponse(http.StatusOK, value, "raw", c.Type() == instancetype.VM)
 }}
 
+// devIncusConfigValueMaxSize is the largest value the instance may store in a user.guest.* key.
+const devIncusConfigValueMaxSize = 64 * 1024
+
+// devIncusConfigMaxKeys is the largest number of user.guest.* keys the instance may store.
+const devIncusConfigMaxKeys = 64
+
+// devIncusConfigMaxTotalSize is the largest combined size of the names and values of the user.guest.* keys.
+const devIncusConfigMaxTotalSize = 256 * 1024
+
+// devIncusConfigKeySet sets (PUT) or removes (DELETE) a user.guest.* key on behalf of the instance.
+// The request body of a PUT is the raw value.
+func devIncusConfigKeySet(d *Daemon, c instance.Instance, key string, r *http.Request) response.Response {
+	if !strings.HasPrefix(key, "user.guest.") || key == "user.guest." {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusForbidden, "not authorized"), c.Type() == instancetype.VM)
+	}
+
+	// Hold the instance lock while updating the configuration so concurrent changes aren't lost.
+	unlock, err := instanceOperationLock(r.Context(), c.Project().Name, c.Name())
+	if err != nil {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusInternalServerError, "internal server error"), c.Type() == instancetype.VM)
+	}
+
+	defer unlock()
+
+	// Reload the instance to get its current configuration.
+	inst, err := instance.LoadByProjectAndName(d.State(), c.Project().Name, c.Name())
+	if err != nil {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusInternalServerError, "internal server error"), c.Type() == instancetype.VM)
+	}
+
+	c = inst
+
+	config := localUtil.CopyConfig(c.LocalConfig())
+
+	if r.Method == "PUT" {
+		value, err := io.ReadAll(io.LimitReader(r.Body, devIncusConfigValueMaxSize+1))
+		if err != nil {
+			return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusBadRequest, "bad request"), c.Type() == instancetype.VM)
+		}
+
+		if len(value) > devIncusConfigValueMaxSize {
+			return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusRequestEntityTooLarge, "value too large"), c.Type() == instancetype.VM)
+		}
+
+		config[key] = string(value)
+
+		keys := 0
+		size := 0
+		for k, v := range config {
+			if strings.HasPrefix(k, "user.guest.") {
+				keys++
+				size += len(k) + len(v)
+			}
+		}
+
+		if keys > devIncusConfigMaxKeys || size > devIncusConfigMaxTotalSize {
+			return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusRequestEntityTooLarge, "too many keys"), c.Type() == instancetype.VM)
+		}
+	} else {
+		_, ok := config[key]
+		if !ok {
+			return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusNotFound, "not found"), c.Type() == instancetype.VM)
+		}
+
+		delete(config, key)
+	}
+
+	args := db.InstanceArgs{
+		Architecture: c.Architecture(),
+		Config:       config,
+		Description:  c.Description(),
+		Devices:      c.LocalDevices(),
+		Ephemeral:    c.IsEphemeral(),
+		Profiles:     c.Profiles(),
+		Project:      c.Project().Name,
+		ExpiryDate:   c.ExpiryDate(),
+	}
+
+	err = c.Update(args, false)
+	if err != nil {
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusInternalServerError, "internal server error"), c.Type() == instancetype.VM)
+	}
+
+	d.State().Events.SendLifecycle(c.Project().Name, lifecycle.InstanceUpdated.Event(c, nil))
+
+	return response.DevIncusResponse(http.StatusOK, "", "raw", c.Type() == instancetype.VM)
+}
+
 var devIncusImageExport = devIncusHandler{"/1.0/images/{fingerprint}/export", func(d *Daemon, c instance.Instance, w http.ResponseWriter, r *http.Request) response.Response {
 	if util.IsFalse(c.ExpandedConfig()["security.guestapi"]) {
 		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusForbidden, "not authorized"), c.Type() == instancetype.VM)
@@ -206,7 +301,7 @@ var devIncusAPIHandler = devIncusHandler{"/1.0", func(d *Daemon, c instance.Inst
 			state = api.Started
 		}
 
-		return response.DevIncusResponse(http.StatusOK, apiGuest.DevIncusGet{APIVersion: version.APIVersion, Location: location, InstanceType: c.Type().String(), DevIncusPut: apiGuest.DevIncusPut{State: state.String()}}, "json", c.Type() == instancetype.VM)
+		return response.DevIncusResponse(http.StatusOK, apiGuest.DevIncusGet{APIVersion: version.APIVersion, Location: location, InstanceType: c.Type().String(), DevIncusPut: apiGuest.DevIncusPut{State: state.String(), Health: c.LocalConfig()["volatile.last_state.health"]}}, "json", c.Type() == instancetype.VM)
 	} else if r.Method == "PATCH" {
 		if util.IsFalse(c.ExpandedConfig()["security.guestapi"]) {
 			return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusForbidden, "not authorized"), c.Type() == instancetype.VM)
@@ -219,13 +314,31 @@ var devIncusAPIHandler = devIncusHandler{"/1.0", func(d *Daemon, c instance.Inst
 			return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusBadRequest, err.Error()), c.Type() == instancetype.VM)
 		}
 
+		if req.State == "" && req.Health == "" {
+			return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusBadRequest, "Either state or health must be provided"), c.Type() == instancetype.VM)
+		}
+
+		volatile := map[string]string{}
+
 		state := api.StatusCodeFromString(req.State)
+		if req.State != "" {
+			if state != api.Started && state != api.Ready {
+				return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusBadRequest, "Invalid state %q", req.State), c.Type() == instancetype.VM)
+			}
 
-		if state != api.Started && state != api.Ready {
-			return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusBadRequest, "Invalid state %q", req.State), c.Type() == instancetype.VM)
+			volatile["volatile.last_state.ready"] = strconv.FormatBool(state == api.Ready)
 		}
 
-		err = c.VolatileSet(map[string]string{"volatile.last_state.ready": strconv.FormatBool(state == api.Ready)})
+		oldHealth := c.LocalConfig()["volatile.last_state.health"]
+		if req.Health != "" {
+			if req.Health != "healthy" && req.Health != "unhealthy" {
+				return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusBadRequest, "Invalid health %q", req.Health), c.Type() == instancetype.VM)
+			}
+
+			volatile["volatile.last_state.health"] = req.Health
+		}
+
+		err = c.VolatileSet(volatile)
 		if err != nil {
 			return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusInternalServerError, err.Error()), c.Type() == instancetype.VM)
 		}
@@ -234,6 +347,10 @@ var devIncusAPIHandler = devIncusHandler{"/1.0", func(d *Daemon, c instance.Inst
 			s.Events.SendLifecycle(c.Project().Name, lifecycle.InstanceReady.Event(c, nil))
 		}
 
+		if req.Health != "" && req.Health != oldHealth {
+			s.Events.SendLifecycle(c.Project().Name, lifecycle.InstanceHealthChanged.Event(c, map[string]any{"health": req.Health}))
+		}
+
 		return response.DevIncusResponse(http.StatusOK, "", "raw", c.Type() == instancetype.VM)
 	}
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/lxc/incus/v6/internal/server/auth"
@@ -20,8 +21,8 @@ import (
 	"github.com/lxc/incus/v6/shared/ws"
 )
 
-var eventTypes = []string{api.EventTypeLogging, api.EventTypeOperation, api.EventTypeLifecycle, api.EventTypeNetworkACL}
-var privilegedEventTypes = []string{api.EventTypeLogging}
+var eventTypes = []string{api.EventTypeLogging, api.EventTypeOperation, api.EventTypeLifecycle, api.EventTypeNetworkACL, api.EventTypeInstanceLog}
+var privilegedEventTypes = []string{api.EventTypeLogging, api.EventTypeInstanceLog}
 
 var eventsCmd = APIEndpoint{
 	Path: "events",
@@ -100,8 +101,21 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 		}
 	}
 
-	if slices.Contains(types, api.EventTypeLogging) && !canViewPrivilegedEvents {
-		return api.StatusErrorf(http.StatusForbidden, "Forbidden")
+	for _, entry := range privilegedEventTypes {
+		if slices.Contains(types, entry) && !canViewPrivilegedEvents {
+			return api.StatusErrorf(http.StatusForbidden, "Forbidden")
+		}
+	}
+
+	// Check whether the events recorded since a given event are to be replayed.
+	since := int64(-1)
+	if r.FormValue("since") != "" && !isClusterNotification(r) {
+		var err error
+
+		since, err = strconv.ParseInt(r.FormValue("since"), 10, 64)
+		if err != nil || since < 0 {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid event ID %q", r.FormValue("since"))
+		}
 	}
 
 	l := logger.AddContext(logger.Ctx{"remote": r.RemoteAddr})
@@ -160,12 +174,55 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 	defer func() { _ = conn.Close() }() // Ensure listener below ends when this function ends.
 
 	listenerConnection := events.NewWebsocketListenerConnection(conn)
+
+	// Hold back the new events while replaying the recorded ones.
+	var replayConnection *events.ReplayListenerConnection
+	if since >= 0 {
+		replayConnection = events.NewReplayListenerConnection(listenerConnection)
+		listenerConnection = replayConnection
+	}
+
 	listener, err := s.Events.AddListener(projectName, allProjects, projectPermissionFunc, listenerConnection, types, excludeSources, recvFunc, excludeLocations)
 	if err != nil {
 		l.Warn("Failed to add event listener", logger.Ctx{"err": err})
 		return nil
 	}
 
+	if replayConnection != nil {
+		var history []api.Event
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			history, err = tx.GetEvents(ctx, since)
+			return err
+		})
+		if err != nil {
+			l.Warn("Failed loading the event history", logger.Ctx{"err": err})
+		}
+
+		replay := []api.Event{}
+		for _, event := range history {
+			if event.Project != "" && !allProjects && event.Project != projectName {
+				continue
+			}
+
+			if event.Project != "" && projectPermissionFunc != nil && !projectPermissionFunc(auth.ObjectProject(event.Project)) {
+				continue
+			}
+
+			if !slices.Contains(types, event.Type) {
+				continue
+			}
+
+			replay = append(replay, event)
+		}
+
+		err = replayConnection.Replay(replay)
+		if err != nil {
+			l.Debug("Failed replaying the event history", logger.Ctx{"err": err})
+			listener.Close()
+			return nil
+		}
+	}
+
 	listener.Wait(r.Context())
 
 	return nil
@@ -195,6 +252,11 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 //	    name: all-projects
 //	    description: Retrieve instances from all projects
 //	    type: boolean
+//	  - in: query
+//	    name: since
+//	    description: Replay the recorded events with a greater ID before streaming new events
+//	    type: integer
+//	    example: 1234
 //	responses:
 //	  "200":
 //	    description: Websocket message (JSON)
@@ -207,3 +269,33 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 func eventsGet(d *Daemon, r *http.Request) response.Response {
 	return &eventsServe{req: r, s: d.State()}
 }
+
+// eventsHistoryRecord records an event in the event history and returns its ID, or 0 if the history is disabled.
+func eventsHistoryRecord(s *state.State, event api.Event) (int64, error) {
+	size := s.GlobalConfig.EventsHistorySize()
+	if size <= 0 {
+		return 0, nil
+	}
+
+	var id int64
+	err := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		id, err = tx.CreateEvent(ctx, event)
+		if err != nil {
+			return err
+		}
+
+		// Remove the oldest events every now and then rather than for every new event.
+		if id%100 == 0 {
+			return tx.DeleteOldEvents(ctx, size)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
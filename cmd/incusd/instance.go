@@ -814,3 +814,24 @@ func instanceOperationLock(ctx context.Context, projectName string, instanceName
 
 	return locking.Lock(ctx, fmt.Sprintf("InstanceOperation_%s", project.Instance(projectName, instanceName)))
 }
+
+// instanceCheckAccessRestriction returns an error if the project forbids the user the interactive access to
+// its instances controlled by the given restriction key.
+// It must be called before forwarding the request, as forwarded requests aren't subject to restrictions.
+func instanceCheckAccessRestriction(s *state.State, r *http.Request, projectName string, restrictionKey string, write bool) error {
+	var p *api.Project
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		p, err = dbProject.ToAPI(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return project.CheckInstanceAccessRestriction(s.Authorizer, r, p, restrictionKey, write)
+}
@@ -224,6 +224,17 @@ func (s *consoleWs) doConsole(op *operations.Operation) error {
 		_ = linux.SetPtySize(int(console.Fd()), s.width, s.height)
 	}
 
+	recorder, err := instanceSessionRecorder(s.instance, op, nil, s.width, s.height)
+	if err != nil {
+		return err
+	}
+
+	var terminal io.ReadWriteCloser = console
+	if recorder != nil {
+		defer func() { _ = recorder.Close() }()
+		terminal = recorder.ReadWriteCloser(console)
+	}
+
 	consoleDoneCh := make(chan struct{})
 
 	// Wait for control socket to connect and then read messages from the remote side in a loop.
@@ -279,6 +290,10 @@ func (s *consoleWs) doConsole(op *operations.Operation) error {
 					continue
 				}
 
+				if recorder != nil {
+					_ = recorder.Resize(winchWidth, winchHeight)
+				}
+
 				logger.Debugf("Set window size to: %dx%d", winchWidth, winchHeight)
 			}
 		}
@@ -295,7 +310,7 @@ func (s *consoleWs) doConsole(op *operations.Operation) error {
 		defer l.Debug("Finished mirroring websocket to console")
 
 		l.Debug("Started mirroring websocket")
-		readDone, writeDone := ws.Mirror(conn, console)
+		readDone, writeDone := ws.Mirror(conn, terminal)
 
 		<-readDone
 		l.Debug("Finished mirroring console to websocket")
@@ -450,6 +465,11 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = instanceCheckAccessRestriction(s, r, projectName, "restricted.console", true)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Forward the request if the container is remote.
 	client, err := cluster.ConnectIfInstanceIsRemote(s, projectName, name, r, instanceType)
 	if err != nil {
@@ -577,6 +597,11 @@ func instanceConsoleLogGet(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Invalid instance name"))
 	}
 
+	err = instanceCheckAccessRestriction(s, r, projectName, "restricted.console", false)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Forward the request if the container is remote.
 	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name, instanceType)
 	if err != nil {
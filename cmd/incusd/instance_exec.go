@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
@@ -20,6 +21,7 @@ import (
 	"github.com/gorilla/websocket"
 	"golang.org/x/sys/unix"
 
+	"github.com/lxc/incus/v6/internal/asciicast"
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/jmap"
 	"github.com/lxc/incus/v6/internal/linux"
@@ -57,9 +59,31 @@ type execWs struct {
 	waitControlConnected  *cancel.Canceller
 	fds                   map[int]string
 	s                     *state.State
+
+	// Only set for detachable sessions.
+	session *execSession
+
+	// Only set for interactive sessions which must be recorded.
+	recorder *asciicast.Writer
 }
 
 func (s *execWs) Metadata() any {
+	metadata := jmap.Map{
+		"fds":         s.fdsMetadata(),
+		"command":     s.req.Command,
+		"environment": s.req.Environment,
+		"interactive": s.req.Interactive,
+	}
+
+	if s.req.Detachable {
+		metadata["detachable"] = true
+	}
+
+	return metadata
+}
+
+// fdsMetadata returns the websocket secrets to be recorded in the operation metadata.
+func (s *execWs) fdsMetadata() jmap.Map {
 	fds := jmap.Map{}
 	for fd, secret := range s.fds {
 		if fd == execWSControl {
@@ -69,12 +93,7 @@ func (s *execWs) Metadata() any {
 		}
 	}
 
-	return jmap.Map{
-		"fds":         fds,
-		"command":     s.req.Command,
-		"environment": s.req.Environment,
-		"interactive": s.req.Interactive,
-	}
+	return fds
 }
 
 func (s *execWs) Connect(op *operations.Operation, r *http.Request, w http.ResponseWriter) error {
@@ -83,7 +102,20 @@ func (s *execWs) Connect(op *operations.Operation, r *http.Request, w http.Respo
 		return fmt.Errorf("missing secret")
 	}
 
-	for fd, fdSecret := range s.fds {
+	if s.session != nil {
+		// Detachable sessions can be re-attached to by other clients, so check they may run commands.
+		err := instanceCheckAccessRestriction(s.s, r, s.instance.Project().Name, "restricted.exec", true)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The secrets of detachable sessions get rotated when their websockets disconnect.
+	s.connsLock.Lock()
+	fds := maps.Clone(s.fds)
+	s.connsLock.Unlock()
+
+	for fd, fdSecret := range fds {
 		if secret == fdSecret {
 			conn, err := ws.Upgrader.Upgrade(w, r, nil)
 			if err != nil {
@@ -126,6 +158,11 @@ func (s *execWs) Connect(op *operations.Operation, r *http.Request, w http.Respo
 					s.waitControlConnected.Cancel() // Control connection connected.
 				}
 
+				if s.session != nil {
+					// Serve clients re-attaching to a running session.
+					go s.session.attach(fd, conn)
+				}
+
 				for i, c := range s.conns {
 					if i == execWSControl && s.req.WaitForWS && !s.req.Interactive {
 						// Due to a historical bug in the LXC CLI command, we cannot force
@@ -285,6 +322,14 @@ func (s *execWs) Do(op *operations.Operation) error {
 
 		wgEOF.Wait()
 
+		if s.session != nil {
+			s.session.finish()
+		}
+
+		if s.recorder != nil {
+			_ = s.recorder.Close()
+		}
+
 		for _, pty := range ptys {
 			_ = pty.Close()
 		}
@@ -305,6 +350,20 @@ func (s *execWs) Do(op *operations.Operation) error {
 		return cmdErr
 	}
 
+	if s.req.Interactive {
+		s.recorder, err = instanceSessionRecorder(s.instance, op, s.req.Command, s.req.Width, s.req.Height)
+		if err != nil {
+			return finisher(-1, err)
+		}
+	}
+
+	if s.session != nil {
+		err = s.session.prepare(op)
+		if err != nil {
+			return finisher(-1, err)
+		}
+	}
+
 	cmd, err := s.instance.Exec(s.req, stdin, stdout, stderr)
 	if err != nil {
 		return finisher(-1, err)
@@ -323,6 +382,28 @@ func (s *execWs) Do(op *operations.Operation) error {
 		}
 	}
 
+	if s.session != nil {
+		// Relay everything through the session so the command survives the websockets disconnecting.
+		stdinWriter := ttys[execWSStdin]
+		outputs := map[int]io.Reader{}
+		if !s.req.Interactive {
+			outputs[execWSStdout] = linux.NewExecWrapper(waitAttachedChildIsDead, ptys[execWSStdout])
+			outputs[execWSStderr] = linux.NewExecWrapper(waitAttachedChildIsDead, ptys[execWSStderr])
+		} else if s.instance.Type() == instancetype.Container {
+			stdinWriter = ptys[0]
+			outputs[0] = s.recordOutput(linux.NewExecWrapper(waitAttachedChildIsDead, ptys[0]))
+		} else {
+			outputs[0] = s.recordOutput(ptys[execWSStdout])
+		}
+
+		s.session.run(l, cmd, stdinWriter, ptys[0], outputs, &wgEOF)
+
+		exitStatus, err := cmd.Wait()
+		l.Debug("Instance process stopped", logger.Ctx{"err": err, "exitStatus": exitStatus})
+
+		return finisher(exitStatus, err)
+	}
+
 	// Now that process has started, we can start the control handler.
 	wgEOF.Add(1)
 	go func() {
@@ -374,40 +455,7 @@ func (s *execWs) Do(op *operations.Operation) error {
 				return
 			}
 
-			command := api.InstanceExecControl{}
-
-			err = json.Unmarshal(buf, &command)
-			if err != nil {
-				l.Debug("Failed to unmarshal control socket command", logger.Ctx{"err": err})
-				continue
-			}
-
-			// Only handle window-resize requests for interactive sessions.
-			if command.Command == "window-resize" && s.req.Interactive {
-				winchWidth, err := strconv.Atoi(command.Args["width"])
-				if err != nil {
-					l.Debug("Unable to extract window width", logger.Ctx{"err": err})
-					continue
-				}
-
-				winchHeight, err := strconv.Atoi(command.Args["height"])
-				if err != nil {
-					l.Debug("Unable to extract window height", logger.Ctx{"err": err})
-					continue
-				}
-
-				err = cmd.WindowResize(int(ptys[0].Fd()), winchWidth, winchHeight)
-				if err != nil {
-					l.Debug("Failed to set window size", logger.Ctx{"err": err, "width": winchWidth, "height": winchHeight})
-					continue
-				}
-			} else if command.Command == "signal" {
-				err := cmd.Signal(unix.Signal(command.Signal))
-				if err != nil {
-					l.Debug("Failed forwarding signal", logger.Ctx{"err": err, "signal": command.Signal})
-					continue
-				}
-			}
+			s.handleControl(l, cmd, ptys[0], buf)
 		}
 	}()
 
@@ -431,9 +479,9 @@ func (s *execWs) Do(op *operations.Operation) error {
 			if s.instance.Type() == instancetype.Container {
 				// For containers, we are running the command via the locally managed PTY and so
 				// need to use the same PTY handle for both read and write.
-				readDone, writeDone = ws.Mirror(conn, linux.NewExecWrapper(waitAttachedChildIsDead, ptys[0]))
+				readDone, writeDone = ws.Mirror(conn, s.recordTerminal(linux.NewExecWrapper(waitAttachedChildIsDead, ptys[0])))
 			} else {
-				readDone = ws.MirrorRead(conn, ptys[execWSStdout])
+				readDone = ws.MirrorRead(conn, s.recordOutput(ptys[execWSStdout]))
 				writeDone = ws.MirrorWrite(conn, ttys[execWSStdin])
 			}
 
@@ -496,6 +544,66 @@ func (s *execWs) Do(op *operations.Operation) error {
 	return finisher(exitStatus, err)
 }
 
+// recordOutput returns a reader recording the terminal output read from r if the session is recorded.
+func (s *execWs) recordOutput(r io.Reader) io.Reader {
+	if s.recorder == nil {
+		return r
+	}
+
+	return s.recorder.Reader(r)
+}
+
+// recordTerminal returns a terminal recording the output read from rwc if the session is recorded.
+func (s *execWs) recordTerminal(rwc io.ReadWriteCloser) io.ReadWriteCloser {
+	if s.recorder == nil {
+		return rwc
+	}
+
+	return s.recorder.ReadWriteCloser(rwc)
+}
+
+// handleControl applies a message received on the control websocket to the running command.
+func (s *execWs) handleControl(l logger.Logger, cmd instance.Cmd, pty *os.File, buf []byte) {
+	command := api.InstanceExecControl{}
+
+	err := json.Unmarshal(buf, &command)
+	if err != nil {
+		l.Debug("Failed to unmarshal control socket command", logger.Ctx{"err": err})
+		return
+	}
+
+	// Only handle window-resize requests for interactive sessions.
+	if command.Command == "window-resize" && s.req.Interactive {
+		winchWidth, err := strconv.Atoi(command.Args["width"])
+		if err != nil {
+			l.Debug("Unable to extract window width", logger.Ctx{"err": err})
+			return
+		}
+
+		winchHeight, err := strconv.Atoi(command.Args["height"])
+		if err != nil {
+			l.Debug("Unable to extract window height", logger.Ctx{"err": err})
+			return
+		}
+
+		err = cmd.WindowResize(int(pty.Fd()), winchWidth, winchHeight)
+		if err != nil {
+			l.Debug("Failed to set window size", logger.Ctx{"err": err, "width": winchWidth, "height": winchHeight})
+			return
+		}
+
+		if s.recorder != nil {
+			_ = s.recorder.Resize(winchWidth, winchHeight)
+		}
+	} else if command.Command == "signal" {
+		err := cmd.Signal(unix.Signal(command.Signal))
+		if err != nil {
+			l.Debug("Failed forwarding signal", logger.Ctx{"err": err, "signal": command.Signal})
+			return
+		}
+	}
+}
+
 // swagger:operation POST /1.0/instances/{name}/exec instances instance_exec_post
 //
 //	Run a command
@@ -572,6 +680,15 @@ func instanceExecPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Cannot use %q in combination with %q", "interactive", "record-output"))
 	}
 
+	if post.Detachable && !post.WaitForWS {
+		return response.BadRequest(fmt.Errorf("Cannot use %q without %q", "detachable", "wait-for-websocket"))
+	}
+
+	err = instanceCheckAccessRestriction(s, r, projectName, "restricted.exec", true)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Forward the request if the container is remote.
 	client, err := cluster.ConnectIfInstanceIsRemote(s, projectName, name, r, instanceType)
 	if err != nil {
@@ -693,10 +810,19 @@ func instanceExecPost(d *Daemon, r *http.Request) response.Response {
 		ws.instance = inst
 		ws.req = post
 
+		// Detachable sessions can be cancelled to kill the command.
+		var onCancel func(*operations.Operation) error
+		if post.Detachable {
+			ws.session = &execSession{ws: ws}
+			onCancel = func(op *operations.Operation) error {
+				return ws.session.cancel()
+			}
+		}
+
 		resources := map[string][]api.URL{}
 		resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", ws.instance.Name())}
 
-		op, err := operations.OperationCreate(s, projectName, operations.OperationClassWebsocket, operationtype.CommandExec, resources, ws.Metadata(), ws.Do, nil, ws.Connect, r)
+		op, err := operations.OperationCreate(s, projectName, operations.OperationClassWebsocket, operationtype.CommandExec, resources, ws.Metadata(), ws.Do, onCancel, ws.Connect, r)
 		if err != nil {
 			return response.InternalError(err)
 		}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/jmap"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/ws"
+)
+
+// execSession keeps a detachable command running when its websockets disconnect.
+// The output of the command is recorded to the exec-output files of the instance and every attached
+// websocket follows those files, so clients re-attaching get the full output before the live one.
+type execSession struct {
+	ws *execWs
+	op *operations.Operation
+	l  logger.Logger
+
+	cmd   instance.Cmd
+	stdin *os.File
+	pty   *os.File
+
+	// Output file for each websocket number.
+	outputs map[int]*os.File
+
+	mu          sync.Mutex
+	running     bool
+	done        bool
+	stdinClosed bool
+	changed     chan struct{}
+	attached    map[int]*websocket.Conn
+	wgFollowers sync.WaitGroup
+}
+
+// prepare creates the output files of the session and records their URLs in the operation metadata.
+func (s *execSession) prepare(op *operations.Operation) error {
+	s.op = op
+	s.changed = make(chan struct{})
+	s.attached = map[int]*websocket.Conn{}
+	s.outputs = map[int]*os.File{}
+
+	execOutputDir := s.ws.instance.ExecOutputPath()
+	err := os.Mkdir(execOutputDir, 0600)
+	if err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+
+	// Interactive sessions only have a single output stream.
+	streams := map[int]string{execWSStdout: "stdout", execWSStderr: "stderr"}
+	if s.ws.req.Interactive {
+		streams = map[int]string{0: "stdout"}
+	}
+
+	urls := jmap.Map{}
+	for number, name := range streams {
+		f, err := os.OpenFile(filepath.Join(execOutputDir, fmt.Sprintf("exec_%s.%s", op.ID(), name)), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			s.close()
+			return err
+		}
+
+		s.outputs[number] = f
+
+		key := "1"
+		if name == "stderr" {
+			key = "2"
+		}
+
+		urls[key] = fmt.Sprintf("/%s/instances/%s/logs/exec-output/%s", version.APIVersion, s.ws.instance.Name(), filepath.Base(f.Name()))
+	}
+
+	return op.ExtendMetadata(jmap.Map{"output": urls})
+}
+
+// close closes the output files.
+func (s *execSession) close() {
+	for _, f := range s.outputs {
+		_ = f.Close()
+	}
+}
+
+// run starts recording the output of the command and attaches the websockets which are already connected.
+func (s *execSession) run(l logger.Logger, cmd instance.Cmd, stdin *os.File, pty *os.File, outputs map[int]io.Reader, wg *sync.WaitGroup) {
+	s.mu.Lock()
+	s.l = l
+	s.cmd = cmd
+	s.stdin = stdin
+	s.pty = pty
+	s.running = true
+	s.mu.Unlock()
+
+	for number, r := range outputs {
+		wg.Add(1)
+		go func(f *os.File, r io.Reader) {
+			defer wg.Done()
+
+			err := s.record(f, r)
+			if err != nil {
+				l.Warn("Failed recording exec output", logger.Ctx{"err": err, "file": f.Name()})
+			}
+		}(s.outputs[number], r)
+	}
+
+	s.ws.connsLock.Lock()
+	conns := map[int]*websocket.Conn{}
+	for number, conn := range s.ws.conns {
+		if conn != nil {
+			conns[number] = conn
+		}
+	}
+
+	s.ws.connsLock.Unlock()
+
+	for number, conn := range conns {
+		s.attach(number, conn)
+	}
+}
+
+// record copies the output of the command to its file, waking up the followers on every write.
+func (s *execSession) record(f *os.File, r io.Reader) error {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			_, err := f.Write(buf[:n])
+			if err != nil {
+				return err
+			}
+
+			s.notify()
+		}
+
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+	}
+}
+
+// notify wakes up the followers.
+func (s *execSession) notify() {
+	s.mu.Lock()
+	close(s.changed)
+	s.changed = make(chan struct{})
+	s.mu.Unlock()
+}
+
+// finish marks the command as done and waits for the followers to have sent the remaining output.
+func (s *execSession) finish() {
+	s.mu.Lock()
+	s.done = true
+	s.mu.Unlock()
+
+	s.notify()
+	s.wgFollowers.Wait()
+	s.close()
+}
+
+// attach serves a newly connected websocket. It is a no-op until the command is running or if the
+// websocket is already attached.
+func (s *execSession) attach(number int, conn *websocket.Conn) {
+	s.mu.Lock()
+	if !s.running || s.done || s.attached[number] == conn {
+		s.mu.Unlock()
+		return
+	}
+
+	s.attached[number] = conn
+
+	// Register the follower while holding the lock so finish() can't miss it.
+	f := s.outputs[number]
+	if f != nil {
+		s.wgFollowers.Add(1)
+	}
+
+	s.mu.Unlock()
+
+	l := s.l.AddContext(logger.Ctx{"number": number})
+	l.Debug("Exec session websocket attached")
+
+	if number == execWSControl {
+		go s.control(l, conn)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if number == execWSStdin {
+		go func() {
+			defer cancel()
+
+			err := <-s.mirrorStdin(conn)
+			if err != nil || s.ws.req.Interactive {
+				s.detach(l, number, conn)
+				return
+			}
+
+			// The client closed its stdin, pass that on to the command.
+			s.mu.Lock()
+			if !s.stdinClosed {
+				s.stdinClosed = true
+				_ = s.stdin.Close()
+			}
+
+			s.mu.Unlock()
+		}()
+	} else {
+		// Nothing is expected from the client on output websockets, so reading is only used to detect
+		// when it goes away.
+		go func() {
+			defer cancel()
+
+			_, _, _ = conn.ReadMessage()
+			s.detach(l, number, conn)
+		}()
+	}
+
+	if f == nil {
+		return
+	}
+
+	go func() {
+		defer s.wgFollowers.Done()
+
+		err := s.follow(ctx, conn, f.Name())
+		if err != nil {
+			l.Debug("Stopped following exec output", logger.Ctx{"err": err})
+		}
+	}()
+}
+
+// mirrorStdin relays the websocket to the command's stdin, unless it was already closed.
+func (s *execSession) mirrorStdin(conn *websocket.Conn) chan error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stdinClosed {
+		return ws.MirrorWrite(conn, io.Discard)
+	}
+
+	return ws.MirrorWrite(conn, s.stdin)
+}
+
+// follow sends the content of an output file to the websocket, waiting for more until the command is done.
+func (s *execSession) follow(ctx context.Context, conn *websocket.Conn, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	buf := make([]byte, 32*1024)
+	for {
+		// Grab the state before reading so no write can be missed.
+		s.mu.Lock()
+		changed := s.changed
+		done := s.done
+		s.mu.Unlock()
+
+		n, err := f.Read(buf)
+		if n > 0 {
+			err := conn.WriteMessage(websocket.BinaryMessage, buf[:n])
+			if err != nil {
+				return err
+			}
+		}
+
+		if err == nil {
+			continue
+		}
+
+		if err != io.EOF {
+			return err
+		}
+
+		if done {
+			// Send the barrier message.
+			return conn.WriteMessage(websocket.TextMessage, []byte{})
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// control handles the messages of an attached control websocket. Unlike regular exec sessions, the
+// command keeps running when the control websocket disconnects.
+func (s *execSession) control(l logger.Logger, conn *websocket.Conn) {
+	for {
+		mt, r, err := conn.NextReader()
+		if err != nil || mt == websocket.CloseMessage {
+			break
+		}
+
+		buf, err := io.ReadAll(r)
+		if err != nil {
+			break
+		}
+
+		s.ws.handleControl(l, s.cmd, s.pty, buf)
+	}
+
+	s.detach(l, execWSControl, conn)
+}
+
+// detach releases the websocket so a new client can attach in its place.
+// The secret of the websocket is rotated so only clients able to get the operation can re-attach.
+func (s *execSession) detach(l logger.Logger, number int, conn *websocket.Conn) {
+	var fds jmap.Map
+
+	s.ws.connsLock.Lock()
+	if s.ws.conns[number] == conn {
+		s.ws.conns[number] = nil
+
+		secret, err := internalUtil.RandomHexString(32)
+		if err == nil {
+			s.ws.fds[number] = secret
+			fds = s.ws.fdsMetadata()
+		} else {
+			// Don't allow re-attaching if the secret can't be rotated.
+			delete(s.ws.fds, number)
+			l.Warn("Failed rotating exec session websocket secret", logger.Ctx{"err": err})
+		}
+	}
+
+	s.ws.connsLock.Unlock()
+
+	if fds != nil {
+		err := s.op.ExtendMetadata(jmap.Map{"fds": fds})
+		if err != nil {
+			l.Debug("Failed updating exec session websocket secrets", logger.Ctx{"err": err})
+		}
+	}
+
+	s.mu.Lock()
+	if s.attached[number] == conn {
+		delete(s.attached, number)
+	}
+
+	s.mu.Unlock()
+
+	_ = conn.Close()
+
+	l.Debug("Exec session websocket detached")
+}
+
+// cancel kills the command of the session.
+func (s *execSession) cancel() error {
+	s.mu.Lock()
+	cmd := s.cmd
+	s.mu.Unlock()
+
+	if cmd == nil {
+		return fmt.Errorf("Command isn't running yet")
+	}
+
+	return cmd.Signal(unix.SIGKILL)
+}
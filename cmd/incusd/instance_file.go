@@ -40,6 +40,12 @@ func instanceFileHandler(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Invalid instance name"))
 	}
 
+	write := r.Method != "GET" && r.Method != "HEAD"
+	err = instanceCheckAccessRestriction(s, r, projectName, "restricted.files", write)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Redirect to correct server if needed.
 	instanceType, err := urlInstanceTypeDetect(r)
 	if err != nil {
@@ -425,6 +431,12 @@ func instanceFileHead(s *state.State, inst instance.Instance, path string, r *ht
 //	    schema:
 //	      type: string
 //	    example: overwrite
+//	  - in: header
+//	    name: Content-Range
+//	    description: Range of the file being written, used to resume interrupted uploads (overwrite only)
+//	    schema:
+//	      type: string
+//	    example: bytes 1048576-2097151/4194304
 //	responses:
 //	  "200":
 //	    $ref: "#/responses/EmptySyncResponse"
@@ -434,6 +446,8 @@ func instanceFileHead(s *state.State, inst instance.Instance, path string, r *ht
 //	    $ref: "#/responses/Forbidden"
 //	  "404":
 //	    $ref: "#/responses/NotFound"
+//	  "416":
+//	    description: Range start is past the end of the file
 //	  "500":
 //	    $ref: "#/responses/InternalServerError"
 func instanceFilePost(s *state.State, inst instance.Instance, path string, r *http.Request) response.Response {
@@ -452,6 +466,21 @@ func instanceFilePost(s *state.State, inst instance.Instance, path string, r *ht
 		return response.BadRequest(fmt.Errorf("Bad file write mode: %s", write))
 	}
 
+	// Resumable uploads provide the range of the file being written.
+	rangeStart := int64(-1)
+	rangeEnd := int64(-1)
+	rangeTotal := int64(-1)
+	if r.Header.Get("Content-Range") != "" {
+		if type_ != "file" || write != "overwrite" {
+			return response.BadRequest(fmt.Errorf("Content ranges are only supported when overwriting files"))
+		}
+
+		rangeStart, rangeEnd, rangeTotal, err = api.ParseContentRange(r.Header.Get("Content-Range"))
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
 	// Check if the file already exists.
 	_, err = client.Stat(path)
 	exists := err == nil
@@ -460,7 +489,12 @@ func instanceFilePost(s *state.State, inst instance.Instance, path string, r *ht
 		fileMode := os.O_RDWR
 
 		if write == "overwrite" {
-			fileMode |= os.O_CREATE | os.O_TRUNC
+			fileMode |= os.O_CREATE
+
+			// Keep the already transferred content when resuming.
+			if rangeStart < 0 {
+				fileMode |= os.O_TRUNC
+			}
 		}
 
 		// Open/create the file.
@@ -471,16 +505,60 @@ func instanceFilePost(s *state.State, inst instance.Instance, path string, r *ht
 
 		defer func() { _ = file.Close() }()
 
-		// Go to the end of the file.
-		_, err = file.Seek(0, io.SeekEnd)
-		if err != nil {
-			return response.InternalError(err)
+		if rangeStart >= 0 {
+			// Go to the start of the range, making sure this doesn't leave a hole in the file.
+			stat, err := file.Stat()
+			if err != nil {
+				return response.InternalError(err)
+			}
+
+			if rangeStart > stat.Size() {
+				return response.SmartError(api.StatusErrorf(http.StatusRequestedRangeNotSatisfiable, "Range starts at %d but the file is only %d bytes long", rangeStart, stat.Size()))
+			}
+
+			_, err = file.Seek(rangeStart, io.SeekStart)
+			if err != nil {
+				return response.InternalError(err)
+			}
+		} else {
+			// Go to the end of the file.
+			_, err = file.Seek(0, io.SeekEnd)
+			if err != nil {
+				return response.InternalError(err)
+			}
 		}
 
 		// Transfer the file into the instance.
-		_, err = io.Copy(file, r.Body)
-		if err != nil {
-			return response.InternalError(err)
+		if rangeStart >= 0 {
+			length := rangeEnd - rangeStart + 1
+
+			written, err := io.CopyN(file, r.Body, length)
+			if err != nil && err != io.EOF {
+				return response.InternalError(err)
+			}
+
+			if written < length {
+				return response.BadRequest(fmt.Errorf("Received %d bytes but the range is %d bytes long", written, length))
+			}
+
+			// Make sure the body doesn't go past the end of the range.
+			n, _ := io.ReadFull(r.Body, make([]byte, 1))
+			if n > 0 {
+				return response.BadRequest(fmt.Errorf("Received more than the %d bytes of the range", length))
+			}
+		} else {
+			_, err = io.Copy(file, r.Body)
+			if err != nil {
+				return response.InternalError(err)
+			}
+		}
+
+		// Drop any stale content past the end once the last range got written.
+		if rangeTotal >= 0 && rangeEnd+1 == rangeTotal {
+			err = file.Truncate(rangeTotal)
+			if err != nil {
+				return response.SmartError(err)
+			}
 		}
 
 		if !exists {
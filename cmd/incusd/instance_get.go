@@ -13,6 +13,8 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 // swagger:operation GET /1.0/instances/{name} instances instance_get
@@ -30,6 +32,11 @@ import (
 //      description: Project name
 //      type: string
 //      example: default
+//    - in: query
+//      name: origin
+//      description: Whether to include the origin of the expanded configuration keys and devices
+//      type: boolean
+//      example: true
 //  responses:
 //    "200":
 //      description: Instance
@@ -73,6 +80,11 @@ import (
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: origin
+//	    description: Whether to include the origin of the expanded configuration keys and devices
+//	    type: boolean
+//	    example: true
 //	responses:
 //	  "200":
 //	    description: Instance
@@ -152,5 +164,19 @@ func instanceGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Add the origin of the expanded configuration if requested.
+	if util.IsTrue(r.FormValue("origin")) {
+		configOrigin, devicesOrigin := internalInstance.ConfigOrigins(c.LocalConfig(), c.LocalDevices().CloneNative(), c.Profiles())
+
+		switch inst := state.(type) {
+		case *api.Instance:
+			inst.ExpandedConfigOrigin = configOrigin
+			inst.ExpandedDevicesOrigin = devicesOrigin
+		case *api.InstanceFull:
+			inst.ExpandedConfigOrigin = configOrigin
+			inst.ExpandedDevicesOrigin = devicesOrigin
+		}
+	}
+
 	return response.SyncResponseETag(true, state, etag)
 }
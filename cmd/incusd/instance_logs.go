@@ -10,11 +10,13 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/lxc/incus/v6/internal/asciicast"
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/revert"
 	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
@@ -671,7 +673,31 @@ func validLogFileName(fname string) bool {
 	return fname == "lxc.log" ||
 		fname == "qemu.log" ||
 		strings.HasPrefix(fname, "migration_") ||
-		strings.HasPrefix(fname, "snapshot_")
+		strings.HasPrefix(fname, "snapshot_") ||
+		strings.HasPrefix(fname, "session_")
+}
+
+// instanceSessionRecorder returns a recorder for an interactive session of the instance if its project
+// requires sessions to be recorded, nil otherwise.
+func instanceSessionRecorder(inst instance.Instance, op *operations.Operation, command []string, width int, height int) (*asciicast.Writer, error) {
+	p := inst.Project()
+	if !project.RecordSessions(&p) {
+		return nil, nil
+	}
+
+	if width <= 0 || height <= 0 {
+		width = 80
+		height = 24
+	}
+
+	header := asciicast.Header{
+		Width:   width,
+		Height:  height,
+		Command: strings.Join(command, " "),
+		Title:   fmt.Sprintf("%s/%s", p.Name, inst.Name()),
+	}
+
+	return operations.NewSessionRecorder(op, inst.LogPath(), header)
 }
 
 func validExecOutputFileName(fName string) bool {
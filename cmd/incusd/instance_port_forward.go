@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/ws"
+)
+
+// swagger:operation GET /1.0/instances/{name}/port-forward instances instance_port_forward_get
+//
+//	Forward a connection to an instance port
+//
+//	Connects to a TCP port inside the instance and relays the connection over a websocket.
+//	This doesn't require any configuration change on the instance or a network device.
+//
+//	---
+//	produces:
+//	  - application/octet-stream
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: port
+//	    description: TCP port inside the instance
+//	    type: integer
+//	    example: 80
+//	responses:
+//	  "101":
+//	    description: Switching protocols to websocket
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instancePortForwardGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(fmt.Errorf("Invalid instance name"))
+	}
+
+	port, err := strconv.ParseUint(r.FormValue("port"), 10, 16)
+	if err != nil || port == 0 {
+		return response.BadRequest(fmt.Errorf("Invalid port %q", r.FormValue("port")))
+	}
+
+	err = instanceCheckAccessRestriction(s, r, projectName, "restricted.exec", true)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Forward the request if the instance is remote.
+	client, err := cluster.ConnectIfInstanceIsRemote(s, projectName, name, r, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if client != nil {
+		memberConn, err := client.GetInstancePortForward(name, int(port))
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.ManualResponse(func(w http.ResponseWriter) error {
+			conn, err := ws.Upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				_ = memberConn.Close()
+				return err
+			}
+
+			<-ws.Proxy(conn, memberConn)
+			return nil
+		})
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Connect to the port before upgrading so failures can be reported to the client.
+	instConn, err := inst.PortForwardConn(int(port))
+	if err != nil {
+		return response.SmartError(api.StatusErrorf(http.StatusInternalServerError, "Failed connecting to port %d: %v", port, err))
+	}
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		defer func() { _ = instConn.Close() }()
+
+		conn, err := ws.Upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		instancePortForwardMirror(conn, instConn)
+
+		logger.Debug("Port forwarding finished", logger.Ctx{"project": projectName, "instance": name, "port": port})
+		return nil
+	})
+}
+
+// instancePortForwardMirror relays data between the websocket and the instance connection until the
+// instance is done sending. When the client is done sending first, the instance connection is half-closed
+// so the response can still be received.
+func instancePortForwardMirror(conn *websocket.Conn, instConn net.Conn) {
+	chRead, chWrite := ws.Mirror(conn, instConn)
+
+	select {
+	case <-chRead:
+		// The instance closed the connection.
+		return
+	case <-chWrite:
+		halfCloser, ok := instConn.(interface{ CloseWrite() error })
+		if !ok || halfCloser.CloseWrite() != nil {
+			_ = instConn.Close()
+		}
+
+		<-chRead
+	}
+}
@@ -59,6 +59,12 @@ func instanceSFTPHandler(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(api.StatusErrorf(http.StatusBadRequest, "Missing or invalid upgrade header"))
 	}
 
+	// SFTP sessions can modify files so require full access.
+	err = instanceCheckAccessRestriction(s, r, projectName, "restricted.files", true)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Redirect to correct server if needed.
 	instanceType, err := urlInstanceTypeDetect(r)
 	if err != nil {
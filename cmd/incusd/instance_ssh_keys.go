@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// swagger:operation GET /1.0/instances/{name}/ssh-keys/{user} instances instance_ssh_keys_get
+//
+//	Get the authorized SSH keys of a guest user
+//
+//	Gets the SSH keys authorized to log in as the guest user.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Authorized SSH keys
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceSSHKeys"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSSHKeysGet(d *Daemon, r *http.Request) response.Response {
+	inst, username, resp := instanceSSHKeysLoad(d, r)
+	if resp != nil {
+		return resp
+	}
+
+	keys, err := inst.SSHKeys(username)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	result := api.InstanceSSHKeys{User: username}
+	result.Keys = keys
+
+	return response.SyncResponseETag(true, result, result.Keys)
+}
+
+// swagger:operation PUT /1.0/instances/{name}/ssh-keys/{user} instances instance_ssh_keys_put
+//
+//	Update the authorized SSH keys of a guest user
+//
+//	Replaces the SSH keys authorized to log in as the guest user.
+//	The user is created if it doesn't exist yet.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: keys
+//	    description: Authorized SSH keys
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceSSHKeysPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSSHKeysPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	inst, username, resp := instanceSSHKeysLoad(d, r)
+	if resp != nil {
+		return resp
+	}
+
+	// Validate ETag, skipping the lookup when none was provided.
+	if r.Header.Get("If-Match") != "" {
+		keys, err := inst.SSHKeys(username)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return response.SmartError(err)
+		}
+
+		err = localUtil.EtagCheck(r, keys)
+		if err != nil {
+			return response.PreconditionFailed(err)
+		}
+	}
+
+	req := api.InstanceSSHKeysPut{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	for _, key := range req.Keys {
+		err = internalInstance.ValidSSHKey(key)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	err = inst.SetSSHKeys(username, req.Keys)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed updating SSH keys of user %q: %w", username, err))
+	}
+
+	s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceSSHKeysUpdated.Event(inst, map[string]any{"user": username}))
+
+	return response.EmptySyncResponse
+}
+
+// instanceSSHKeysLoad loads the instance and guest user targeted by the request.
+// A non-nil response is returned if the request was forwarded or failed.
+func instanceSSHKeysLoad(d *Daemon, r *http.Request) (instance.Instance, string, response.Response) {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return nil, "", response.BadRequest(fmt.Errorf("Invalid instance name"))
+	}
+
+	username, err := url.PathUnescape(mux.Vars(r)["user"])
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	err = internalInstance.ValidGuestUser(username)
+	if err != nil {
+		return nil, "", response.BadRequest(err)
+	}
+
+	// Setting the SSH keys allows logging into the instance, same as running commands in it.
+	if r.Method == http.MethodPut {
+		err = instanceCheckAccessRestriction(s, r, projectName, "restricted.exec", true)
+		if err != nil {
+			return nil, "", response.SmartError(err)
+		}
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name, instanceType)
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	if resp != nil {
+		return nil, "", resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return nil, "", response.SmartError(err)
+	}
+
+	return inst, username, nil
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/events"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/storage/memorypipe"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// instanceWaitConditions lists the conditions which can be waited for.
+var instanceWaitConditions = []string{"running", "stopped", "agent", "ip", "ready"}
+
+// instanceWaitIPInterval is how often the addresses of the instance get checked when waiting for an IP.
+// Address changes don't generate events so this is the only condition which needs re-checking.
+const instanceWaitIPInterval = 2 * time.Second
+
+// swagger:operation GET /1.0/instances/{name}/wait instances instance_wait_get
+//
+//	Wait for an instance condition
+//
+//	Waits until the instance reaches the requested condition and returns its state.
+//	The condition is re-evaluated whenever a lifecycle event is emitted for the instance.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: for
+//	    description: Condition to wait for (running, stopped, agent, ip or ready)
+//	    type: string
+//	    example: ready
+//	  - in: query
+//	    name: timeout
+//	    description: Timeout in seconds (-1 means never)
+//	    type: integer
+//	    example: -1
+//	responses:
+//	  "200":
+//	    description: Instance state
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceState"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceWaitGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(fmt.Errorf("Invalid instance name"))
+	}
+
+	condition := r.FormValue("for")
+	if !slices.Contains(instanceWaitConditions, condition) {
+		return response.BadRequest(fmt.Errorf("Invalid condition %q", condition))
+	}
+
+	timeoutSecs := -1
+	if r.FormValue("timeout") != "" {
+		timeoutSecs, err = strconv.Atoi(r.FormValue("timeout"))
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+
+	// If timeout is -1, it will wait indefinitely otherwise it will timeout after timeoutSecs.
+	if timeoutSecs > -1 {
+		ctx, cancel = context.WithDeadline(r.Context(), time.Now().Add(time.Second*time.Duration(timeoutSecs)))
+	} else {
+		ctx, cancel = context.WithCancel(r.Context())
+	}
+
+	waitResponse := func(w http.ResponseWriter) error {
+		defer cancel()
+
+		// Write header to avoid client side timeouts.
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusOK)
+		f, ok := w.(http.Flusher)
+		if ok {
+			f.Flush()
+		}
+
+		instState, err := instanceWait(ctx, s, inst.Project().Name, inst.Name(), condition)
+		if err != nil {
+			_ = response.SmartError(err).Render(w)
+			return nil
+		}
+
+		_ = response.SyncResponse(true, instState).Render(w)
+		return nil
+	}
+
+	return response.ManualResponse(waitResponse)
+}
+
+// instanceWait blocks until the instance reaches the condition and returns its state.
+// The condition is checked once initially and then every time a lifecycle event is emitted for the instance.
+func instanceWait(ctx context.Context, s *state.State, projectName string, name string, condition string) (*api.InstanceState, error) {
+	// Start listening before the initial check so no event can be missed.
+	aEnd, bEnd := memorypipe.NewPipePair(ctx)
+	listener, err := s.Events.AddListener(projectName, false, nil, events.NewSimpleListenerConnection(aEnd), []string{api.EventTypeLifecycle}, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer listener.Close()
+
+	chChanged := make(chan struct{}, 1)
+	go func() {
+		decoder := json.NewDecoder(bEnd)
+		for {
+			var event api.Event
+
+			err := decoder.Decode(&event)
+			if err != nil {
+				return
+			}
+
+			var lifecycleEvent api.EventLifecycle
+
+			err = json.Unmarshal(event.Metadata, &lifecycleEvent)
+			if err != nil || lifecycleEvent.Name != name {
+				continue
+			}
+
+			select {
+			case chChanged <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	var chTicker <-chan time.Time
+	if condition == "ip" {
+		ticker := time.NewTicker(instanceWaitIPInterval)
+		defer ticker.Stop()
+
+		chTicker = ticker.C
+	}
+
+	for {
+		// Reload the instance to get the current volatile keys.
+		inst, err := instance.LoadByProjectAndName(s, projectName, name)
+		if err != nil {
+			return nil, err
+		}
+
+		instState, err := instanceWaitCheck(inst, condition)
+		if err != nil {
+			return nil, err
+		}
+
+		if instState != nil {
+			return instState, nil
+		}
+
+		select {
+		case <-chChanged:
+		case <-chTicker:
+		case <-ctx.Done():
+			return nil, api.StatusErrorf(http.StatusRequestTimeout, "Timed out waiting for instance %q to reach condition %q", name, condition)
+		}
+	}
+}
+
+// instanceWaitCheck returns the state of the instance if it has reached the condition, nil otherwise.
+func instanceWaitCheck(inst instance.Instance, condition string) (*api.InstanceState, error) {
+	var reached bool
+
+	switch condition {
+	case "running":
+		reached = inst.IsRunning()
+	case "stopped":
+		reached = !inst.IsRunning()
+	case "agent":
+		if inst.Type() == instancetype.VM {
+			vm, ok := inst.(instance.VM)
+			reached = ok && vm.AgentStarted()
+		} else {
+			// Containers don't need an agent.
+			reached = inst.IsRunning()
+		}
+
+	case "ready":
+		reached = inst.IsRunning() && util.IsTrue(inst.LocalConfig()["volatile.last_state.ready"]) && inst.LocalConfig()["volatile.last_state.health"] != "unhealthy"
+	case "ip":
+		if !inst.IsRunning() {
+			return nil, nil
+		}
+
+		hostInterfaces, _ := net.Interfaces()
+		instState, err := inst.RenderState(hostInterfaces)
+		if err != nil {
+			return nil, err
+		}
+
+		for netName, network := range instState.Network {
+			if netName == "lo" {
+				continue
+			}
+
+			for _, address := range network.Addresses {
+				if address.Scope == "global" {
+					return instState, nil
+				}
+			}
+		}
+
+		return nil, nil
+	}
+
+	if !reached {
+		return nil, nil
+	}
+
+	hostInterfaces, _ := net.Interfaces()
+	return inst.RenderState(hostInterfaces)
+}
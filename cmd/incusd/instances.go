@@ -20,6 +20,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
 	"github.com/lxc/incus/v6/internal/server/warnings"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
@@ -62,6 +63,13 @@ var instanceStateCmd = APIEndpoint{
 	Put: APIEndpointAction{Handler: instanceStatePut, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanUpdateState, "name")},
 }
 
+var instancePortForwardCmd = APIEndpoint{
+	Name: "instancePortForward",
+	Path: "instances/{name}/port-forward",
+
+	Get: APIEndpointAction{Handler: instancePortForwardGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanExec, "name")},
+}
+
 var instanceSFTPCmd = APIEndpoint{
 	Name: "instanceFile",
 	Path: "instances/{name}/sftp",
@@ -163,6 +171,28 @@ var instanceAccessCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: instanceAccess, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanView, "name")},
 }
 
+var instanceInventoryCmd = APIEndpoint{
+	Name: "instanceInventory",
+	Path: "instances/{name}/inventory",
+
+	Get: APIEndpointAction{Handler: instanceInventoryGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanView, "name")},
+}
+
+var instanceSSHKeysCmd = APIEndpoint{
+	Name: "instanceSSHKeys",
+	Path: "instances/{name}/ssh-keys/{user}",
+
+	Get: APIEndpointAction{Handler: instanceSSHKeysGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanView, "name")},
+	Put: APIEndpointAction{Handler: instanceSSHKeysPut, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
+var instanceWaitCmd = APIEndpoint{
+	Name: "instanceWait",
+	Path: "instances/{name}/wait",
+
+	Get: APIEndpointAction{Handler: instanceWaitGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanView, "name")},
+}
+
 type instanceAutostartList []instance.Instance
 
 func (slice instanceAutostartList) Len() int {
@@ -463,3 +493,42 @@ func instancesShutdown(s *state.State, instances []instance.Instance) {
 	wg.Wait()
 	close(instShutdownCh)
 }
+
+// instancesClockSyncThreshold is the minimum clock jump caused by a host suspend to resynchronize the VM clocks.
+const instancesClockSyncThreshold = 5 * time.Second
+
+// instancesClockSyncTask steps the guest clock of the running virtual machines after the host resumed from suspend.
+func instancesClockSyncTask(d *Daemon) (task.Func, task.Schedule) {
+	last := time.Now()
+
+	f := func(ctx context.Context) {
+		now := time.Now()
+
+		// The monotonic clock doesn't advance while the host is suspended, unlike the wall clock.
+		jump := now.Round(0).Sub(last.Round(0)) - now.Sub(last)
+		last = now
+
+		if jump < instancesClockSyncThreshold {
+			return
+		}
+
+		logger.Info("Host resume detected, synchronizing virtual machine clocks", logger.Ctx{"suspended": jump.Round(time.Second)})
+
+		instances, err := instance.LoadNodeAll(d.State(), instancetype.VM)
+		if err != nil {
+			logger.Error("Failed loading instances to synchronize clocks", logger.Ctx{"err": err})
+			return
+		}
+
+		for _, inst := range instances {
+			vm, ok := inst.(instance.VM)
+			if !ok || !vm.IsRunning() {
+				continue
+			}
+
+			go vm.SyncGuestClock()
+		}
+	}
+
+	return f, task.Every(10 * time.Second)
+}
@@ -85,6 +85,7 @@ void forkfile(void)
 import "C"
 
 import (
+	"io"
 	"net"
 	"os"
 	"os/signal"
@@ -95,6 +96,8 @@ import (
 	"github.com/pkg/sftp"
 	"github.com/spf13/cobra"
 	"golang.org/x/sys/unix"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
 )
 
 type cmdForkfile struct {
@@ -120,9 +123,43 @@ func (c *cmdForkfile) Command() *cobra.Command {
 	cmd.Args = cobra.ExactArgs(4)
 	cmd.RunE = c.Run
 
+	// ssh-keys
+	cmdSSHKeys := &cobra.Command{}
+	cmdSSHKeys.Use = "ssh-keys <rootfs fd> <PIDFd> <PID> <home> <uid> <gid>"
+	cmdSSHKeys.Short = "Write the authorized SSH keys of a container user"
+	cmdSSHKeys.Long = `Description:
+  Write the authorized SSH keys of a container user
+
+  This replaces the authorized keys file in the provided home directory
+  with the keys read from stdin, from within the instance's filesystem.
+`
+	cmdSSHKeys.Args = cobra.ExactArgs(6)
+	cmdSSHKeys.RunE = c.RunSSHKeys
+	cmd.AddCommand(cmdSSHKeys)
+
 	return cmd
 }
 
+// RunSSHKeys writes the authorized SSH keys read from stdin to the home directory of a container user.
+func (c *cmdForkfile) RunSSHKeys(cmd *cobra.Command, args []string) error {
+	uid, err := strconv.Atoi(args[4])
+	if err != nil {
+		return err
+	}
+
+	gid, err := strconv.Atoi(args[5])
+	if err != nil {
+		return err
+	}
+
+	content, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	return internalInstance.WriteAuthorizedKeys(args[3], uid, gid, internalInstance.ParseAuthorizedKeys(content))
+}
+
 func (c *cmdForkfile) Run(cmd *cobra.Command, args []string) error {
 	var mu sync.RWMutex
 	var connections uint64
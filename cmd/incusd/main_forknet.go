@@ -126,7 +126,7 @@ void forknet(void)
 	}
 
 	// Call the subcommands
-	if (strcmp(command, "info") == 0) {
+	if (strcmp(command, "info") == 0 || strcmp(command, "connect") == 0) {
 		int ns_fd, pidfd;
 		pid = atoi(cur);
 
@@ -147,6 +147,7 @@ import "C"
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path/filepath"
@@ -157,6 +158,7 @@ import (
 	"github.com/insomniacslk/dhcp/dhcpv4/client4"
 	"github.com/spf13/cobra"
 
+	"github.com/lxc/incus/v6/internal/eagain"
 	"github.com/lxc/incus/v6/internal/netutils"
 	"github.com/lxc/incus/v6/internal/server/ip"
 	_ "github.com/lxc/incus/v6/shared/cgo" // Used by cgo
@@ -188,6 +190,13 @@ func (c *cmdForknet) Command() *cobra.Command {
 	cmdInfo.RunE = c.RunInfo
 	cmd.AddCommand(cmdInfo)
 
+	// connect
+	cmdConnect := &cobra.Command{}
+	cmdConnect.Use = "connect <PID> <PidFd> <address>"
+	cmdConnect.Args = cobra.ExactArgs(3)
+	cmdConnect.RunE = c.RunConnect
+	cmd.AddCommand(cmdConnect)
+
 	// detach
 	cmdDetach := &cobra.Command{}
 	cmdDetach.Use = "detach <netns file> <daemon PID> <ifname> <hostname>"
@@ -225,6 +234,29 @@ func (c *cmdForknet) RunInfo(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// RunConnect connects to a TCP address in the container's network namespace and relays it over stdin and stdout.
+func (c *cmdForknet) RunConnect(cmd *cobra.Command, args []string) error {
+	conn, err := net.Dial("tcp", args[2])
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	go func() {
+		_, _ = io.Copy(eagain.Writer{Writer: conn}, eagain.Reader{Reader: os.Stdin})
+
+		// Let the target know that no more data is coming.
+		tcpConn, ok := conn.(*net.TCPConn)
+		if ok {
+			_ = tcpConn.CloseWrite()
+		}
+	}()
+
+	_, err = io.Copy(eagain.Writer{Writer: os.Stdout}, eagain.Reader{Reader: conn})
+	return err
+}
+
 // RunDHCP runs a one time DHCPv4 client and applies address, route and DNS configuration.
 func (c *cmdForknet) RunDHCP(cmd *cobra.Command, args []string) error {
 	var messages []*dhcpv4.DHCPv4
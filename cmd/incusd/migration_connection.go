@@ -13,6 +13,7 @@ import (
 
 	"github.com/gorilla/websocket"
 
+	"github.com/lxc/incus/v6/internal/server/metrics"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/tcp"
@@ -159,7 +160,35 @@ func (c *migrationConn) WebsocketIO(ctx context.Context) (io.ReadWriteCloser, er
 		return nil, err
 	}
 
-	return ws.NewWrapper(wsConn), nil
+	return &migrationMetricsConn{ReadWriteCloser: ws.NewWrapper(wsConn)}, nil
+}
+
+// migrationMetricsConn counts the bytes transferred over a migration connection.
+type migrationMetricsConn struct {
+	io.ReadWriteCloser
+}
+
+var migrationReceivedLabels = map[string]string{"direction": "received"}
+var migrationSentLabels = map[string]string{"direction": "sent"}
+
+// Read reads from the connection, counting the received bytes.
+func (c *migrationMetricsConn) Read(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Read(p)
+	if n > 0 {
+		metrics.MigrationBytes.Add(migrationReceivedLabels, float64(n))
+	}
+
+	return n, err
+}
+
+// Write writes to the connection, counting the sent bytes.
+func (c *migrationMetricsConn) Write(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Write(p)
+	if n > 0 {
+		metrics.MigrationBytes.Add(migrationSentLabels, float64(n))
+	}
+
+	return n, err
 }
 
 // Close closes the connection (if established) and marks it as disconnected so that it cannot be used again.
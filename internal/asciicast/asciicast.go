@@ -0,0 +1,162 @@
+// Package asciicast records terminal sessions in the asciicast v2 format.
+//
+// See https://docs.asciinema.org/manual/asciicast/v2/ for the format specification.
+package asciicast
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Header is the first line of an asciicast v2 recording.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Command   string            `json:"command,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Writer records the events of a terminal session.
+type Writer struct {
+	mu      sync.Mutex
+	w       io.Writer
+	start   time.Time
+	pending []byte
+}
+
+// NewWriter writes the header and returns a Writer recording events relative to now.
+func NewWriter(w io.Writer, header Header) (*Writer, error) {
+	start := time.Now()
+
+	header.Version = 2
+	if header.Timestamp == 0 {
+		header.Timestamp = start.Unix()
+	}
+
+	buf, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{w: w, start: start}, nil
+}
+
+// Output records data written to the terminal.
+// Incomplete UTF-8 sequences at the end of data are held back until the rest of them is recorded.
+func (w *Writer) Output(data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	data = append(w.pending, data...)
+
+	// Hold back a trailing incomplete rune.
+	cut := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+
+			break
+		}
+	}
+
+	w.pending = append([]byte(nil), data[cut:]...)
+	if cut == 0 {
+		return nil
+	}
+
+	return w.event("o", string(data[:cut]))
+}
+
+// Resize records a change of the terminal size.
+func (w *Writer) Resize(width int, height int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// Close flushes any pending output and closes the underlying writer if it is an io.Closer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) > 0 {
+		_ = w.event("o", string(w.pending))
+		w.pending = nil
+	}
+
+	closer, ok := w.w.(io.Closer)
+	if ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// event writes an event line, the caller must hold the lock.
+func (w *Writer) event(code string, data string) error {
+	buf, err := json.Marshal([]any{time.Since(w.start).Seconds(), code, data})
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w.w, "%s\n", buf)
+	return err
+}
+
+// Reader returns a reader recording everything read from r as terminal output.
+func (w *Writer) Reader(r io.Reader) io.Reader {
+	return &reader{Reader: r, w: w}
+}
+
+// ReadWriteCloser returns a ReadWriteCloser recording everything read from rwc as terminal output.
+// Data written to rwc (the terminal input) isn't recorded.
+func (w *Writer) ReadWriteCloser(rwc io.ReadWriteCloser) io.ReadWriteCloser {
+	return &readWriteCloser{ReadWriteCloser: rwc, w: w}
+}
+
+type reader struct {
+	io.Reader
+
+	w *Writer
+}
+
+// Read reads from the underlying reader and records the data.
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		_ = r.w.Output(p[:n])
+	}
+
+	return n, err
+}
+
+type readWriteCloser struct {
+	io.ReadWriteCloser
+
+	w *Writer
+}
+
+// Read reads from the underlying ReadWriteCloser and records the data.
+func (r *readWriteCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadWriteCloser.Read(p)
+	if n > 0 {
+		_ = r.w.Output(p[:n])
+	}
+
+	return n, err
+}
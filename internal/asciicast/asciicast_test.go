@@ -0,0 +1,107 @@
+package asciicast_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/asciicast"
+)
+
+// events parses the recording, skipping the header.
+func events(t *testing.T, buf *bytes.Buffer) [][]any {
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.NotEmpty(t, lines)
+
+	header := asciicast.Header{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &header))
+	assert.Equal(t, 2, header.Version)
+
+	result := [][]any{}
+	for _, line := range lines[1:] {
+		event := []any{}
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		require.Len(t, event, 3)
+		result = append(result, event)
+	}
+
+	return result
+}
+
+// Output and resize events are recorded in order.
+func TestWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := asciicast.NewWriter(buf, asciicast.Header{Width: 80, Height: 24})
+	require.NoError(t, err)
+
+	require.NoError(t, w.Output([]byte("hello\r\n")))
+	require.NoError(t, w.Resize(100, 50))
+	require.NoError(t, w.Close())
+
+	result := events(t, buf)
+	require.Len(t, result, 2)
+	assert.Equal(t, "o", result[0][1])
+	assert.Equal(t, "hello\r\n", result[0][2])
+	assert.Equal(t, "r", result[1][1])
+	assert.Equal(t, "100x50", result[1][2])
+}
+
+// Multi-byte characters split across reads are recorded as a whole.
+func TestWriter_SplitRune(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := asciicast.NewWriter(buf, asciicast.Header{Width: 80, Height: 24})
+	require.NoError(t, err)
+
+	data := []byte("é")
+	require.NoError(t, w.Output([]byte{'a', data[0]}))
+	require.NoError(t, w.Output(data[1:]))
+	require.NoError(t, w.Close())
+
+	result := events(t, buf)
+	require.Len(t, result, 2)
+	assert.Equal(t, "a", result[0][2])
+	assert.Equal(t, "é", result[1][2])
+}
+
+// Only the data read from the terminal is recorded, never the input.
+func TestWriter_ReadWriteCloser(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := asciicast.NewWriter(buf, asciicast.Header{Width: 80, Height: 24})
+	require.NoError(t, err)
+
+	term := &fakeTerminal{Reader: strings.NewReader("output")}
+	rwc := w.ReadWriteCloser(term)
+
+	_, err = rwc.Write([]byte("secret"))
+	require.NoError(t, err)
+
+	out, err := io.ReadAll(rwc)
+	require.NoError(t, err)
+	assert.Equal(t, "output", string(out))
+	assert.Equal(t, "secret", term.input.String())
+
+	require.NoError(t, w.Close())
+
+	result := events(t, buf)
+	require.Len(t, result, 1)
+	assert.Equal(t, "output", result[0][2])
+}
+
+type fakeTerminal struct {
+	io.Reader
+
+	input bytes.Buffer
+}
+
+func (f *fakeTerminal) Write(p []byte) (int, error) {
+	return f.input.Write(p)
+}
+
+func (f *fakeTerminal) Close() error {
+	return nil
+}
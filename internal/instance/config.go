@@ -239,6 +239,17 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 		return nil
 	},
 
+	// gendoc:generate(entity=instance, group=miscellaneous, key=logging.console)
+	// The console output of the instance, and for containers the messages sent to `/dev/log`, are sent to the remote logging targets of the server which accept the `instance-log` type.
+	// Messages beyond 100 per second on average, with bursts of up to 1000, are dropped.
+	// The changes apply on the next start of the instance.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  shortdesc: Whether to forward the console and syslog messages to the remote logging targets
+	"logging.console": validate.Optional(validate.IsBool),
+
 	// gendoc:generate(entity=instance, group=migration, key=migration.stateful)
 	// Enabling this option prevents the use of some features that are incompatible with it.
 	// ---
@@ -328,6 +339,42 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 		return err
 	},
 
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.hooks.pre)
+	// The command is run with `/bin/sh -c` as root inside of the instance before a snapshot of the running instance is taken.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Command to run inside of the instance before taking a snapshot
+	"snapshots.hooks.pre": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.hooks.post)
+	// The command is run with `/bin/sh -c` as root inside of the instance after a snapshot of the running instance was taken, even if it failed.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Command to run inside of the instance after taking a snapshot
+	"snapshots.hooks.post": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.hooks.timeout)
+	// The hook command is killed when exceeding the timeout and is then considered as failed.
+	// The timeout must be at least one second.
+	// ---
+	//  type: integer
+	//  defaultdesc: `30`
+	//  liveupdate: yes
+	//  shortdesc: How many seconds each snapshot hook may run for
+	"snapshots.hooks.timeout": validate.Optional(validate.IsInRange(1, 4294967295)),
+
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.hooks.on_failure)
+	// With `abort`, a failing pre-snapshot hook prevents the snapshot from being taken and a failing post-snapshot hook fails the operation.
+	// With `continue`, hook failures are only logged.
+	// ---
+	//  type: string
+	//  defaultdesc: `abort`
+	//  liveupdate: yes
+	//  shortdesc: What to do when a snapshot hook fails (`abort` or `continue`)
+	"snapshots.hooks.on_failure": validate.Optional(validate.IsOneOf("abort", "continue")),
+
 	// Volatile keys.
 
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.apply_template)
@@ -387,6 +434,13 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Instance marked itself as ready
 	"volatile.last_state.ready": validate.IsBool,
 
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.last_state.health)
+	//
+	// ---
+	//  type: string
+	//  shortdesc: Health reported by the instance (`healthy` or `unhealthy`)
+	"volatile.last_state.health": validate.Optional(validate.IsOneOf("healthy", "unhealthy")),
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.uuid)
 	// The instance UUID is globally unique across all servers and projects.
 	// ---
@@ -905,6 +959,28 @@ var InstanceConfigKeysContainer = map[string]func(value string) error{
 
 // InstanceConfigKeysVM is a map of config key to validator. (keys applying to VM only).
 var InstanceConfigKeysVM = map[string]func(value string) error{
+	// gendoc:generate(entity=instance, group=boot, key=boot.shutdown_method)
+	// Possible values are `acpi` (send an ACPI power button event), `agent` (run the shutdown command through the `incus-agent`, falling back to ACPI if the agent can't start it) and `both` (do both at the same time).
+	// The method that was used is reported in the `instance-shutdown` lifecycle event.
+	// ---
+	//  type: string
+	//  defaultdesc: `acpi`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: How to request the guest to shut down
+	"boot.shutdown_method": validate.Optional(validate.IsOneOf("acpi", "agent", "both")),
+
+	// gendoc:generate(entity=instance, group=boot, key=boot.shutdown_command)
+	// The command is run through `/bin/sh` by the `incus-agent` when `boot.shutdown_method` is set to `agent` or `both`.
+	// A failure of the command itself is only logged by the agent in the guest.
+	// ---
+	//  type: string
+	//  defaultdesc: `systemctl poweroff` (or `poweroff` without systemd)
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Command used to shut down the guest through the agent
+	"boot.shutdown_command": validate.IsAny,
+
 	// gendoc:generate(entity=instance, group=resource-limits, key=limits.memory.hugepages)
 	// If this option is set to `false`, regular system memory is used.
 	// ---
@@ -1005,6 +1081,17 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: The guest owner's `base64`-encoded session blob
 	"security.sev.session.data": validate.Optional(validate.IsAny),
 
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.quiesce)
+	// When enabled, the guest filesystems are frozen through the `incus-agent` while taking a stateless snapshot or a backup of a running instance.
+	// Executables placed in `/etc/incus-agent/quiesce.d/` inside of the guest are called with `freeze` before the filesystems get frozen and with `thaw` after they get thawed.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Whether to freeze the guest filesystems during snapshots and backups
+	"snapshots.quiesce": validate.Optional(validate.IsBool),
+
 	// gendoc:generate(entity=instance, group=miscellaneous, key=user.*)
 	// User keys can be used in search.
 	// ---
@@ -1012,6 +1099,13 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  liveupdate: no
 	//  shortdesc: Free-form user key/value storage
 
+	// gendoc:generate(entity=instance, group=miscellaneous, key=user.guest.*)
+	// These keys can also be set and removed from inside the instance through the guest API (`/dev/incus/sock`).
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Free-form key/value storage writable by the instance
+
 	// gendoc:generate(entity=instance, group=miscellaneous, key=agent.nic_config)
 	// For containers, the name and MTU of the default network interfaces is used for the instance devices.
 	// For virtual machines, set this option to `true` to set the name and MTU of the default network interfaces to be the same as the instance devices.
@@ -1023,6 +1117,17 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to use the name and MTU of the default network interfaces
 	"agent.nic_config": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=miscellaneous, key=clock.ptp_kvm)
+	// When enabled, the KVM clock is exposed to the guest and the `incus-agent` loads the `ptp_kvm` driver.
+	// The resulting PTP device is made available as `/dev/ptp_kvm` and can then be used as a reference clock by the guest time synchronization daemon, for example with `refclock PHC /dev/ptp_kvm poll 2` in the `chrony` configuration.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Whether to provide the KVM PTP clock to the guest
+	"clock.ptp_kvm": validate.Optional(validate.IsBool),
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.apply_nvram)
 	//
 	// ---
@@ -0,0 +1,70 @@
+package instance
+
+import (
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// ConfigOrigins returns where each key of the expanded configuration and each expanded device of an
+// instance comes from, along with the profile values they override. The profiles are applied in order,
+// and the local configuration and devices of the instance are applied last.
+func ConfigOrigins(config map[string]string, devices map[string]map[string]string, profiles []api.Profile) (map[string]api.InstanceConfigOrigin, map[string]api.InstanceConfigOrigin) {
+	configOrigins := map[string]api.InstanceConfigOrigin{}
+	configValues := map[string]string{}
+
+	devicesOrigins := map[string]api.InstanceConfigOrigin{}
+	devicesValues := map[string]map[string]string{}
+
+	for _, profile := range profiles {
+		for k, v := range profile.Config {
+			origin := api.InstanceConfigOrigin{Source: "profile", Profile: profile.Name}
+
+			previous, ok := configOrigins[k]
+			if ok {
+				override := api.InstanceConfigOverride{Source: previous.Source, Profile: previous.Profile, Value: configValues[k]}
+				origin.Overridden = append([]api.InstanceConfigOverride{override}, previous.Overridden...)
+			}
+
+			configOrigins[k] = origin
+			configValues[k] = v
+		}
+
+		for name, device := range profile.Devices {
+			origin := api.InstanceConfigOrigin{Source: "profile", Profile: profile.Name}
+
+			previous, ok := devicesOrigins[name]
+			if ok {
+				override := api.InstanceConfigOverride{Source: previous.Source, Profile: previous.Profile, Device: devicesValues[name]}
+				origin.Overridden = append([]api.InstanceConfigOverride{override}, previous.Overridden...)
+			}
+
+			devicesOrigins[name] = origin
+			devicesValues[name] = device
+		}
+	}
+
+	for k := range config {
+		origin := api.InstanceConfigOrigin{Source: "instance"}
+
+		previous, ok := configOrigins[k]
+		if ok {
+			override := api.InstanceConfigOverride{Source: previous.Source, Profile: previous.Profile, Value: configValues[k]}
+			origin.Overridden = append([]api.InstanceConfigOverride{override}, previous.Overridden...)
+		}
+
+		configOrigins[k] = origin
+	}
+
+	for name := range devices {
+		origin := api.InstanceConfigOrigin{Source: "instance"}
+
+		previous, ok := devicesOrigins[name]
+		if ok {
+			override := api.InstanceConfigOverride{Source: previous.Source, Profile: previous.Profile, Device: devicesValues[name]}
+			origin.Overridden = append([]api.InstanceConfigOverride{override}, previous.Overridden...)
+		}
+
+		devicesOrigins[name] = origin
+	}
+
+	return configOrigins, devicesOrigins
+}
@@ -0,0 +1,62 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestConfigOrigins(t *testing.T) {
+	profiles := []api.Profile{
+		{
+			Name: "default",
+			ProfilePut: api.ProfilePut{
+				Config: map[string]string{"limits.cpu": "1", "limits.memory": "1GiB"},
+				Devices: map[string]map[string]string{
+					"root": {"type": "disk", "path": "/", "pool": "default"},
+				},
+			},
+		},
+		{
+			Name: "big",
+			ProfilePut: api.ProfilePut{
+				Config: map[string]string{"limits.cpu": "4"},
+				Devices: map[string]map[string]string{
+					"root": {"type": "disk", "path": "/", "pool": "fast"},
+				},
+			},
+		},
+	}
+
+	config := map[string]string{"limits.cpu": "8", "user.foo": "bar"}
+	devices := map[string]map[string]string{
+		"eth0": {"type": "nic", "network": "incusbr0"},
+	}
+
+	configOrigins, devicesOrigins := ConfigOrigins(config, devices, profiles)
+
+	assert.Equal(t, map[string]api.InstanceConfigOrigin{
+		"limits.cpu": {
+			Source: "instance",
+			Overridden: []api.InstanceConfigOverride{
+				{Source: "profile", Profile: "big", Value: "4"},
+				{Source: "profile", Profile: "default", Value: "1"},
+			},
+		},
+		"limits.memory": {Source: "profile", Profile: "default"},
+		"user.foo":      {Source: "instance"},
+	}, configOrigins)
+
+	assert.Equal(t, map[string]api.InstanceConfigOrigin{
+		"root": {
+			Source:  "profile",
+			Profile: "big",
+			Overridden: []api.InstanceConfigOverride{
+				{Source: "profile", Profile: "default", Device: map[string]string{"type": "disk", "path": "/", "pool": "default"}},
+			},
+		},
+		"eth0": {Source: "instance"},
+	}, devicesOrigins)
+}
@@ -0,0 +1,69 @@
+package instance
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHAuthorizedKeysPath is the path of the authorized keys file relative to the home directory of a guest user.
+const SSHAuthorizedKeysPath = ".ssh/authorized_keys"
+
+// guestUserName matches the user names accepted by the usual guest user management tools.
+var guestUserName = regexp.MustCompile(`^[a-z_][a-z0-9_-]{0,31}$`)
+
+// ValidGuestUser checks whether the provided string is a valid guest user name.
+func ValidGuestUser(name string) error {
+	if !guestUserName.MatchString(name) {
+		return fmt.Errorf("Invalid user name %q", name)
+	}
+
+	return nil
+}
+
+// ValidSSHKey checks whether the provided string is a valid authorized SSH key entry.
+func ValidSSHKey(key string) error {
+	if strings.ContainsAny(key, "\r\n") {
+		return fmt.Errorf("SSH key must be on a single line")
+	}
+
+	_, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+	if err != nil {
+		return fmt.Errorf("Invalid SSH key %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// ParseAuthorizedKeys returns the keys listed in an authorized keys file, skipping empty lines and comments.
+func ParseAuthorizedKeys(content []byte) []string {
+	keys := []string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		keys = append(keys, line)
+	}
+
+	return keys
+}
+
+// RenderAuthorizedKeys returns the content of an authorized keys file holding the provided keys.
+func RenderAuthorizedKeys(keys []string) []byte {
+	var buf bytes.Buffer
+
+	for _, key := range keys {
+		buf.WriteString(strings.TrimSpace(key))
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,93 @@
+//go:build linux
+
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// WriteAuthorizedKeys replaces the authorized keys file in the provided home directory.
+//
+// The home directory is owned by the user who could replace the files and directories in it with
+// symlinks, so it's walked down without following them and the ownership and mode are only changed
+// through the open file descriptors.
+func WriteAuthorizedKeys(home string, uid int, gid int, keys []string) error {
+	err := os.MkdirAll(home, 0755)
+	if err != nil {
+		return err
+	}
+
+	homeFd, err := unix.Open(home, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return &os.PathError{Op: "open", Path: home, Err: err}
+	}
+
+	defer func() { _ = unix.Close(homeFd) }()
+
+	sshDir := filepath.Dir(SSHAuthorizedKeysPath)
+
+	err = unix.Mkdirat(homeFd, sshDir, 0700)
+	if err != nil && !errors.Is(err, unix.EEXIST) {
+		return &os.PathError{Op: "mkdir", Path: filepath.Join(home, sshDir), Err: err}
+	}
+
+	created := err == nil
+
+	sshFd, err := unix.Openat(homeFd, sshDir, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return &os.PathError{Op: "open", Path: filepath.Join(home, sshDir), Err: err}
+	}
+
+	defer func() { _ = unix.Close(sshFd) }()
+
+	if created {
+		err = unix.Fchown(sshFd, uid, gid)
+		if err != nil {
+			return err
+		}
+	}
+
+	keysFd, err := unix.Openat(sshFd, filepath.Base(SSHAuthorizedKeysPath), unix.O_WRONLY|unix.O_CREAT|unix.O_NOFOLLOW|unix.O_NONBLOCK|unix.O_CLOEXEC, 0600)
+	if err != nil {
+		return &os.PathError{Op: "open", Path: filepath.Join(home, SSHAuthorizedKeysPath), Err: err}
+	}
+
+	f := os.NewFile(uintptr(keysFd), filepath.Join(home, SSHAuthorizedKeysPath))
+	defer func() { _ = f.Close() }()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if !fi.Mode().IsRegular() {
+		return fmt.Errorf("Refusing to write SSH keys, %q isn't a regular file", f.Name())
+	}
+
+	err = f.Truncate(0)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(RenderAuthorizedKeys(keys))
+	if err != nil {
+		return err
+	}
+
+	err = f.Chmod(0600)
+	if err != nil {
+		return err
+	}
+
+	err = f.Chown(uid, gid)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
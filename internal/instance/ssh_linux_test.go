@@ -0,0 +1,57 @@
+//go:build linux
+
+package instance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAuthorizedKeys(t *testing.T) {
+	uid := os.Getuid()
+	gid := os.Getgid()
+
+	home := filepath.Join(t.TempDir(), "home")
+	keys := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIA6mnN4vMpv5H6ptc0o7bP8aYg9Nv3VTW+SuY1kkIMnP user@host"}
+
+	err := WriteAuthorizedKeys(home, uid, gid, keys)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(home, SSHAuthorizedKeysPath))
+	require.NoError(t, err)
+
+	assert.Equal(t, keys[0]+"\n", string(content))
+
+	// The user replacing the keys file with a symlink mustn't redirect the write.
+	target := filepath.Join(t.TempDir(), "target")
+	err = os.WriteFile(target, []byte("unchanged"), 0600)
+	require.NoError(t, err)
+
+	err = os.Remove(filepath.Join(home, SSHAuthorizedKeysPath))
+	require.NoError(t, err)
+
+	err = os.Symlink(target, filepath.Join(home, SSHAuthorizedKeysPath))
+	require.NoError(t, err)
+
+	err = WriteAuthorizedKeys(home, uid, gid, keys)
+	assert.Error(t, err)
+
+	// Same for the .ssh directory.
+	err = os.RemoveAll(filepath.Join(home, ".ssh"))
+	require.NoError(t, err)
+
+	err = os.Symlink(filepath.Dir(target), filepath.Join(home, ".ssh"))
+	require.NoError(t, err)
+
+	err = WriteAuthorizedKeys(home, uid, gid, keys)
+	assert.Error(t, err)
+
+	content, err = os.ReadFile(target)
+	require.NoError(t, err)
+
+	assert.Equal(t, "unchanged", string(content))
+}
@@ -0,0 +1,307 @@
+// Package inventory collects the software inventory of an instance from its root filesystem.
+package inventory
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Runner runs a command inside of the instance and returns its output.
+type Runner func(name string, args ...string) (string, error)
+
+// Collect returns the inventory of the provided root filesystem.
+// The runner is used to query the rpm database and the state of the services, it may be nil when the
+// instance isn't running in which case only the information available on disk is returned.
+// The kernel version isn't filled as it isn't a property of the root filesystem.
+func Collect(rootfs fs.FS, run Runner) (*api.InstanceInventory, error) {
+	inventory := &api.InstanceInventory{
+		Packages: []api.InstanceInventoryPackage{},
+		Services: []api.InstanceInventoryService{},
+	}
+
+	var err error
+
+	inventory.OS, err = osRelease(rootfs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, collector := range []func(fs.FS, Runner) ([]api.InstanceInventoryPackage, error){dpkgPackages, apkPackages, rpmPackages} {
+		packages, err := collector(rootfs, run)
+		if err != nil {
+			return nil, err
+		}
+
+		inventory.Packages = append(inventory.Packages, packages...)
+	}
+
+	sort.Slice(inventory.Packages, func(i, j int) bool {
+		if inventory.Packages[i].Name != inventory.Packages[j].Name {
+			return inventory.Packages[i].Name < inventory.Packages[j].Name
+		}
+
+		return inventory.Packages[i].Architecture < inventory.Packages[j].Architecture
+	})
+
+	inventory.Services, err = services(rootfs, run)
+	if err != nil {
+		return nil, err
+	}
+
+	return inventory, nil
+}
+
+// readFile reads a file from the root filesystem, returning nil if it doesn't exist.
+func readFile(rootfs fs.FS, name string) ([]byte, error) {
+	content, err := fs.ReadFile(rootfs, name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Failed reading %q: %w", "/"+name, err)
+	}
+
+	return content, nil
+}
+
+// exists returns whether any of the provided paths exist in the root filesystem.
+func exists(rootfs fs.FS, names ...string) bool {
+	for _, name := range names {
+		_, err := fs.Stat(rootfs, name)
+		if err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// osRelease parses the os-release file.
+func osRelease(rootfs fs.FS) (api.InstanceInventoryOS, error) {
+	osInfo := api.InstanceInventoryOS{}
+
+	var content []byte
+	for _, name := range []string{"etc/os-release", "usr/lib/os-release"} {
+		var err error
+
+		content, err = readFile(rootfs, name)
+		if err != nil {
+			return osInfo, err
+		}
+
+		if content != nil {
+			break
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !found || strings.HasPrefix(key, "#") {
+			continue
+		}
+
+		unquoted, err := strconv.Unquote(value)
+		if err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `'"`)
+		}
+
+		switch key {
+		case "ID":
+			osInfo.ID = value
+		case "NAME":
+			osInfo.Name = value
+		case "VERSION_ID":
+			osInfo.Version = value
+		case "PRETTY_NAME":
+			osInfo.PrettyName = value
+		}
+	}
+
+	return osInfo, nil
+}
+
+// stanzas splits a database made of blank line separated records into key/value maps.
+func stanzas(content []byte, separator string) []map[string]string {
+	records := []map[string]string{}
+	record := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(record) > 0 {
+				records = append(records, record)
+				record = map[string]string{}
+			}
+
+			continue
+		}
+
+		// Skip continuation lines.
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, separator)
+		if found {
+			record[key] = strings.TrimSpace(value)
+		}
+	}
+
+	if len(record) > 0 {
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// dpkgPackages lists the packages installed in the dpkg database.
+func dpkgPackages(rootfs fs.FS, run Runner) ([]api.InstanceInventoryPackage, error) {
+	content, err := readFile(rootfs, "var/lib/dpkg/status")
+	if err != nil {
+		return nil, err
+	}
+
+	packages := []api.InstanceInventoryPackage{}
+	for _, record := range stanzas(content, ":") {
+		if !strings.HasSuffix(record["Status"], " installed") {
+			continue
+		}
+
+		packages = append(packages, api.InstanceInventoryPackage{
+			Name:         record["Package"],
+			Version:      record["Version"],
+			Architecture: record["Architecture"],
+			Manager:      "dpkg",
+		})
+	}
+
+	return packages, nil
+}
+
+// apkPackages lists the packages installed in the apk database.
+func apkPackages(rootfs fs.FS, run Runner) ([]api.InstanceInventoryPackage, error) {
+	var content []byte
+	for _, name := range []string{"lib/apk/db/installed", "usr/lib/apk/db/installed"} {
+		var err error
+
+		content, err = readFile(rootfs, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if content != nil {
+			break
+		}
+	}
+
+	packages := []api.InstanceInventoryPackage{}
+	for _, record := range stanzas(content, ":") {
+		if record["P"] == "" {
+			continue
+		}
+
+		packages = append(packages, api.InstanceInventoryPackage{
+			Name:         record["P"],
+			Version:      record["V"],
+			Architecture: record["A"],
+			Manager:      "apk",
+		})
+	}
+
+	return packages, nil
+}
+
+// rpmPackages lists the packages installed in the rpm database.
+// The database format isn't stable across distributions so this relies on the rpm tool of the instance.
+func rpmPackages(rootfs fs.FS, run Runner) ([]api.InstanceInventoryPackage, error) {
+	if run == nil || !exists(rootfs, "usr/bin/rpm", "bin/rpm") {
+		return nil, nil
+	}
+
+	output, err := run("rpm", "-qa", "--queryformat", `%{NAME}\t%{EPOCHNUM}:%{VERSION}-%{RELEASE}\t%{ARCH}\n`)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing rpm packages: %w", err)
+	}
+
+	packages := []api.InstanceInventoryPackage{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+
+		packages = append(packages, api.InstanceInventoryPackage{
+			Name:         fields[0],
+			Version:      strings.TrimPrefix(fields[1], "0:"),
+			Architecture: fields[2],
+			Manager:      "rpm",
+		})
+	}
+
+	return packages, nil
+}
+
+// services lists the systemd and OpenRC services of the instance.
+func services(rootfs fs.FS, run Runner) ([]api.InstanceInventoryService, error) {
+	found := map[string]*api.InstanceInventoryService{}
+
+	// Services enabled on disk.
+	for _, pattern := range []string{"etc/systemd/system/*.wants/*.service", "etc/runlevels/*/*"} {
+		matches, err := fs.Glob(rootfs, pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, match := range matches {
+			name := path.Base(match)
+			found[name] = &api.InstanceInventoryService{Name: name, Enabled: true}
+		}
+	}
+
+	// Current state from systemd.
+	if run != nil && exists(rootfs, "usr/bin/systemctl", "bin/systemctl") {
+		// Failures are expected when systemd isn't running as the init system.
+		output, err := run("systemctl", "list-units", "--type=service", "--all", "--plain", "--no-legend", "--no-pager")
+		if err == nil {
+			for _, line := range strings.Split(output, "\n") {
+				// Fields are UNIT, LOAD, ACTIVE, SUB and DESCRIPTION.
+				fields := strings.Fields(line)
+				if len(fields) < 4 || !strings.HasSuffix(fields[0], ".service") || fields[1] == "not-found" {
+					continue
+				}
+
+				service, ok := found[fields[0]]
+				if !ok {
+					service = &api.InstanceInventoryService{Name: fields[0]}
+					found[fields[0]] = service
+				}
+
+				service.State = fields[3]
+			}
+		}
+	}
+
+	result := make([]api.InstanceInventoryService, 0, len(found))
+	for _, service := range found {
+		result = append(result, *service)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result, nil
+}
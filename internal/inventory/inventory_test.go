@@ -0,0 +1,116 @@
+package inventory
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type inventorySuite struct {
+	suite.Suite
+}
+
+func TestInventorySuite(t *testing.T) {
+	suite.Run(t, new(inventorySuite))
+}
+
+// The os-release file is parsed with its quoting.
+func (s *inventorySuite) Test_osRelease() {
+	rootfs := fstest.MapFS{
+		"usr/lib/os-release": {Data: []byte("NAME=\"Ubuntu\"\nVERSION_ID='24.04'\nID=ubuntu\nPRETTY_NAME=\"Ubuntu 24.04.1 LTS\"\n")},
+	}
+
+	osInfo, err := osRelease(rootfs)
+	s.NoError(err)
+	s.Equal(api.InstanceInventoryOS{ID: "ubuntu", Name: "Ubuntu", Version: "24.04", PrettyName: "Ubuntu 24.04.1 LTS"}, osInfo)
+}
+
+// Only installed dpkg packages are listed.
+func (s *inventorySuite) Test_dpkgPackages() {
+	rootfs := fstest.MapFS{
+		"var/lib/dpkg/status": {Data: []byte(`Package: openssl
+Status: install ok installed
+Architecture: amd64
+Version: 3.0.13-0ubuntu3.4
+Description: Secure Sockets Layer toolkit
+ This package contains the openssl binary.
+
+Package: removed
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 1.0
+`)},
+	}
+
+	packages, err := dpkgPackages(rootfs, nil)
+	s.NoError(err)
+	s.Equal([]api.InstanceInventoryPackage{{Name: "openssl", Version: "3.0.13-0ubuntu3.4", Architecture: "amd64", Manager: "dpkg"}}, packages)
+}
+
+// Packages are listed from the apk database.
+func (s *inventorySuite) Test_apkPackages() {
+	rootfs := fstest.MapFS{
+		"lib/apk/db/installed": {Data: []byte("C:Q1abc=\nP:musl\nV:1.2.5-r0\nA:x86_64\n\nC:Q1def=\nP:busybox\nV:1.36.1-r29\nA:x86_64\n")},
+	}
+
+	packages, err := apkPackages(rootfs, nil)
+	s.NoError(err)
+	s.Equal([]api.InstanceInventoryPackage{
+		{Name: "musl", Version: "1.2.5-r0", Architecture: "x86_64", Manager: "apk"},
+		{Name: "busybox", Version: "1.36.1-r29", Architecture: "x86_64", Manager: "apk"},
+	}, packages)
+}
+
+// The rpm database is only queried when a runner is available.
+func (s *inventorySuite) Test_rpmPackages() {
+	rootfs := fstest.MapFS{
+		"usr/bin/rpm": {},
+	}
+
+	packages, err := rpmPackages(rootfs, nil)
+	s.NoError(err)
+	s.Empty(packages)
+
+	run := func(name string, args ...string) (string, error) {
+		return "bash\t0:5.2.26-3.fc40\tx86_64\nkernel-core\t1:6.8.5-301.fc40\tx86_64\n", nil
+	}
+
+	packages, err = rpmPackages(rootfs, run)
+	s.NoError(err)
+	s.Equal([]api.InstanceInventoryPackage{
+		{Name: "bash", Version: "5.2.26-3.fc40", Architecture: "x86_64", Manager: "rpm"},
+		{Name: "kernel-core", Version: "1:6.8.5-301.fc40", Architecture: "x86_64", Manager: "rpm"},
+	}, packages)
+}
+
+// Enabled services are merged with the state reported by systemd.
+func (s *inventorySuite) Test_services() {
+	rootfs := fstest.MapFS{
+		"usr/bin/systemctl": {},
+		"etc/systemd/system/multi-user.target.wants/ssh.service": {},
+	}
+
+	run := func(name string, args ...string) (string, error) {
+		return "ssh.service loaded active running OpenBSD Secure Shell server\ncron.service loaded inactive dead Regular background program processing daemon\n", nil
+	}
+
+	result, err := services(rootfs, run)
+	s.NoError(err)
+	s.Equal([]api.InstanceInventoryService{
+		{Name: "cron.service", Enabled: false, State: "dead"},
+		{Name: "ssh.service", Enabled: true, State: "running"},
+	}, result)
+
+	// Failing systemctl only reports the enabled services.
+	run = func(name string, args ...string) (string, error) {
+		return "", fmt.Errorf("System has not been booted with systemd")
+	}
+
+	result, err = services(rootfs, run)
+	s.NoError(err)
+	s.Equal([]api.InstanceInventoryService{{Name: "ssh.service", Enabled: true}}, result)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -125,6 +126,11 @@ func (c *Config) MaxStandBy() int64 {
 	return c.m.GetInt64("cluster.max_standby")
 }
 
+// ProjectsUsageRetentionDays returns the number of days after which the project usage records are deleted.
+func (c *Config) ProjectsUsageRetentionDays() int64 {
+	return c.m.GetInt64("projects.usage_retention")
+}
+
 // NetworkOVNIntegrationBridge returns the integration OVS bridge to use for OVN networks.
 func (c *Config) NetworkOVNIntegrationBridge() string {
 	return c.m.GetString("network.ovn.integration_bridge")
@@ -147,6 +153,11 @@ func (c *Config) ShutdownTimeout() time.Duration {
 	return time.Duration(n) * time.Minute
 }
 
+// EventsHistorySize returns the number of lifecycle events to keep in the event history.
+func (c *Config) EventsHistorySize() int64 {
+	return c.m.GetInt64("core.events_history_size")
+}
+
 // ImagesDefaultArchitecture returns the default architecture.
 func (c *Config) ImagesDefaultArchitecture() string {
 	return c.m.GetString("images.default_architecture")
@@ -203,6 +214,48 @@ func (c *Config) LokiServer() (string, string, string, string, string, string, [
 	return c.m.GetString("loki.api.url"), c.m.GetString("loki.auth.username"), c.m.GetString("loki.auth.password"), c.m.GetString("loki.api.ca_cert"), c.m.GetString("loki.instance"), c.m.GetString("loki.loglevel"), labels, types
 }
 
+// TracingServer returns all the OpenTelemetry tracing settings.
+func (c *Config) TracingServer() (string, string, map[string]string, float64) {
+	ratio, _ := strconv.ParseFloat(c.m.GetString("tracing.sample_ratio"), 64)
+
+	return c.m.GetString("tracing.otlp.api.url"), c.m.GetString("tracing.otlp.api.ca_cert"), c.getHeaders("tracing.otlp.api.headers"), ratio
+}
+
+// LoggingOTLP returns all the settings of the OTLP logging target.
+func (c *Config) LoggingOTLP() (string, string, map[string]string, string, string, []string, []string) {
+	return c.m.GetString("logging.otlp.api.url"), c.m.GetString("logging.otlp.api.ca_cert"), c.getHeaders("logging.otlp.api.headers"), c.m.GetString("logging.otlp.instance"), c.m.GetString("logging.otlp.loglevel"), c.getList("logging.otlp.labels"), c.getList("logging.otlp.types")
+}
+
+// LoggingSyslog returns all the settings of the remote syslog logging target.
+func (c *Config) LoggingSyslog() (string, string, string, string, string, []string, []string) {
+	return c.m.GetString("logging.syslog.address"), c.m.GetString("logging.syslog.protocol"), c.m.GetString("logging.syslog.ca_cert"), c.m.GetString("logging.syslog.instance"), c.m.GetString("logging.syslog.loglevel"), c.getList("logging.syslog.labels"), c.getList("logging.syslog.types")
+}
+
+// LoggingGELF returns all the settings of the GELF logging target.
+func (c *Config) LoggingGELF() (string, string, string, string, string, []string, []string) {
+	return c.m.GetString("logging.gelf.address"), c.m.GetString("logging.gelf.protocol"), c.m.GetString("logging.gelf.ca_cert"), c.m.GetString("logging.gelf.instance"), c.m.GetString("logging.gelf.loglevel"), c.getList("logging.gelf.labels"), c.getList("logging.gelf.types")
+}
+
+// getList returns the values of a comma-separated list key.
+func (c *Config) getList(key string) []string {
+	if c.m.GetString(key) == "" {
+		return nil
+	}
+
+	return strings.Split(c.m.GetString(key), ",")
+}
+
+// getHeaders returns the HTTP headers of a comma-separated list of key=value pairs.
+func (c *Config) getHeaders(key string) map[string]string {
+	headers := map[string]string{}
+	for _, header := range c.getList(key) {
+		name, value, _ := strings.Cut(header, "=")
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return headers
+}
+
 // ACME returns all ACME settings needed for certificate renewal.
 func (c *Config) ACME() (string, string, string, bool) {
 	return c.m.GetString("acme.domain"), c.m.GetString("acme.email"), c.m.GetString("acme.ca_url"), c.m.GetBool("acme.agree_tos")
@@ -407,6 +460,16 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: BGP Autonomous System Number for the local server
 	"core.bgp_asn": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 4294967294))},
 
+	// gendoc:generate(entity=server, group=core, key=core.events_history_size)
+	// Lifecycle events are recorded in the database so that event listeners can replay them
+	// after reconnecting. This sets how many of the most recent events are kept, `0` disables recording.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `10000`
+	//  shortdesc: Number of lifecycle events kept in the event history
+	"core.events_history_size": {Type: config.Int64, Default: "10000", Validator: validate.Optional(validate.IsInRange(0, 1000000))},
+
 	// gendoc:generate(entity=server, group=core, key=core.https_allowed_headers)
 	//
 	// ---
@@ -583,6 +646,188 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Instance placement scriptlet for automatic instance placement
 	"instances.placement.scriptlet": {Validator: validate.Optional(scriptletLoad.InstancePlacementValidate)},
 
+	// gendoc:generate(entity=server, group=logging, key=logging.gelf.address)
+	// Specify the name or IP and port of the Graylog input. For example `graylog.example.com:12201`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Address of the GELF server
+	"logging.gelf.address": {Validator: validate.Optional(addressValidator)},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.gelf.ca_cert)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: CA certificate for the GELF server
+	"logging.gelf.ca_cert": {},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.gelf.instance)
+	// This allows replacing the default instance value (server host name) by a more relevant value like a cluster identifier.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: Local server host name or cluster member name
+	//  shortdesc: Name to use as the instance field in GELF messages
+	"logging.gelf.instance": {},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.gelf.labels)
+	// Specify a comma-separated list of values that should be sent as additional fields of the GELF messages.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Labels for a GELF message
+	"logging.gelf.labels": {},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.gelf.loglevel)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `info`
+	//  shortdesc: Minimum log level to send to the GELF server
+	"logging.gelf.loglevel": {Validator: logLevelValidator, Default: logrus.InfoLevel.String()},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.gelf.protocol)
+	// Possible values are `tcp`, `tls` and `udp`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `udp`
+	//  shortdesc: Protocol used to connect to the GELF server
+	"logging.gelf.protocol": {Validator: validate.Optional(validate.IsOneOf("tcp", "tls", "udp")), Default: "udp"},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.gelf.types)
+	// Specify a comma-separated list of events to send to the GELF server.
+	// The events can be any combination of `lifecycle`, `logging`, `network-acl` and `instance-log`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `lifecycle,logging`
+	//  shortdesc: Events to send to the GELF server
+	"logging.gelf.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("lifecycle", "logging", "network-acl", "instance-log"))), Default: "lifecycle,logging"},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.otlp.api.ca_cert)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: CA certificate for the OpenTelemetry collector
+	"logging.otlp.api.ca_cert": {},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.otlp.api.headers)
+	// Specify a comma-separated list of `key=value` HTTP headers to send to the collector, for example to authenticate.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Additional headers sent to the OpenTelemetry collector
+	"logging.otlp.api.headers": {Validator: validate.Optional(validate.IsListOf(headerValidator))},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.otlp.api.url)
+	// Specify the protocol, name or IP and port of an OTLP/HTTP endpoint. For example `https://otel.example.com:4318`. Incus will automatically add the `/v1/logs` suffix so there's no need to add it here.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: URL to the OpenTelemetry collector
+	"logging.otlp.api.url": {},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.otlp.instance)
+	// This allows replacing the default instance value (server host name) by a more relevant value like a cluster identifier.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: Local server host name or cluster member name
+	//  shortdesc: Name to use as the instance attribute in OTLP log records
+	"logging.otlp.instance": {},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.otlp.labels)
+	// Specify a comma-separated list of values that should be sent as attributes of the OTLP log records.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Labels for an OTLP log record
+	"logging.otlp.labels": {},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.otlp.loglevel)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `info`
+	//  shortdesc: Minimum log level to send to the OpenTelemetry collector
+	"logging.otlp.loglevel": {Validator: logLevelValidator, Default: logrus.InfoLevel.String()},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.otlp.types)
+	// Specify a comma-separated list of events to send to the OpenTelemetry collector.
+	// The events can be any combination of `lifecycle`, `logging`, `network-acl` and `instance-log`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `lifecycle,logging`
+	//  shortdesc: Events to send to the OpenTelemetry collector
+	"logging.otlp.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("lifecycle", "logging", "network-acl", "instance-log"))), Default: "lifecycle,logging"},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.syslog.address)
+	// Specify the name or IP and port of the syslog server. For example `syslog.example.com:6514`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Address of the remote syslog server
+	"logging.syslog.address": {Validator: validate.Optional(addressValidator)},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.syslog.ca_cert)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: CA certificate for the remote syslog server
+	"logging.syslog.ca_cert": {},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.syslog.instance)
+	// This allows replacing the default instance value (server host name) by a more relevant value like a cluster identifier.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: Local server host name or cluster member name
+	//  shortdesc: Name to use as the instance field in syslog messages
+	"logging.syslog.instance": {},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.syslog.labels)
+	// Specify a comma-separated list of values that should be sent as structured data of the syslog messages.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Labels for a syslog message
+	"logging.syslog.labels": {},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.syslog.loglevel)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `info`
+	//  shortdesc: Minimum log level to send to the remote syslog server
+	"logging.syslog.loglevel": {Validator: logLevelValidator, Default: logrus.InfoLevel.String()},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.syslog.protocol)
+	// Possible values are `tcp` and `tls`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `tcp`
+	//  shortdesc: Protocol used to connect to the remote syslog server
+	"logging.syslog.protocol": {Validator: validate.Optional(validate.IsOneOf("tcp", "tls")), Default: "tcp"},
+
+	// gendoc:generate(entity=server, group=logging, key=logging.syslog.types)
+	// Specify a comma-separated list of events to send to the remote syslog server.
+	// The events can be any combination of `lifecycle`, `logging`, `network-acl` and `instance-log`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `lifecycle,logging`
+	//  shortdesc: Events to send to the remote syslog server
+	"logging.syslog.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("lifecycle", "logging", "network-acl", "instance-log"))), Default: "lifecycle,logging"},
+
 	// gendoc:generate(entity=server, group=loki, key=loki.auth.username)
 	//
 	// ---
@@ -643,13 +888,13 @@ var ConfigSchema = config.Schema{
 
 	// gendoc:generate(entity=server, group=loki, key=loki.types)
 	// Specify a comma-separated list of events to send to the Loki server.
-	// The events can be any combination of `lifecycle`, `logging`, and `network-acl`.
+	// The events can be any combination of `lifecycle`, `logging`, `network-acl` and `instance-log`.
 	// ---
 	//  type: string
 	//  scope: global
 	//  defaultdesc: `lifecycle,logging`
 	//  shortdesc: Events to send to the Loki server
-	"loki.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("lifecycle", "logging", "network-acl"))), Default: "lifecycle,logging"},
+	"loki.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("lifecycle", "logging", "network-acl", "instance-log"))), Default: "lifecycle,logging"},
 
 	// gendoc:generate(entity=server, group=openfga, key=openfga.api.token)
 	//
@@ -753,6 +998,49 @@ var ConfigSchema = config.Schema{
 	//  defaultdesc: Content of `/etc/ovn/key_host` if present
 	//  shortdesc: OVN SSL client key
 	"network.ovn.client_key": {Default: ""},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=projects.usage_retention)
+	// Specify the number of days after which the hourly usage records of the projects are deleted.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `90`
+	//  shortdesc: How long project usage records are kept
+	"projects.usage_retention": {Type: config.Int64, Default: "90", Validator: validate.Optional(validate.IsInRange(1, 36500))},
+
+	// gendoc:generate(entity=server, group=tracing, key=tracing.otlp.api.ca_cert)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: CA certificate for the OpenTelemetry collector
+	"tracing.otlp.api.ca_cert": {},
+
+	// gendoc:generate(entity=server, group=tracing, key=tracing.otlp.api.headers)
+	// Specify a comma-separated list of `key=value` HTTP headers to send to the collector, for example to authenticate.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Additional headers sent to the OpenTelemetry collector
+	"tracing.otlp.api.headers": {Validator: validate.Optional(validate.IsListOf(headerValidator))},
+
+	// gendoc:generate(entity=server, group=tracing, key=tracing.otlp.api.url)
+	// Specify the protocol, name or IP and port of an OTLP/HTTP endpoint. For example `https://otel.example.com:4318`. Incus will automatically add the `/v1/traces` suffix so there's no need to add it here.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: URL to the OpenTelemetry collector
+	"tracing.otlp.api.url": {},
+
+	// gendoc:generate(entity=server, group=tracing, key=tracing.sample_ratio)
+	// Specify the fraction of the traces to record, between `0` and `1`.
+	// Traces started by another cluster member follow the decision of that member.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `1`
+	//  shortdesc: Fraction of the traces to record
+	"tracing.sample_ratio": {Default: "1", Validator: tracingSampleRatioValidator},
 }
 
 func expiryValidator(value string) error {
@@ -836,3 +1124,34 @@ func maxStandByValidator(value string) error {
 
 	return nil
 }
+
+func headerValidator(value string) error {
+	key, _, found := strings.Cut(value, "=")
+	if !found || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("Header must be in the form key=value")
+	}
+
+	return nil
+}
+
+func tracingSampleRatioValidator(value string) error {
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("Value is not a number")
+	}
+
+	if ratio < 0 || ratio > 1 {
+		return fmt.Errorf("Value must be between 0 and 1")
+	}
+
+	return nil
+}
+
+func addressValidator(value string) error {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil || host == "" {
+		return fmt.Errorf("Address must be in the form host:port")
+	}
+
+	return validate.IsNetworkPort(port)
+}
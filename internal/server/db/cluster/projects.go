@@ -162,6 +162,46 @@ func GetProjectIDsToNames(ctx context.Context, tx *sql.Tx) (map[int64]string, er
 	return result, nil
 }
 
+// GetProjectParents returns a map associating each project that has a parent
+// to the name of its parent project.
+func GetProjectParents(ctx context.Context, tx *sql.Tx) (map[string]string, error) {
+	stmt := `
+SELECT projects.name, projects_config.value
+  FROM projects_config
+  JOIN projects ON projects.id = projects_config.project_id
+ WHERE projects_config.key = 'parent'
+`
+
+	result := map[string]string{}
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		var name string
+		var parent string
+
+		err := scan(&name, &parent)
+		if err != nil {
+			return err
+		}
+
+		result[name] = parent
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Fetch project parents: %w", err)
+	}
+
+	return result, nil
+}
+
+// RenameProjectParent points the children of a renamed project to its new name.
+func RenameProjectParent(ctx context.Context, tx *sql.Tx, name string, to string) error {
+	_, err := tx.ExecContext(ctx, "UPDATE projects_config SET value = ? WHERE key = 'parent' AND value = ?", to, name)
+	if err != nil {
+		return fmt.Errorf("Update project parents: %w", err)
+	}
+
+	return nil
+}
+
 // ProjectHasImages is a helper to check if a project has the images
 // feature enabled.
 func ProjectHasImages(ctx context.Context, tx *sql.Tx, name string) (bool, error) {
@@ -43,6 +43,14 @@ CREATE TABLE config (
     value TEXT,
     UNIQUE (key)
 );
+CREATE TABLE events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    type TEXT NOT NULL,
+    timestamp DATETIME NOT NULL,
+    location TEXT NOT NULL,
+    project TEXT NOT NULL,
+    metadata TEXT NOT NULL
+);
 CREATE TABLE "images" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     fingerprint TEXT NOT NULL,
@@ -467,6 +475,31 @@ CREATE TABLE "projects_config" (
     FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
     UNIQUE (project_id, key)
 );
+CREATE TABLE projects_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    definition TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE projects_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    instance_name TEXT NOT NULL,
+    instance_type INTEGER NOT NULL,
+    date DATETIME NOT NULL,
+    samples INTEGER NOT NULL,
+    cpu_seconds REAL NOT NULL,
+    memory_bytes INTEGER NOT NULL,
+    disk_bytes INTEGER NOT NULL,
+    network_received_bytes INTEGER NOT NULL,
+    network_sent_bytes INTEGER NOT NULL,
+    cpu_seconds_counter REAL NOT NULL,
+    network_received_counter INTEGER NOT NULL,
+    network_sent_counter INTEGER NOT NULL,
+    UNIQUE (project_id, instance_name, date),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
 CREATE TABLE "storage_buckets" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     name TEXT NOT NULL,
@@ -656,6 +689,25 @@ CREATE TABLE "warnings" (
     FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
+CREATE TABLE webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    types TEXT NOT NULL,
+    projects TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE webhooks_dead_letters (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    webhook_id INTEGER NOT NULL,
+    event TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    error TEXT NOT NULL,
+    failed_at DATETIME NOT NULL,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
 
-INSERT INTO schema (version, updated_at) VALUES (74, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (78, strftime("%s"))
 `
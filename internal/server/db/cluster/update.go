@@ -111,6 +111,110 @@ var updates = map[int]schema.Update{
 	72: updateFromV71,
 	73: updateFromV72,
 	74: updateFromV73,
+	75: updateFromV74,
+	76: updateFromV75,
+	77: updateFromV76,
+	78: updateFromV77,
+}
+
+// updateFromV77 adds the tables storing the webhooks and the events that couldn't be delivered to them.
+func updateFromV77(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    types TEXT NOT NULL,
+    projects TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE webhooks_dead_letters (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    webhook_id INTEGER NOT NULL,
+    event TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    error TEXT NOT NULL,
+    failed_at DATETIME NOT NULL,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding webhooks tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV76 adds a table recording the history of the lifecycle events.
+func updateFromV76(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    type TEXT NOT NULL,
+    timestamp DATETIME NOT NULL,
+    location TEXT NOT NULL,
+    project TEXT NOT NULL,
+    metadata TEXT NOT NULL
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding events table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV75 adds a table storing the project templates.
+func updateFromV75(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE projects_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    definition TEXT NOT NULL,
+    UNIQUE (name)
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding projects templates table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV74 adds a table recording the hourly resource usage of the instances of each project.
+func updateFromV74(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE projects_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    instance_name TEXT NOT NULL,
+    instance_type INTEGER NOT NULL,
+    date DATETIME NOT NULL,
+    samples INTEGER NOT NULL,
+    cpu_seconds REAL NOT NULL,
+    memory_bytes INTEGER NOT NULL,
+    disk_bytes INTEGER NOT NULL,
+    network_received_bytes INTEGER NOT NULL,
+    network_sent_bytes INTEGER NOT NULL,
+    cpu_seconds_counter REAL NOT NULL,
+    network_received_counter INTEGER NOT NULL,
+    network_sent_counter INTEGER NOT NULL,
+    UNIQUE (project_id, instance_name, date),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding projects usage table: %w", err)
+	}
+
+	return nil
 }
 
 // updateFromV73 adds a config table to cluster groups.
@@ -19,6 +19,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/node"
 	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/internal/server/metrics"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/logger"
 )
@@ -338,13 +339,24 @@ func (c *Cluster) transaction(ctx context.Context, f func(context.Context, *Clus
 		nodeID: c.nodeID,
 	}
 
-	return query.Retry(ctx, func(ctx context.Context) error {
+	return query.Retry(ctx, func(ctx context.Context) (err error) {
+		// Record the transaction duration.
+		start := time.Now()
+		defer func() {
+			result := "success"
+			if err != nil {
+				result = "error"
+			}
+
+			metrics.DqliteTransactions.Observe(map[string]string{"result": result}, time.Since(start).Seconds())
+		}()
+
 		txFunc := func(ctx context.Context, tx *sql.Tx) error {
 			clusterTx.tx = tx
 			return f(ctx, clusterTx)
 		}
 
-		err := query.Transaction(ctx, c.db, txFunc)
+		err = query.Transaction(ctx, c.db, txFunc)
 		if errors.Is(err, context.DeadlineExceeded) {
 			// If the query timed out it likely means that the leader has abruptly become unreachable.
 			// Now that this query has been cancelled, a leader election should have taken place by now.
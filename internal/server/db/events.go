@@ -0,0 +1,62 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// CreateEvent records an event in the event history and returns its ID.
+func (c *ClusterTx) CreateEvent(ctx context.Context, event api.Event) (int64, error) {
+	q := `INSERT INTO events (type, timestamp, location, project, metadata) VALUES (?, ?, ?, ?, ?)`
+
+	result, err := c.tx.ExecContext(ctx, q, event.Type, event.Timestamp.UTC(), event.Location, event.Project, string(event.Metadata))
+	if err != nil {
+		return -1, fmt.Errorf("Failed recording event: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// GetEvents returns the events of the event history with an ID greater than the given one, oldest first.
+func (c *ClusterTx) GetEvents(ctx context.Context, since int64) ([]api.Event, error) {
+	q := `SELECT id, type, timestamp, location, project, metadata FROM events WHERE id > ? ORDER BY id`
+
+	result := []api.Event{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		event := api.Event{}
+		var metadata string
+
+		err := scan(&event.ID, &event.Type, &event.Timestamp, &event.Location, &event.Project, &metadata)
+		if err != nil {
+			return err
+		}
+
+		event.Metadata = json.RawMessage(metadata)
+		result = append(result, event)
+
+		return nil
+	}, since)
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching events: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeleteOldEvents removes the events of the event history, keeping only the given number of most recent ones.
+func (c *ClusterTx) DeleteOldEvents(ctx context.Context, keep int64) error {
+	q := `DELETE FROM events WHERE id <= (SELECT MAX(id) FROM events) - ?`
+
+	_, err := c.tx.ExecContext(ctx, q, keep)
+	if err != nil {
+		return fmt.Errorf("Failed removing old events: %w", err)
+	}
+
+	return nil
+}
@@ -1031,8 +1031,8 @@ ORDER BY instances_snapshots.creation_date, instances_snapshots.id
 	return max
 }
 
-// DeleteReadyStateFromLocalInstances deletes the volatile.last_state.ready config key
-// from all local instances.
+// DeleteReadyStateFromLocalInstances deletes the volatile.last_state.ready and
+// volatile.last_state.health config keys from all local instances.
 func (c *ClusterTx) DeleteReadyStateFromLocalInstances(ctx context.Context) error {
 	nodeID := c.GetNodeID()
 
@@ -1042,7 +1042,7 @@ WHERE instances_config.id IN (
 	SELECT instances_config.id FROM instances_config
 	JOIN instances ON instances_config.instance_id=instances.id
 	JOIN nodes ON instances.node_id=nodes.id
-	WHERE key="volatile.last_state.ready" AND nodes.id=?
+	WHERE key IN ("volatile.last_state.ready", "volatile.last_state.health") AND nodes.id=?
 )`, nodeID)
 	if err != nil {
 		return fmt.Errorf("Failed deleting ready state from local instances: %w", err)
@@ -0,0 +1,172 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// projectTemplateDefinition is the part of a project template stored as JSON.
+type projectTemplateDefinition struct {
+	Config   map[string]string  `json:"config"`
+	Profiles []api.ProfilesPost `json:"profiles"`
+	Networks []api.NetworksPost `json:"networks"`
+}
+
+func scanProjectTemplate(scan func(dest ...any) error) (*api.ProjectTemplate, error) {
+	template := api.ProjectTemplate{}
+	var definition string
+
+	err := scan(&template.Name, &template.Description, &definition)
+	if err != nil {
+		return nil, err
+	}
+
+	def := projectTemplateDefinition{}
+	err = json.Unmarshal([]byte(definition), &def)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing definition of project template %q: %w", template.Name, err)
+	}
+
+	template.Config = def.Config
+	template.Profiles = def.Profiles
+	template.Networks = def.Networks
+
+	if template.Config == nil {
+		template.Config = map[string]string{}
+	}
+
+	if template.Profiles == nil {
+		template.Profiles = []api.ProfilesPost{}
+	}
+
+	if template.Networks == nil {
+		template.Networks = []api.NetworksPost{}
+	}
+
+	return &template, nil
+}
+
+func projectTemplateDefinitionJSON(put api.ProjectTemplatePut) (string, error) {
+	definition, err := json.Marshal(projectTemplateDefinition{
+		Config:   put.Config,
+		Profiles: put.Profiles,
+		Networks: put.Networks,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(definition), nil
+}
+
+// GetProjectTemplates returns all the project templates.
+func (c *ClusterTx) GetProjectTemplates(ctx context.Context) ([]api.ProjectTemplate, error) {
+	q := `SELECT name, description, definition FROM projects_templates ORDER BY name`
+
+	result := []api.ProjectTemplate{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		template, err := scanProjectTemplate(scan)
+		if err != nil {
+			return err
+		}
+
+		result = append(result, *template)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading project templates: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetProjectTemplate returns the project template with the given name.
+func (c *ClusterTx) GetProjectTemplate(ctx context.Context, name string) (*api.ProjectTemplate, error) {
+	q := `SELECT name, description, definition FROM projects_templates WHERE name = ?`
+
+	template, err := scanProjectTemplate(c.tx.QueryRowContext(ctx, q, name).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Project template not found")
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed loading project template %q: %w", name, err)
+	}
+
+	return template, nil
+}
+
+// CreateProjectTemplate adds a new project template.
+func (c *ClusterTx) CreateProjectTemplate(ctx context.Context, template api.ProjectTemplatesPost) error {
+	definition, err := projectTemplateDefinitionJSON(template.ProjectTemplatePut)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	err = c.tx.QueryRowContext(ctx, `SELECT count(*) FROM projects_templates WHERE name = ?`, template.Name).Scan(&count)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return api.StatusErrorf(http.StatusConflict, "A project template with name %q already exists", template.Name)
+	}
+
+	_, err = c.tx.ExecContext(ctx, `INSERT INTO projects_templates (name, description, definition) VALUES (?, ?, ?)`, template.Name, template.Description, definition)
+	if err != nil {
+		return fmt.Errorf("Failed creating project template %q: %w", template.Name, err)
+	}
+
+	return nil
+}
+
+// UpdateProjectTemplate updates the project template with the given name.
+func (c *ClusterTx) UpdateProjectTemplate(ctx context.Context, name string, put api.ProjectTemplatePut) error {
+	definition, err := projectTemplateDefinitionJSON(put)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.tx.ExecContext(ctx, `UPDATE projects_templates SET description = ?, definition = ? WHERE name = ?`, put.Description, definition, name)
+	if err != nil {
+		return fmt.Errorf("Failed updating project template %q: %w", name, err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Project template not found")
+	}
+
+	return nil
+}
+
+// DeleteProjectTemplate deletes the project template with the given name.
+func (c *ClusterTx) DeleteProjectTemplate(ctx context.Context, name string) error {
+	result, err := c.tx.ExecContext(ctx, `DELETE FROM projects_templates WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("Failed deleting project template %q: %w", name, err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Project template not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+)
+
+// InstanceUsage is the resource usage of an instance during an hour.
+type InstanceUsage struct {
+	Project  string
+	Instance string
+	Type     instancetype.Type
+
+	// Start of the hour.
+	Date time.Time
+
+	// Number of samples aggregated in the record.
+	Samples int64
+
+	// CPU time and network traffic during the hour.
+	CPUSeconds           float64
+	NetworkReceivedBytes int64
+	NetworkSentBytes     int64
+
+	// Average memory usage and maximum disk usage during the hour.
+	MemoryBytes int64
+	DiskBytes   int64
+
+	// Counter values at the last sample, used to compute the usage since then.
+	CPUSecondsCounter      float64
+	NetworkReceivedCounter int64
+	NetworkSentCounter     int64
+}
+
+const instanceUsageColumns = `projects.name, projects_usage.instance_name, projects_usage.instance_type, projects_usage.date, projects_usage.samples,
+  projects_usage.cpu_seconds, projects_usage.network_received_bytes, projects_usage.network_sent_bytes,
+  projects_usage.memory_bytes, projects_usage.disk_bytes,
+  projects_usage.cpu_seconds_counter, projects_usage.network_received_counter, projects_usage.network_sent_counter`
+
+func scanInstanceUsage(scan func(dest ...any) error) (*InstanceUsage, error) {
+	u := InstanceUsage{}
+
+	err := scan(&u.Project, &u.Instance, &u.Type, &u.Date, &u.Samples,
+		&u.CPUSeconds, &u.NetworkReceivedBytes, &u.NetworkSentBytes,
+		&u.MemoryBytes, &u.DiskBytes,
+		&u.CPUSecondsCounter, &u.NetworkReceivedCounter, &u.NetworkSentCounter)
+	if err != nil {
+		return nil, err
+	}
+
+	u.Date = u.Date.UTC()
+
+	return &u, nil
+}
+
+// GetLastInstanceUsage returns the most recent usage record of the instance, or nil if there's none.
+func (c *ClusterTx) GetLastInstanceUsage(ctx context.Context, projectName string, instanceName string) (*InstanceUsage, error) {
+	q := `SELECT ` + instanceUsageColumns + `
+FROM projects_usage
+JOIN projects ON projects.id = projects_usage.project_id
+WHERE projects.name = ? AND projects_usage.instance_name = ?
+ORDER BY projects_usage.date DESC
+LIMIT 1`
+
+	usage, err := scanInstanceUsage(c.tx.QueryRowContext(ctx, q, projectName, instanceName).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed loading usage of instance %q in project %q: %w", instanceName, projectName, err)
+	}
+
+	return usage, nil
+}
+
+// UpsertInstanceUsage creates or replaces the usage record of the instance for the hour of the record.
+func (c *ClusterTx) UpsertInstanceUsage(ctx context.Context, usage InstanceUsage) error {
+	q := `
+INSERT OR REPLACE INTO projects_usage (project_id, instance_name, instance_type, date, samples,
+  cpu_seconds, network_received_bytes, network_sent_bytes, memory_bytes, disk_bytes,
+  cpu_seconds_counter, network_received_counter, network_sent_counter)
+VALUES ((SELECT projects.id FROM projects WHERE projects.name = ?), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := c.tx.ExecContext(ctx, q, usage.Project, usage.Instance, usage.Type, usage.Date.UTC(), usage.Samples,
+		usage.CPUSeconds, usage.NetworkReceivedBytes, usage.NetworkSentBytes, usage.MemoryBytes, usage.DiskBytes,
+		usage.CPUSecondsCounter, usage.NetworkReceivedCounter, usage.NetworkSentCounter)
+	if err != nil {
+		return fmt.Errorf("Failed recording usage of instance %q in project %q: %w", usage.Instance, usage.Project, err)
+	}
+
+	return nil
+}
+
+// GetProjectUsage returns the usage records of the instances of the project for the hours starting between from
+// (inclusive) and to (exclusive).
+func (c *ClusterTx) GetProjectUsage(ctx context.Context, projectName string, from time.Time, to time.Time) ([]InstanceUsage, error) {
+	q := `SELECT ` + instanceUsageColumns + `
+FROM projects_usage
+JOIN projects ON projects.id = projects_usage.project_id
+WHERE projects.name = ? AND projects_usage.date >= ? AND projects_usage.date < ?
+ORDER BY projects_usage.date, projects_usage.instance_name`
+
+	result := []InstanceUsage{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		usage, err := scanInstanceUsage(scan)
+		if err != nil {
+			return err
+		}
+
+		result = append(result, *usage)
+		return nil
+	}, projectName, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading usage of project %q: %w", projectName, err)
+	}
+
+	return result, nil
+}
+
+// DeleteInstanceUsageBefore deletes the usage records of the hours starting before the given time.
+func (c *ClusterTx) DeleteInstanceUsageBefore(ctx context.Context, before time.Time) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM projects_usage WHERE date < ?", before.UTC())
+	if err != nil {
+		return fmt.Errorf("Failed deleting project usage records: %w", err)
+	}
+
+	return nil
+}
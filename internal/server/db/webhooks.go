@@ -0,0 +1,224 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// webhookDeadLettersMax is the number of undelivered events kept for each webhook.
+const webhookDeadLettersMax = 1000
+
+// splitWebhookList converts a comma separated list as stored in the database into a slice.
+func splitWebhookList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+
+	return strings.Split(value, ",")
+}
+
+func scanWebhook(scan func(dest ...any) error) (*api.Webhook, error) {
+	webhook := api.Webhook{}
+	var types, projects string
+
+	err := scan(&webhook.Name, &webhook.Description, &webhook.URL, &webhook.Secret, &types, &projects)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Types = splitWebhookList(types)
+	webhook.Projects = splitWebhookList(projects)
+
+	return &webhook, nil
+}
+
+// GetWebhooks returns all the webhooks.
+func (c *ClusterTx) GetWebhooks(ctx context.Context) ([]api.Webhook, error) {
+	q := `SELECT name, description, url, secret, types, projects FROM webhooks ORDER BY name`
+
+	result := []api.Webhook{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		webhook, err := scanWebhook(scan)
+		if err != nil {
+			return err
+		}
+
+		result = append(result, *webhook)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading webhooks: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetWebhook returns the webhook with the given name.
+func (c *ClusterTx) GetWebhook(ctx context.Context, name string) (*api.Webhook, error) {
+	q := `SELECT name, description, url, secret, types, projects FROM webhooks WHERE name = ?`
+
+	webhook, err := scanWebhook(c.tx.QueryRowContext(ctx, q, name).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Webhook not found")
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed loading webhook %q: %w", name, err)
+	}
+
+	return webhook, nil
+}
+
+// CreateWebhook adds a new webhook.
+func (c *ClusterTx) CreateWebhook(ctx context.Context, webhook api.WebhooksPost) error {
+	var count int
+	err := c.tx.QueryRowContext(ctx, `SELECT count(*) FROM webhooks WHERE name = ?`, webhook.Name).Scan(&count)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return api.StatusErrorf(http.StatusConflict, "A webhook with name %q already exists", webhook.Name)
+	}
+
+	q := `INSERT INTO webhooks (name, description, url, secret, types, projects) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err = c.tx.ExecContext(ctx, q, webhook.Name, webhook.Description, webhook.URL, webhook.Secret, strings.Join(webhook.Types, ","), strings.Join(webhook.Projects, ","))
+	if err != nil {
+		return fmt.Errorf("Failed creating webhook %q: %w", webhook.Name, err)
+	}
+
+	return nil
+}
+
+// UpdateWebhook updates the webhook with the given name.
+func (c *ClusterTx) UpdateWebhook(ctx context.Context, name string, put api.WebhookPut) error {
+	q := `UPDATE webhooks SET description = ?, url = ?, secret = ?, types = ?, projects = ? WHERE name = ?`
+	result, err := c.tx.ExecContext(ctx, q, put.Description, put.URL, put.Secret, strings.Join(put.Types, ","), strings.Join(put.Projects, ","), name)
+	if err != nil {
+		return fmt.Errorf("Failed updating webhook %q: %w", name, err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Webhook not found")
+	}
+
+	return nil
+}
+
+// DeleteWebhook deletes the webhook with the given name, along with its undelivered events.
+func (c *ClusterTx) DeleteWebhook(ctx context.Context, name string) error {
+	result, err := c.tx.ExecContext(ctx, `DELETE FROM webhooks WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("Failed deleting webhook %q: %w", name, err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Webhook not found")
+	}
+
+	return nil
+}
+
+// GetWebhookDeadLetters returns the events that couldn't be delivered to the webhook with the given name, oldest first.
+func (c *ClusterTx) GetWebhookDeadLetters(ctx context.Context, name string) ([]api.WebhookDeadLetter, error) {
+	q := `
+SELECT webhooks_dead_letters.event, webhooks_dead_letters.attempts, webhooks_dead_letters.error, webhooks_dead_letters.failed_at
+  FROM webhooks_dead_letters
+  JOIN webhooks ON webhooks.id = webhooks_dead_letters.webhook_id
+  WHERE webhooks.name = ?
+  ORDER BY webhooks_dead_letters.id
+`
+
+	result := []api.WebhookDeadLetter{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		letter := api.WebhookDeadLetter{}
+		var event string
+
+		err := scan(&event, &letter.Attempts, &letter.Error, &letter.FailedAt)
+		if err != nil {
+			return err
+		}
+
+		err = json.Unmarshal([]byte(event), &letter.Event)
+		if err != nil {
+			return fmt.Errorf("Failed parsing undelivered event: %w", err)
+		}
+
+		result = append(result, letter)
+
+		return nil
+	}, name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading undelivered events of webhook %q: %w", name, err)
+	}
+
+	return result, nil
+}
+
+// CreateWebhookDeadLetter records an event that couldn't be delivered to the webhook with the given name.
+// Only the most recent undelivered events of each webhook are kept.
+func (c *ClusterTx) CreateWebhookDeadLetter(ctx context.Context, name string, letter api.WebhookDeadLetter) error {
+	webhook, err := c.GetWebhook(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	event, err := json.Marshal(letter.Event)
+	if err != nil {
+		return err
+	}
+
+	q := `
+INSERT INTO webhooks_dead_letters (webhook_id, event, attempts, error, failed_at)
+  VALUES ((SELECT id FROM webhooks WHERE name = ?), ?, ?, ?, ?)
+`
+	_, err = c.tx.ExecContext(ctx, q, webhook.Name, string(event), letter.Attempts, letter.Error, letter.FailedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("Failed recording undelivered event of webhook %q: %w", name, err)
+	}
+
+	q = `
+DELETE FROM webhooks_dead_letters
+  WHERE webhook_id = (SELECT id FROM webhooks WHERE name = ?)
+  AND id NOT IN (SELECT id FROM webhooks_dead_letters WHERE webhook_id = (SELECT id FROM webhooks WHERE name = ?) ORDER BY id DESC LIMIT ?)
+`
+	_, err = c.tx.ExecContext(ctx, q, webhook.Name, webhook.Name, webhookDeadLettersMax)
+	if err != nil {
+		return fmt.Errorf("Failed removing old undelivered events of webhook %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// DeleteWebhookDeadLetters removes the events that couldn't be delivered to the webhook with the given name.
+func (c *ClusterTx) DeleteWebhookDeadLetters(ctx context.Context, name string) error {
+	_, err := c.GetWebhook(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, `DELETE FROM webhooks_dead_letters WHERE webhook_id = (SELECT id FROM webhooks WHERE name = ?)`, name)
+	if err != nil {
+		return fmt.Errorf("Failed removing undelivered events of webhook %q: %w", name, err)
+	}
+
+	return nil
+}
@@ -40,6 +40,9 @@ type Server struct {
 	listeners map[string]*Listener
 	notify    NotifyFunc
 	location  string
+
+	history      chan api.Event
+	historyTypes []string
 }
 
 // NewServer returns a new event server.
@@ -107,6 +110,21 @@ func (s *Server) AddListener(projectName string, allProjects bool, projectPermis
 	return listener, nil
 }
 
+// ListenerCounts returns the number of connected listeners for each event type.
+func (s *Server) ListenerCounts() map[string]int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	counts := map[string]int{}
+	for _, listener := range s.listeners {
+		for _, messageType := range listener.messageTypes {
+			counts[messageType]++
+		}
+	}
+
+	return counts
+}
+
 // SendLifecycle broadcasts a lifecycle event.
 func (s *Server) SendLifecycle(projectName string, event api.EventLifecycle) {
 	_ = s.Send(projectName, api.EventTypeLifecycle, event)
@@ -126,6 +144,11 @@ func (s *Server) Send(projectName string, eventType string, eventMessage any) er
 		Project:   projectName,
 	}
 
+	// Events recorded in the history are broadcast once they have been recorded.
+	if s.record(event) {
+		return nil
+	}
+
 	return s.broadcast(event, EventSourceLocal)
 }
 
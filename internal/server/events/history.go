@@ -0,0 +1,135 @@
+package events
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// historyQueueSize is the number of local events that can be waiting to be recorded in the history.
+const historyQueueSize = 1024
+
+// HistoryFunc records an event in the event history and returns its ID.
+type HistoryFunc func(event api.Event) (int64, error)
+
+// SetHistory records the local events of the given types through the given function before they're
+// broadcast, so that they carry their ID in the event history. It can only be called once.
+func (s *Server) SetHistory(eventTypes []string, f HistoryFunc) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.history != nil {
+		return
+	}
+
+	s.historyTypes = eventTypes
+	s.history = make(chan api.Event, historyQueueSize)
+
+	go func() {
+		for event := range s.history {
+			id, err := f(event)
+			if err != nil {
+				logger.Warn("Failed recording event in the event history", logger.Ctx{"type": event.Type, "err": err})
+			} else {
+				event.ID = id
+			}
+
+			_ = s.broadcast(event, EventSourceLocal)
+		}
+	}()
+}
+
+// record queues a local event to be recorded in the history and then broadcast.
+// Returns false if the event isn't to be recorded, in which case it should be broadcast right away.
+func (s *Server) record(event api.Event) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.history == nil || !slices.Contains(s.historyTypes, event.Type) {
+		return false
+	}
+
+	// Set the location now as it's recorded along with the event.
+	if event.Location == "" {
+		event.Location = s.location
+	}
+
+	select {
+	case s.history <- event:
+		return true
+	default:
+		logger.Warn("Event history queue is full, broadcasting event without recording it", logger.Ctx{"type": event.Type})
+		return false
+	}
+}
+
+// ReplayListenerConnection is an event listener connection that holds back the events sent to it until the
+// events from the history have been replayed, so that no event is missed or sent twice.
+type ReplayListenerConnection struct {
+	EventListenerConnection
+
+	lock      sync.Mutex
+	replaying bool
+	pending   []any
+	lastID    int64
+}
+
+// NewReplayListenerConnection returns a new listener connection holding back events until Replay is called.
+func NewReplayListenerConnection(connection EventListenerConnection) *ReplayListenerConnection {
+	return &ReplayListenerConnection{
+		EventListenerConnection: connection,
+		replaying:               true,
+	}
+}
+
+// WriteJSON sends the event, unless it has already been replayed, or queues it while replaying.
+func (c *ReplayListenerConnection) WriteJSON(event any) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.replaying {
+		c.pending = append(c.pending, event)
+		return nil
+	}
+
+	return c.write(event)
+}
+
+// Replay sends the events from the history, then the events that were held back in the meantime.
+func (c *ReplayListenerConnection) Replay(events []api.Event) error {
+	for _, event := range events {
+		err := c.EventListenerConnection.WriteJSON(event)
+		if err != nil {
+			return err
+		}
+
+		c.lastID = max(c.lastID, event.ID)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, event := range c.pending {
+		err := c.write(event)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.pending = nil
+	c.replaying = false
+
+	return nil
+}
+
+// write sends the event unless it's a recorded event that has already been replayed.
+func (c *ReplayListenerConnection) write(event any) error {
+	e, ok := event.(api.Event)
+	if ok && e.ID != 0 && e.ID <= c.lastID {
+		return nil
+	}
+
+	return c.EventListenerConnection.WriteJSON(event)
+}
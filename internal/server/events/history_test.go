@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type recordingListenerConnection struct {
+	events []any
+}
+
+func (c *recordingListenerConnection) Reader(ctx context.Context, recvFunc EventHandler) {}
+
+func (c *recordingListenerConnection) WriteJSON(event any) error {
+	c.events = append(c.events, event)
+	return nil
+}
+
+func (c *recordingListenerConnection) Close() error         { return nil }
+func (c *recordingListenerConnection) LocalAddr() net.Addr  { return nil }
+func (c *recordingListenerConnection) RemoteAddr() net.Addr { return nil }
+
+func TestReplayListenerConnection(t *testing.T) {
+	conn := &recordingListenerConnection{}
+	replay := NewReplayListenerConnection(conn)
+
+	// Events sent while replaying are held back.
+	require.NoError(t, replay.WriteJSON(api.Event{ID: 3}))
+	require.NoError(t, replay.WriteJSON(api.Event{Type: api.EventTypeOperation}))
+	require.NoError(t, replay.WriteJSON(api.Event{ID: 4}))
+	assert.Empty(t, conn.events)
+
+	// The held back events that were already replayed are skipped.
+	require.NoError(t, replay.Replay([]api.Event{{ID: 2}, {ID: 3}}))
+	require.NoError(t, replay.WriteJSON(api.Event{ID: 5}))
+
+	assert.Equal(t, []any{
+		api.Event{ID: 2},
+		api.Event{ID: 3},
+		api.Event{Type: api.EventTypeOperation},
+		api.Event{ID: 4},
+		api.Event{ID: 5},
+	}, conn.events)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -759,6 +760,139 @@ func (d *common) snapshotCommon(inst instance.Instance, name string, expiry time
 	return nil
 }
 
+// snapshotHooksRun runs the pre-snapshot hook of a running instance and returns a function running the
+// post-snapshot one. The post-snapshot hook is expected to be called whether or not the snapshot succeeded.
+func (d *common) snapshotHooksRun(inst instance.Instance) (func() error, error) {
+	if !inst.IsRunning() {
+		return func() error { return nil }, nil
+	}
+
+	err := d.snapshotHookRun(inst, "snapshots.hooks.pre")
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error { return d.snapshotHookRun(inst, "snapshots.hooks.post") }, nil
+}
+
+// snapshotHookRun runs the snapshot hook command stored in the provided configuration key.
+func (d *common) snapshotHookRun(inst instance.Instance, key string) error {
+	command := d.expandedConfig[key]
+	if command == "" {
+		return nil
+	}
+
+	timeout := 30 * time.Second
+	if d.expandedConfig["snapshots.hooks.timeout"] != "" {
+		seconds, err := strconv.Atoi(d.expandedConfig["snapshots.hooks.timeout"])
+		if err != nil {
+			return fmt.Errorf("Invalid snapshots.hooks.timeout: %w", err)
+		}
+
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	d.logger.Debug("Running snapshot hook", logger.Ctx{"hook": key, "command": command})
+
+	_, err := d.execCommand(inst, []string{"/bin/sh", "-c", command}, timeout)
+	if err != nil {
+		if d.expandedConfig["snapshots.hooks.on_failure"] == "continue" {
+			d.logger.Warn("Snapshot hook failed, continuing", logger.Ctx{"hook": key, "err": err})
+			return nil
+		}
+
+		return fmt.Errorf("Failed running %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// execCommand runs a command as root inside of the running instance and returns its combined output.
+// The command is killed if it doesn't complete within the timeout.
+func (d *common) execCommand(inst instance.Instance, command []string, timeout time.Duration) (string, error) {
+	stdin, err := os.Open(os.DevNull)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = stdin.Close() }()
+
+	output, err := os.CreateTemp("", "incus_exec_")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = output.Close()
+		_ = os.Remove(output.Name())
+	}()
+
+	env := map[string]string{
+		"PATH": "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+		"HOME": "/root",
+		"USER": "root",
+		"LANG": "C.UTF-8",
+	}
+
+	for k, v := range d.expandedConfig {
+		if strings.HasPrefix(k, "environment.") {
+			env[strings.TrimPrefix(k, "environment.")] = v
+		}
+	}
+
+	req := api.InstanceExecPost{
+		Command:     command,
+		Environment: env,
+		Cwd:         "/root",
+	}
+
+	cmd, err := inst.Exec(req, stdin, output, output)
+	if err != nil {
+		return "", err
+	}
+
+	type execResult struct {
+		exitCode int
+		err      error
+	}
+
+	chResult := make(chan execResult, 1)
+	go func() {
+		exitCode, err := cmd.Wait()
+		chResult <- execResult{exitCode: exitCode, err: err}
+	}()
+
+	var res execResult
+	select {
+	case res = <-chResult:
+	case <-time.After(timeout):
+		_ = cmd.Signal(syscall.SIGKILL)
+		<-chResult
+
+		return "", fmt.Errorf("Command timed out after %s", timeout)
+	}
+
+	_, err = output.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := io.ReadAll(output)
+	if err != nil {
+		return "", err
+	}
+
+	if res.err != nil {
+		return string(out), res.err
+	}
+
+	if res.exitCode != 0 {
+		return string(out), fmt.Errorf("Command exited with status %d: %s", res.exitCode, strings.TrimSpace(string(out)))
+	}
+
+	return string(out), nil
+}
+
 // updateProgress updates the operation metadata with a new progress string.
 func (d *common) updateProgress(progress string) {
 	if d.op == nil {
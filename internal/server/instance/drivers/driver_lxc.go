@@ -40,6 +40,7 @@ import (
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/instancewriter"
+	"github.com/lxc/incus/v6/internal/inventory"
 	internalIO "github.com/lxc/incus/v6/internal/io"
 	"github.com/lxc/incus/v6/internal/jmap"
 	"github.com/lxc/incus/v6/internal/linux"
@@ -1417,6 +1418,14 @@ func (d *lxc) devIncusEventSend(eventType string, eventMessage map[string]any) e
 // RegisterDevices calls the Register() function on all of the instance's devices.
 func (d *lxc) RegisterDevices() {
 	d.devicesRegister(d)
+
+	// Resume forwarding the console output, the syslog socket is only recreated on next start.
+	if util.IsTrue(d.expandedConfig["logging.console"]) && !d.logForwardingActive() {
+		err := d.startLogForwarding(d.ConsoleBufferLogPath(), false, false, "")
+		if err != nil {
+			d.logger.Warn("Failed resuming log forwarding", logger.Ctx{"err": err})
+		}
+	}
 }
 
 // deviceStart loads a new device and calls its Start() function.
@@ -2456,6 +2465,29 @@ ff02::2 ip6-allrouters
 		}
 	}
 
+	// Forward the console output and syslog messages to the remote logging targets.
+	if util.IsTrue(d.expandedConfig["logging.console"]) {
+		// Only forward the output of this run.
+		err = os.Remove(d.ConsoleBufferLogPath())
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", nil, err
+		}
+
+		syslogPath := filepath.Join(d.RunPath(), "syslog.socket")
+
+		err = d.startLogForwarding(d.ConsoleBufferLogPath(), true, false, syslogPath)
+		if err != nil {
+			return "", nil, fmt.Errorf("Failed starting log forwarding: %w", err)
+		}
+
+		revert.Add(d.stopLogForwarding)
+
+		err = lxcSetConfigItem(cc, "lxc.mount.entry", fmt.Sprintf("%s dev/log none bind,create=file,optional 0 0", syslogPath))
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
 	// Check if we should start a dedicated LXCFS.
 	if d.state.GlobalConfig.InstancesLXCFSPerInstance() {
 		if !util.PathExists(filepath.Join(d.RunPath(), "lxcfs", "proc")) {
@@ -2770,6 +2802,8 @@ func (d *lxc) Start(stateful bool) error {
 
 		d.logger.Error("Failed starting instance", ctxMap)
 
+		d.stopLogForwarding()
+
 		// Return the actual error
 		op.Done(err)
 		return err
@@ -3220,8 +3254,9 @@ func (d *lxc) onStop(args map[string]string) error {
 
 	// Record power state.
 	err = d.VolatileSet(map[string]string{
-		"volatile.last_state.power": instance.PowerStateStopped,
-		"volatile.last_state.ready": "false",
+		"volatile.last_state.power":  instance.PowerStateStopped,
+		"volatile.last_state.ready":  "false",
+		"volatile.last_state.health": "",
 	})
 	if err != nil {
 		// Don't return an error here as we still want to cleanup the instance even if DB not available.
@@ -3246,6 +3281,9 @@ func (d *lxc) onStop(args map[string]string) error {
 		// This is to required so we can actually unmount the container.
 		d.stopForkfile(false)
 
+		// Stop forwarding the console output and syslog messages.
+		d.stopLogForwarding()
+
 		// Clean up devices.
 		d.cleanupDevices(false, "")
 
@@ -3666,6 +3704,7 @@ func (d *lxc) renderState(statusCode api.StatusCode, hostInterfaces []net.Interf
 		status.Network = d.networkState(hostInterfaces)
 		status.Pid = int64(pid)
 		status.Processes = processesState
+		status.Health = d.LocalConfig()["volatile.last_state.health"]
 
 		status.StartedAt, err = d.processStartedAt(d.InitPID())
 		if err != nil {
@@ -3781,7 +3820,18 @@ func (d *lxc) Snapshot(name string, expiry time.Time, stateful bool) error {
 
 	defer unlock()
 
-	return d.snapshot(name, expiry, stateful)
+	postHook, err := d.snapshotHooksRun(d)
+	if err != nil {
+		return err
+	}
+
+	err = d.snapshot(name, expiry, stateful)
+	hookErr := postHook()
+	if err != nil {
+		return err
+	}
+
+	return hookErr
 }
 
 // Restore restores a snapshot.
@@ -7089,6 +7139,29 @@ func (d *lxc) inheritInitPidFd() (int, *os.File) {
 	return -1, nil
 }
 
+// forkfileSysProcAttr returns the process attributes forkfile needs to operate on the shifted disk of a stopped container.
+func (d *lxc) forkfileSysProcAttr() (*syscall.SysProcAttr, error) {
+	// Get the disk idmap.
+	idmapset, err := d.DiskIdmap()
+	if err != nil {
+		return nil, err
+	}
+
+	if idmapset == nil {
+		return nil, nil
+	}
+
+	return &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWUSER,
+		Credential: &syscall.Credential{
+			Uid: uint32(0),
+			Gid: uint32(0),
+		},
+		UidMappings: idmapset.ToUIDMappings(),
+		GidMappings: idmapset.ToGIDMappings(),
+	}, nil
+}
+
 // FileSFTPConn returns a connection to the forkfile handler.
 func (d *lxc) FileSFTPConn() (net.Conn, error) {
 	// Lock to avoid concurrent spawning.
@@ -7234,24 +7307,11 @@ func (d *lxc) FileSFTPConn() (net.Conn, error) {
 		forkfile.Stderr = &stderr
 
 		if !d.IsRunning() {
-			// Get the disk idmap.
-			idmapset, err := d.DiskIdmap()
+			forkfile.SysProcAttr, err = d.forkfileSysProcAttr()
 			if err != nil {
 				chReady <- err
 				return
 			}
-
-			if idmapset != nil {
-				forkfile.SysProcAttr = &syscall.SysProcAttr{
-					Cloneflags: syscall.CLONE_NEWUSER,
-					Credential: &syscall.Credential{
-						Uid: uint32(0),
-						Gid: uint32(0),
-					},
-					UidMappings: idmapset.ToUIDMappings(),
-					GidMappings: idmapset.ToGIDMappings(),
-				}
-			}
 		}
 
 		// Start the server.
@@ -7329,6 +7389,245 @@ func (d *lxc) FileSFTP() (*sftp.Client, error) {
 	return client, nil
 }
 
+// PortForwardConn returns a connection to a TCP port listening inside the container.
+func (d *lxc) PortForwardConn(port int) (net.Conn, error) {
+	if !d.IsRunning() {
+		return nil, fmt.Errorf("Instance is not running")
+	}
+
+	pid := d.InitPID()
+	if pid < 1 {
+		return nil, fmt.Errorf("Failed getting the container's init PID")
+	}
+
+	// The daemon keeps one end of the socket pair while forknet uses the other as its stdin and stdout.
+	fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating socket pair: %w", err)
+	}
+
+	localFile := os.NewFile(uintptr(fds[0]), "port-forward")
+	defer func() { _ = localFile.Close() }()
+
+	remoteFile := os.NewFile(uintptr(fds[1]), "port-forward")
+	defer func() { _ = remoteFile.Close() }()
+
+	pidFdNr, pidFd := d.inheritInitPidFd()
+
+	var stderr bytes.Buffer
+	cmd := exec.Cmd{}
+	cmd.Path = d.state.OS.ExecPath
+	cmd.Args = []string{
+		d.state.OS.ExecPath,
+		"forknet",
+		"connect",
+		"--",
+		fmt.Sprintf("%d", pid),
+		fmt.Sprintf("%d", pidFdNr),
+		net.JoinHostPort("localhost", fmt.Sprintf("%d", port)),
+	}
+
+	cmd.Stdin = remoteFile
+	cmd.Stdout = remoteFile
+	cmd.Stderr = &stderr
+
+	if pidFd != nil {
+		cmd.ExtraFiles = []*os.File{pidFd}
+	}
+
+	err = cmd.Start()
+	if pidFd != nil {
+		_ = pidFd.Close()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed starting forknet: %w", err)
+	}
+
+	go func() {
+		err := cmd.Wait()
+		if err != nil {
+			d.logger.Debug("Port forwarding stopped with error", logger.Ctx{"port": port, "err": err, "stderr": strings.TrimSpace(stderr.String())})
+		}
+	}()
+
+	// net.FileConn duplicates the file descriptor so the deferred close of localFile is safe.
+	conn, err := net.FileConn(localFile)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// SSHKeys returns the authorized SSH keys of a guest user.
+func (d *lxc) SSHKeys(username string) ([]string, error) {
+	client, err := d.FileSFTP()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = client.Close() }()
+
+	u, err := guestUserLookup(client, username)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := client.Open(path.Join(u.home, internalInstance.SSHAuthorizedKeysPath))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []string{}, nil
+		}
+
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return internalInstance.ParseAuthorizedKeys(content), nil
+}
+
+// SetSSHKeys replaces the authorized SSH keys of a guest user, creating the user if missing.
+func (d *lxc) SetSSHKeys(username string, keys []string) error {
+	client, err := d.FileSFTP()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = client.Close() }()
+
+	u, err := guestUserLookup(client, username)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		if !d.IsRunning() {
+			return fmt.Errorf("User %q doesn't exist and can only be created while the instance is running", username)
+		}
+
+		// Create the missing user, falling back to busybox's adduser when useradd isn't available.
+		_, err = d.execCommand(d, []string{"/bin/sh", "-c", `useradd --create-home "$1" || adduser -D "$1"`, "sh", username}, time.Minute)
+		if err != nil {
+			return fmt.Errorf("Failed creating user %q: %w", username, err)
+		}
+
+		d.logger.Info("Created guest user", logger.Ctx{"user": username})
+
+		u, err = guestUserLookup(client, username)
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.forkfileSSHKeys(u, keys)
+}
+
+// forkfileSSHKeys replaces the authorized SSH keys of a guest user through forkfile.
+// The guest user could replace the files and directories in its home directory with symlinks, so rather than
+// going through SFTP, forkfile walks down from the home directory from within the container without following them.
+func (d *lxc) forkfileSSHKeys(u *guestUser, keys []string) error {
+	// Mount the root filesystem if required.
+	if !d.IsRunning() {
+		_, err := d.mount()
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = d.unmount() }()
+	}
+
+	// Get the rootfs.
+	rootfsFile, err := os.Open(d.RootfsPath())
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = rootfsFile.Close() }()
+
+	args := []string{
+		d.state.OS.ExecPath,
+		"forkfile",
+		"ssh-keys",
+		"3",
+	}
+
+	extraFiles := []*os.File{rootfsFile}
+
+	// Get the pidfd.
+	pidFdNr, pidFd := d.inheritInitPidFd()
+	if pidFdNr >= 0 {
+		defer func() { _ = pidFd.Close() }()
+		args = append(args, "4")
+		extraFiles = append(extraFiles, pidFd)
+	} else {
+		args = append(args, "-1")
+	}
+
+	args = append(args, fmt.Sprintf("%d", d.InitPID()), u.home, fmt.Sprintf("%d", u.uid), fmt.Sprintf("%d", u.gid))
+
+	forkfile := exec.Cmd{
+		Path:       d.state.OS.ExecPath,
+		Args:       args,
+		ExtraFiles: extraFiles,
+		Stdin:      bytes.NewReader(internalInstance.RenderAuthorizedKeys(keys)),
+	}
+
+	var stderr bytes.Buffer
+	forkfile.Stderr = &stderr
+
+	if !d.IsRunning() {
+		forkfile.SysProcAttr, err = d.forkfileSysProcAttr()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = forkfile.Run()
+	if err != nil {
+		return fmt.Errorf("Failed to run forkfile: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// Inventory returns the software inventory of the instance, read from its root filesystem.
+func (d *lxc) Inventory() (*api.InstanceInventory, error) {
+	client, err := d.FileSFTP()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = client.Close() }()
+
+	// Commands can only be run in running containers.
+	var run inventory.Runner
+	if d.IsRunning() {
+		run = func(name string, args ...string) (string, error) {
+			return d.execCommand(d, append([]string{name}, args...), time.Minute)
+		}
+	}
+
+	result, err := inventory.Collect(sftpFS{client: client}, run)
+	if err != nil {
+		return nil, err
+	}
+
+	// Containers share the host kernel.
+	if d.state.OS.Uname != nil {
+		result.OS.Kernel = d.state.OS.Uname.Release
+	}
+
+	return result, nil
+}
+
 // stopForkFile attempts to send SIGTERM (if force is true) or SIGINT to forkfile then waits for it to exit.
 func (d *lxc) stopForkfile(force bool) {
 	// Make sure that when the function exits, no forkfile is running by acquiring the lock (which indicates
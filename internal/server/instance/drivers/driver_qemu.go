@@ -120,6 +120,12 @@ const qemuMigrationNBDExportName = "incus_root"
 // 4 are reserved, and the other 4 can be used for any USB device.
 const qemuSparseUSBPorts = 8
 
+// qemuSnapshotQuiesceTimeout is how long the guest filesystems may stay frozen while taking a snapshot.
+const qemuSnapshotQuiesceTimeout = 5 * time.Minute
+
+// qemuClockSyncTimeout is how long to wait for the agent to come back before giving up on stepping the guest clock.
+const qemuClockSyncTimeout = 2 * time.Minute
+
 var errQemuAgentOffline = fmt.Errorf("VM agent isn't currently running")
 
 type monitorHook func(m *qmp.Monitor) error
@@ -426,6 +432,8 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 				d.logger.Warn("Failed to advertise vsock address to instance agent", logger.Ctx{"err": err})
 				return
 			}
+
+			d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceAgentStarted.Event(d, nil))
 		} else if event == qmp.EventVMShutdown {
 			target := "stop"
 			entry, ok := data["reason"]
@@ -612,14 +620,18 @@ func (d *qemu) onStop(target string) error {
 
 	// Record power state.
 	err = d.VolatileSet(map[string]string{
-		"volatile.last_state.power": instance.PowerStateStopped,
-		"volatile.last_state.ready": "false",
+		"volatile.last_state.power":  instance.PowerStateStopped,
+		"volatile.last_state.ready":  "false",
+		"volatile.last_state.health": "",
 	})
 	if err != nil {
 		// Don't return an error here as we still want to cleanup the instance even if DB not available.
 		d.logger.Error("Failed recording last power state", logger.Ctx{"err": err})
 	}
 
+	// Stop forwarding the console output.
+	d.stopLogForwarding()
+
 	// Cleanup.
 	d.cleanupDevices() // Must be called before unmount.
 	_ = os.Remove(d.pidFilePath())
@@ -642,7 +654,12 @@ func (d *qemu) onStop(target string) error {
 
 	// Log and emit lifecycle if not user triggered.
 	if op.GetInstanceInitiated() {
-		d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceShutdown.Event(d, nil))
+		var ctx map[string]any
+		if op.GetShutdownMethod() != "" {
+			ctx = map[string]any{"method": op.GetShutdownMethod()}
+		}
+
+		d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceShutdown.Event(d, ctx))
 	} else if op.Action() != operationlock.ActionMigrate {
 		d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceStopped.Event(d, nil))
 	}
@@ -717,34 +734,52 @@ func (d *qemu) Shutdown(timeout time.Duration) error {
 	// to the powerdown request.
 	op.SetInstanceInitiated(true)
 
-	// Send the system_powerdown command.
-	err = monitor.Powerdown()
-	if err != nil {
-		if err == qmp.ErrMonitorDisconnect {
-			op.Done(nil)
-			return nil
-		}
+	// Ask the agent to shut down the guest if configured.
+	method := d.expandedConfig["boot.shutdown_method"]
+	if method == "" {
+		method = "acpi"
+	}
 
-		op.Done(err)
-		return err
+	if method == "agent" || method == "both" {
+		err = d.agentShutdown()
+		if err != nil {
+			d.logger.Warn("Failed shutting down through the agent, falling back to ACPI", logger.Ctx{"err": err})
+			method = "acpi"
+		}
 	}
 
-	// Wait 500ms for the first event to be received by the guest.
-	time.Sleep(500 * time.Millisecond)
+	op.SetShutdownMethod(method)
 
-	// Send a second system_powerdown command (required to get Windows to shutdown).
-	err = monitor.Powerdown()
-	if err != nil {
-		if err == qmp.ErrMonitorDisconnect {
-			op.Done(nil)
-			return nil
+	if method != "agent" {
+		// Send the system_powerdown command.
+		err = monitor.Powerdown()
+		if err != nil {
+			if err == qmp.ErrMonitorDisconnect {
+				op.Done(nil)
+				return nil
+			}
+
+			op.Done(err)
+			return err
 		}
 
-		op.Done(err)
-		return err
+		// Wait 500ms for the first event to be received by the guest.
+		time.Sleep(500 * time.Millisecond)
+
+		// Send a second system_powerdown command (required to get Windows to shutdown).
+		err = monitor.Powerdown()
+		if err != nil {
+			if err == qmp.ErrMonitorDisconnect {
+				op.Done(nil)
+				return nil
+			}
+
+			op.Done(err)
+			return err
+		}
 	}
 
-	d.logger.Debug("Shutdown request sent to instance")
+	d.logger.Debug("Shutdown request sent to instance", logger.Ctx{"method": method})
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
@@ -1429,6 +1464,11 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		if cpuInfo.threads > 1 {
 			cpuExtensions = append(cpuExtensions, "topoext")
 		}
+
+		// The KVM PTP clock relies on the KVM paravirtualized clock.
+		if util.IsTrue(d.expandedConfig["clock.ptp_kvm"]) {
+			cpuExtensions = append(cpuExtensions, "kvmclock", "kvmclock-stable-bit")
+		}
 	}
 
 	cpuType := "host"
@@ -1671,6 +1711,25 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		return err
 	}
 
+	// Forward the console output to the remote logging targets.
+	if util.IsTrue(d.expandedConfig["logging.console"]) {
+		// Only forward the output of this run.
+		err = os.Remove(d.ConsoleBufferLogPath())
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			op.Done(err)
+			return err
+		}
+
+		err = d.startLogForwarding(d.ConsoleBufferLogPath(), true, true, "")
+		if err != nil {
+			err = fmt.Errorf("Failed starting log forwarding: %w", err)
+			op.Done(err)
+			return err
+		}
+
+		revert.Add(d.stopLogForwarding)
+	}
+
 	err = p.StartWithFiles(context.Background(), fdFiles)
 	if err != nil {
 		op.Done(err)
@@ -1821,6 +1880,9 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 			op.Done(err)
 			return fmt.Errorf("Error updating instance stateful flag: %w", err)
 		}
+
+		// The guest clock is behind by however long the instance was saved or in transit for.
+		go d.SyncGuestClock()
 	}
 
 	// Record last start state.
@@ -1955,6 +2017,7 @@ func (d *qemu) getAgentConnectionInfo() (*agentAPI.API10Put, error) {
 	req := agentAPI.API10Put{
 		Certificate: string(d.state.Endpoints.NetworkCert().PublicKey()),
 		DevIncus:    util.IsTrueOrEmpty(d.expandedConfig["security.guestapi"]),
+		PTPKVM:      util.IsTrue(d.expandedConfig["clock.ptp_kvm"]),
 		CID:         vsock.Host, // Always tell the agent to connect to the server using the Host Context ID to support nesting.
 		Port:        vsockaddr.Port,
 	}
@@ -1994,6 +2057,20 @@ func (d *qemu) advertiseVsockAddress() error {
 	return nil
 }
 
+// AgentStarted returns whether the agent is running inside of the instance.
+func (d *qemu) AgentStarted() bool {
+	if !d.IsRunning() {
+		return false
+	}
+
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return false
+	}
+
+	return monitor.AgenStarted()
+}
+
 // AgentCertificate returns the server certificate of the agent.
 func (d *qemu) AgentCertificate() *x509.Certificate {
 	agentCert := filepath.Join(d.Path(), "config", "agent.crt")
@@ -2112,6 +2189,14 @@ func (d *qemu) qemuArchConfig(arch int) (string, string, error) {
 // RegisterDevices calls the Register() function on all of the instance's devices.
 func (d *qemu) RegisterDevices() {
 	d.devicesRegister(d)
+
+	// Resume forwarding the console output.
+	if util.IsTrue(d.expandedConfig["logging.console"]) && !d.logForwardingActive() {
+		err := d.startLogForwarding(d.ConsoleBufferLogPath(), false, true, "")
+		if err != nil {
+			d.logger.Warn("Failed resuming log forwarding", logger.Ctx{"err": err})
+		}
+	}
 }
 
 func (d *qemu) saveConnectionInfo(connInfo *agentAPI.API10Put) error {
@@ -3122,7 +3207,12 @@ func (d *qemu) generateQemuConfigFile(cpuInfo *cpuTopology, mountInfo *storagePo
 	cfg = append(cfg, qemuControlSocket(&qemuControlSocketOpts{d.monitorPath()})...)
 
 	// Console output.
-	cfg = append(cfg, qemuConsole(&qemuConsoleOpts{d.consolePath()})...)
+	consoleOpts := qemuConsoleOpts{path: d.consolePath()}
+	if util.IsTrue(d.expandedConfig["logging.console"]) {
+		consoleOpts.logPath = d.ConsoleBufferLogPath()
+	}
+
+	cfg = append(cfg, qemuConsole(&consoleOpts)...)
 
 	// Setup the bus allocator.
 	bus := qemuNewBus(busName, &cfg)
@@ -4863,6 +4953,9 @@ func (d *qemu) Unfreeze() error {
 		return err
 	}
 
+	// The guest clock stood still while paused.
+	go d.SyncGuestClock()
+
 	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceResumed.Event(d, nil))
 	return nil
 }
@@ -4908,6 +5001,16 @@ func (d *qemu) snapshot(name string, expiry time.Time, stateful bool) error {
 		}
 	}
 
+	// Freeze the guest filesystems for the duration of a stateless snapshot.
+	if !stateful && d.IsRunning() && util.IsTrue(d.expandedConfig["snapshots.quiesce"]) {
+		thaw, err := d.Quiesce(qemuSnapshotQuiesceTimeout)
+		if err != nil {
+			return err
+		}
+
+		defer thaw()
+	}
+
 	// Create the snapshot.
 	err = d.snapshotCommon(d, name, expiry, stateful)
 	if err != nil {
@@ -4940,7 +5043,18 @@ func (d *qemu) Snapshot(name string, expiry time.Time, stateful bool) error {
 
 	defer unlock()
 
-	return d.snapshot(name, expiry, stateful)
+	postHook, err := d.snapshotHooksRun(d)
+	if err != nil {
+		return err
+	}
+
+	err = d.snapshot(name, expiry, stateful)
+	hookErr := postHook()
+	if err != nil {
+		return err
+	}
+
+	return hookErr
 }
 
 // Restore restores an instance snapshot.
@@ -7464,6 +7578,20 @@ func (d *qemu) FileSFTPConn() (net.Conn, error) {
 		return nil, fmt.Errorf("Instance is not running")
 	}
 
+	return d.agentUpgradeConn("/1.0/sftp", "sftp")
+}
+
+// PortForwardConn returns a connection to a TCP port listening inside the VM through the agent.
+func (d *qemu) PortForwardConn(port int) (net.Conn, error) {
+	if !d.IsRunning() {
+		return nil, fmt.Errorf("Instance is not running")
+	}
+
+	return d.agentUpgradeConn(fmt.Sprintf("/1.0/port-forward?port=%d", port), "tcp")
+}
+
+// agentUpgradeConn upgrades a request to an agent endpoint into a raw connection using the given protocol.
+func (d *qemu) agentUpgradeConn(path string, protocol string) (net.Conn, error) {
 	// Connect to the agent.
 	client, err := d.getAgentClient()
 	if err != nil {
@@ -7474,7 +7602,7 @@ func (d *qemu) FileSFTPConn() (net.Conn, error) {
 	httpTransport := client.Transport.(*http.Transport)
 
 	// Send the upgrade request.
-	u, err := url.Parse("https://custom.socket/1.0/sftp")
+	u, err := url.Parse("https://custom.socket" + path)
 	if err != nil {
 		return nil, err
 	}
@@ -7489,7 +7617,7 @@ func (d *qemu) FileSFTPConn() (net.Conn, error) {
 		Host:       u.Host,
 	}
 
-	req.Header["Upgrade"] = []string{"sftp"}
+	req.Header["Upgrade"] = []string{protocol}
 	req.Header["Connection"] = []string{"Upgrade"}
 
 	conn, err := httpTransport.DialContext(context.Background(), "tcp", "8443")
@@ -7508,7 +7636,8 @@ func (d *qemu) FileSFTPConn() (net.Conn, error) {
 		return nil, err
 	}
 
-	resp, err := http.ReadResponse(bufio.NewReader(tlsConn), req)
+	reader := bufio.NewReader(tlsConn)
+	resp, err := http.ReadResponse(reader, req)
 	if err != nil {
 		return nil, err
 	}
@@ -7517,13 +7646,40 @@ func (d *qemu) FileSFTPConn() (net.Conn, error) {
 		return nil, fmt.Errorf("Dialing failed: expected status code 101 got %d", resp.StatusCode)
 	}
 
-	if resp.Header.Get("Upgrade") != "sftp" {
+	if resp.Header.Get("Upgrade") != protocol {
 		return nil, fmt.Errorf("Missing or unexpected Upgrade header in response")
 	}
 
+	// The guest may have started sending data right after the upgrade response.
+	if reader.Buffered() > 0 {
+		return &agentUpgradedConn{Conn: tlsConn, reader: reader}, nil
+	}
+
 	return tlsConn, nil
 }
 
+// agentUpgradedConn is an upgraded agent connection whose start was already read into a buffer.
+type agentUpgradedConn struct {
+	net.Conn
+
+	reader *bufio.Reader
+}
+
+// Read reads from the buffer before reading from the connection.
+func (c *agentUpgradedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// CloseWrite shuts down the writing side of the connection.
+func (c *agentUpgradedConn) CloseWrite() error {
+	tlsConn, ok := c.Conn.(*tls.Conn)
+	if !ok {
+		return fmt.Errorf("Connection doesn't support half-closing")
+	}
+
+	return tlsConn.CloseWrite()
+}
+
 // FileSFTP returns an SFTP connection to the agent endpoint.
 func (d *qemu) FileSFTP() (*sftp.Client, error) {
 	// Connect to the forkfile daemon.
@@ -7548,6 +7704,171 @@ func (d *qemu) FileSFTP() (*sftp.Client, error) {
 	return client, nil
 }
 
+// agentShutdown asks the agent to shut down the guest.
+func (d *qemu) agentShutdown() error {
+	client, err := d.getAgentClient()
+	if err != nil {
+		return err
+	}
+
+	agent, err := incus.ConnectIncusHTTP(&incus.ConnectionArgs{SkipGetServer: true}, client)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to the agent: %w", err)
+	}
+
+	defer agent.Disconnect()
+
+	req := agentAPI.ShutdownPost{}
+	if d.expandedConfig["boot.shutdown_command"] != "" {
+		req.Command = []string{"/bin/sh", "-c", d.expandedConfig["boot.shutdown_command"]}
+	}
+
+	_, _, err = agent.RawQuery("POST", "/1.0/shutdown", req, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SyncGuestClock steps the guest clock to the host time through the agent.
+// This is used after the guest got paused or restored so it doesn't have to wait for its time synchronization
+// daemon to catch up. As the agent usually needs a few seconds to reconnect, this waits for it to be available.
+func (d *qemu) SyncGuestClock() {
+	ctx, cancel := context.WithTimeout(context.Background(), qemuClockSyncTimeout)
+	defer cancel()
+
+	for {
+		err := d.setGuestClock()
+		if err == nil {
+			d.logger.Debug("Synchronized guest clock")
+			return
+		}
+
+		// Older agents don't support setting the clock.
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			d.logger.Debug("Skipping guest clock synchronization as the agent doesn't support it")
+			return
+		}
+
+		if !errors.Is(err, errQemuAgentOffline) {
+			d.logger.Warn("Failed synchronizing guest clock", logger.Ctx{"err": err})
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			d.logger.Debug("Skipping guest clock synchronization as the agent isn't running")
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// setGuestClock sends the current host time to the agent.
+func (d *qemu) setGuestClock() error {
+	if !d.IsRunning() {
+		return nil
+	}
+
+	client, err := d.getAgentClient()
+	if err != nil {
+		return err
+	}
+
+	agent, err := incus.ConnectIncusHTTP(&incus.ConnectionArgs{SkipGetServer: true}, client)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to the agent: %w", err)
+	}
+
+	defer agent.Disconnect()
+
+	_, _, err = agent.RawQuery("PUT", "/1.0/clock", agentAPI.ClockPut{Time: time.Now()}, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SSHKeys returns the authorized SSH keys of a guest user.
+func (d *qemu) SSHKeys(username string) ([]string, error) {
+	client, err := d.getAgentClient()
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := incus.ConnectIncusHTTP(&incus.ConnectionArgs{SkipGetServer: true}, client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to the agent: %w", err)
+	}
+
+	defer agent.Disconnect()
+
+	resp, _, err := agent.RawQuery("GET", fmt.Sprintf("/1.0/ssh-keys/%s", url.PathEscape(username)), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	keys := api.InstanceSSHKeys{}
+	err = resp.MetadataAsStruct(&keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys.Keys, nil
+}
+
+// SetSSHKeys replaces the authorized SSH keys of a guest user, creating the user if missing.
+func (d *qemu) SetSSHKeys(username string, keys []string) error {
+	client, err := d.getAgentClient()
+	if err != nil {
+		return err
+	}
+
+	agent, err := incus.ConnectIncusHTTP(&incus.ConnectionArgs{SkipGetServer: true}, client)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to the agent: %w", err)
+	}
+
+	defer agent.Disconnect()
+
+	_, _, err = agent.RawQuery("PUT", fmt.Sprintf("/1.0/ssh-keys/%s", url.PathEscape(username)), api.InstanceSSHKeysPut{Keys: keys}, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Inventory returns the software inventory of the instance as reported by the agent.
+func (d *qemu) Inventory() (*api.InstanceInventory, error) {
+	client, err := d.getAgentClient()
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := incus.ConnectIncusHTTP(&incus.ConnectionArgs{SkipGetServer: true}, client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to the agent: %w", err)
+	}
+
+	defer agent.Disconnect()
+
+	resp, _, err := agent.RawQuery("GET", "/1.0/inventory", nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result := api.InstanceInventory{}
+	err = resp.MetadataAsStruct(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
 // Console gets access to the instance's console.
 func (d *qemu) Console(protocol string) (*os.File, chan error, error) {
 	var path string
@@ -7853,6 +8174,10 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 
 	status.Status = statusCode.String()
 	status.StatusCode = statusCode
+	if d.isRunningStatusCode(statusCode) {
+		status.Health = d.localConfig["volatile.last_state.health"]
+	}
+
 	status.Disk, err = d.diskState()
 	if err != nil && !errors.Is(err, storageDrivers.ErrNotSupported) {
 		d.logger.Warn("Error getting disk usage", logger.Ctx{"err": err})
@@ -7916,6 +8241,46 @@ func (d *qemu) agentGetState() (*api.InstanceState, error) {
 	return status, nil
 }
 
+// Quiesce freezes the guest filesystems through the agent and returns a function to thaw them.
+// The agent thaws the filesystems by itself once the timeout is reached.
+func (d *qemu) Quiesce(timeout time.Duration) (func(), error) {
+	client, err := d.getAgentClient()
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting agent client handle: %w", err)
+	}
+
+	agentArgs := &incus.ConnectionArgs{SkipGetServer: true}
+	agent, err := incus.ConnectIncusHTTP(agentArgs, client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to the agent: %w", err)
+	}
+
+	req := agentAPI.QuiescePost{Timeout: int(timeout.Seconds())}
+	resp, _, err := agent.RawQuery("POST", "/1.0/quiesce", req, "")
+	if err != nil {
+		agent.Disconnect()
+		return nil, fmt.Errorf("Failed freezing the guest filesystems: %w", err)
+	}
+
+	quiesce := agentAPI.Quiesce{}
+	err = resp.MetadataAsStruct(&quiesce)
+	if err == nil {
+		d.logger.Debug("Froze guest filesystems", logger.Ctx{"filesystems": quiesce.Filesystems})
+	}
+
+	return func() {
+		defer agent.Disconnect()
+
+		_, _, err := agent.RawQuery("DELETE", "/1.0/quiesce", nil, "")
+		if err != nil {
+			d.logger.Warn("Failed thawing the guest filesystems", logger.Ctx{"err": err})
+			return
+		}
+
+		d.logger.Debug("Thawed guest filesystems")
+	}, nil
+}
+
 // IsRunning returns whether or not the instance is running.
 func (d *qemu) IsRunning() bool {
 	return d.isRunningStatusCode(d.statusCode())
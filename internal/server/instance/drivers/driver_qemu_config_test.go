@@ -704,13 +704,23 @@ func TestQemuConfigTemplates(t *testing.T) {
 			opts     qemuConsoleOpts
 			expected string
 		}{{
-			qemuConsoleOpts{"/dev/shm/console-socket"},
+			qemuConsoleOpts{"/dev/shm/console-socket", ""},
 			`# Console
 			[chardev "console"]
 			backend = "socket"
 			path = "/dev/shm/console-socket"
 			server = "on"
 			wait = "off"`,
+		}, {
+			qemuConsoleOpts{"/dev/shm/console-socket", "/var/log/incus/vm/console.log"},
+			`# Console
+			[chardev "console"]
+			backend = "socket"
+			path = "/dev/shm/console-socket"
+			server = "on"
+			wait = "off"
+			logfile = "/var/log/incus/vm/console.log"
+			logappend = "on"`,
 		}}
 		for _, tc := range testCases {
 			runTest(tc.expected, qemuConsole(&tc.opts))
@@ -607,19 +607,27 @@ func qemuControlSocket(opts *qemuControlSocketOpts) []cfgSection {
 }
 
 type qemuConsoleOpts struct {
-	path string
+	path    string
+	logPath string
 }
 
 func qemuConsole(opts *qemuConsoleOpts) []cfgSection {
+	entries := []cfgEntry{
+		{key: "backend", value: "socket"},
+		{key: "path", value: opts.path},
+		{key: "server", value: "on"},
+		{key: "wait", value: "off"},
+	}
+
+	if opts.logPath != "" {
+		// Append to the log file so writes land at its end after the log forwarder truncated it.
+		entries = append(entries, cfgEntry{key: "logfile", value: opts.logPath}, cfgEntry{key: "logappend", value: "on"})
+	}
+
 	return []cfgSection{{
 		name:    `chardev "console"`,
 		comment: "Console",
-		entries: []cfgEntry{
-			{key: "backend", value: "socket"},
-			{key: "path", value: opts.path},
-			{key: "server", value: "on"},
-			{key: "wait", value: "off"},
-		},
+		entries: entries,
 	}}
 }
 
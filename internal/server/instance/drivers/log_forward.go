@@ -0,0 +1,379 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/lxc/incus/v6/internal/server/events"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+const (
+	// logForwardInterval is how often the console log file is checked for new output.
+	logForwardInterval = time.Second
+
+	// logForwardMaxLine is the maximum length of a forwarded line, longer lines are split.
+	logForwardMaxLine = 8192
+
+	// logForwardRate is the number of messages per second an instance may send on average.
+	logForwardRate = 100
+
+	// logForwardBurst is the number of messages an instance may send at once.
+	logForwardBurst = 1000
+
+	// logForwardMaxFileSize is the size above which a console log file owned by the forwarder is truncated
+	// once forwarded.
+	logForwardMaxFileSize = 1024 * 1024
+)
+
+// logForwardEscapes matches the terminal escape sequences stripped from the console output.
+var logForwardEscapes = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]|\x1b[()][0-9A-Za-z]`)
+
+// logForwarders holds the running log forwarders, keyed by instance ID.
+var logForwarders = map[int]*logForwarder{}
+var logForwardersMu sync.Mutex
+
+// logForwarder sends the console output and syslog messages of an instance as instance-log events.
+type logForwarder struct {
+	events  *events.Server
+	project string
+	name    string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	limiterMu sync.Mutex
+	limiter   logForwardLimiter
+	dropped   int
+}
+
+// logForwardLimiter is a token bucket limiting the rate of the forwarded messages.
+type logForwardLimiter struct {
+	tokens float64
+	last   time.Time
+}
+
+// allow returns whether a message may be sent at the given time, taking a token if so.
+func (l *logForwardLimiter) allow(now time.Time) bool {
+	if l.last.IsZero() {
+		l.tokens = logForwardBurst
+	} else {
+		l.tokens = min(logForwardBurst, l.tokens+now.Sub(l.last).Seconds()*logForwardRate)
+	}
+
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+
+	l.tokens--
+
+	return true
+}
+
+func newLogForwarder(eventServer *events.Server, projectName string, instanceName string) *logForwarder {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &logForwarder{
+		events:  eventServer,
+		project: projectName,
+		name:    instanceName,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// send sends a single log message.
+// Messages exceeding the rate limit are dropped, which is reported once messages can be sent again.
+func (f *logForwarder) send(source string, level logrus.Level, message string) {
+	message = strings.TrimSpace(logForwardEscapes.ReplaceAllString(message, ""))
+	if message == "" {
+		return
+	}
+
+	f.limiterMu.Lock()
+	if !f.limiter.allow(time.Now()) {
+		f.dropped++
+		f.limiterMu.Unlock()
+		return
+	}
+
+	dropped := f.dropped
+	f.dropped = 0
+	f.limiterMu.Unlock()
+
+	if dropped > 0 {
+		logger.Warn("Dropped instance log messages exceeding the rate limit", logger.Ctx{"project": f.project, "instance": f.name, "count": dropped})
+		f.sendEvent("incus", logrus.WarnLevel, fmt.Sprintf("Dropped %d messages exceeding the rate limit", dropped))
+	}
+
+	f.sendEvent(source, level, message)
+}
+
+// sendEvent sends a log message as an instance-log event.
+func (f *logForwarder) sendEvent(source string, level logrus.Level, message string) {
+	_ = f.events.Send(f.project, api.EventTypeInstanceLog, api.EventLogging{
+		Level:   level.String(),
+		Message: message,
+		Context: map[string]string{
+			"name":   f.name,
+			"source": source,
+		},
+	})
+}
+
+// tailFile forwards the lines appended to the console log file at the given path.
+// The file may not exist yet and is read again from the start if truncated.
+// With truncate, the file is truncated once forwarded if larger than logForwardMaxFileSize, which requires
+// its writer to append to it.
+func (f *logForwarder) tailFile(path string, fromStart bool, truncate bool) {
+	var offset int64
+
+	if !fromStart {
+		fi, err := os.Stat(path)
+		if err == nil {
+			offset = fi.Size()
+		}
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		var pending []byte
+
+		read := func() {
+			file, err := os.Open(path)
+			if err != nil {
+				return
+			}
+
+			defer func() { _ = file.Close() }()
+
+			fi, err := file.Stat()
+			if err != nil {
+				return
+			}
+
+			// Start over if the file was truncated.
+			if fi.Size() < offset {
+				offset = 0
+				pending = nil
+			}
+
+			if fi.Size() == offset {
+				return
+			}
+
+			_, err = file.Seek(offset, io.SeekStart)
+			if err != nil {
+				return
+			}
+
+			buf, err := io.ReadAll(io.LimitReader(file, fi.Size()-offset))
+			if err != nil {
+				return
+			}
+
+			offset += int64(len(buf))
+			pending = append(pending, buf...)
+
+			// Keep the file from growing without bounds. Output written since it was read is lost.
+			if truncate && offset > logForwardMaxFileSize {
+				err = os.Truncate(path, 0)
+				if err == nil {
+					offset = 0
+				}
+			}
+
+			for {
+				i := bytes.IndexByte(pending, '\n')
+				if i < 0 {
+					if len(pending) < logForwardMaxLine {
+						break
+					}
+
+					i = logForwardMaxLine
+				}
+
+				f.send("console", logrus.InfoLevel, string(bytes.TrimRight(pending[:i], "\r")))
+				pending = pending[min(i+1, len(pending)):]
+			}
+		}
+
+		ticker := time.NewTicker(logForwardInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-f.ctx.Done():
+				// Send the remaining output.
+				read()
+				if len(pending) > 0 {
+					f.send("console", logrus.InfoLevel, string(pending))
+				}
+
+				return
+			case <-ticker.C:
+				read()
+			}
+		}
+	}()
+}
+
+// listenSyslog forwards the messages received on a syslog socket created at the given path.
+func (f *logForwarder) listenSyslog(path string) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	var listenConfig net.ListenConfig
+
+	conn, err := listenConfig.ListenPacket(f.ctx, "unixgram", path)
+	if err != nil {
+		return err
+	}
+
+	// The socket is bind-mounted into the instance and must be usable by any user.
+	err = os.Chmod(path, 0666)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	// Close the connection on stop, causing ReadFrom to return an error.
+	go func() {
+		<-f.ctx.Done()
+		_ = conn.Close()
+		_ = os.Remove(path)
+	}()
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		buf := make([]byte, 64*1024)
+
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			level, message := parseSyslogMessage(string(buf[:n]))
+			f.send("syslog", level, message)
+		}
+	}()
+
+	return nil
+}
+
+// stop stops the forwarder after sending the pending output.
+func (f *logForwarder) stop() {
+	f.cancel()
+	f.wg.Wait()
+}
+
+// parseSyslogMessage returns the level and message of a syslog message as sent to /dev/log.
+// The priority prefix and the RFC3164 timestamp are removed, keeping the tag.
+func parseSyslogMessage(msg string) (logrus.Level, string) {
+	level := logrus.InfoLevel
+
+	if strings.HasPrefix(msg, "<") {
+		end := strings.Index(msg, ">")
+		if end > 0 {
+			priority, err := strconv.Atoi(msg[1:end])
+			if err == nil {
+				msg = msg[end+1:]
+
+				switch severity := priority % 8; {
+				case severity <= 3:
+					level = logrus.ErrorLevel
+				case severity == 4:
+					level = logrus.WarnLevel
+				case severity == 7:
+					level = logrus.DebugLevel
+				}
+			}
+		}
+	}
+
+	if len(msg) > len(time.Stamp) {
+		_, err := time.Parse(time.Stamp, msg[:len(time.Stamp)])
+		if err == nil {
+			msg = msg[len(time.Stamp):]
+		}
+	}
+
+	return level, strings.TrimSpace(msg)
+}
+
+// startLogForwarding starts forwarding the console output and syslog messages of the instance to the
+// remote logging targets if enabled through logging.console. The syslog socket is only created if
+// syslogPath isn't empty. With truncateConsoleLog, the console log file is truncated once it has been
+// forwarded and gets too large.
+func (d *common) startLogForwarding(consoleLogPath string, fromStart bool, truncateConsoleLog bool, syslogPath string) error {
+	d.stopLogForwarding()
+
+	if !util.IsTrue(d.expandedConfig["logging.console"]) {
+		return nil
+	}
+
+	forwarder := newLogForwarder(d.state.Events, d.project.Name, d.name)
+
+	if syslogPath != "" {
+		err := forwarder.listenSyslog(syslogPath)
+		if err != nil {
+			forwarder.stop()
+			return err
+		}
+	}
+
+	if consoleLogPath != "" {
+		forwarder.tailFile(consoleLogPath, fromStart, truncateConsoleLog)
+	}
+
+	logForwardersMu.Lock()
+	logForwarders[d.id] = forwarder
+	logForwardersMu.Unlock()
+
+	return nil
+}
+
+// stopLogForwarding stops forwarding the logs of the instance.
+func (d *common) stopLogForwarding() {
+	logForwardersMu.Lock()
+	forwarder := logForwarders[d.id]
+	delete(logForwarders, d.id)
+	logForwardersMu.Unlock()
+
+	if forwarder != nil {
+		forwarder.stop()
+		d.logger.Debug("Stopped log forwarding")
+	}
+}
+
+// logForwardingActive returns whether the logs of the instance are being forwarded.
+func (d *common) logForwardingActive() bool {
+	logForwardersMu.Lock()
+	defer logForwardersMu.Unlock()
+
+	return logForwarders[d.id] != nil
+}
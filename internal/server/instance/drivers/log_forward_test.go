@@ -0,0 +1,91 @@
+package drivers
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/lxc/incus/v6/internal/server/events"
+)
+
+func TestParseSyslogMessage(t *testing.T) {
+	testCases := []struct {
+		msg     string
+		level   logrus.Level
+		message string
+	}{
+		{"<30>Oct 17 10:00:00 systemd[1]: Started Daily apt upgrade.", logrus.InfoLevel, "systemd[1]: Started Daily apt upgrade."},
+		{"<11>Jan  2 03:04:05 app: Failed", logrus.ErrorLevel, "app: Failed"},
+		{"<12>app: Low disk space", logrus.WarnLevel, "app: Low disk space"},
+		{"<15>Jan  2 03:04:05 app: Details", logrus.DebugLevel, "app: Details"},
+		{"No priority", logrus.InfoLevel, "No priority"},
+	}
+
+	for _, tc := range testCases {
+		level, message := parseSyslogMessage(tc.msg)
+		if level != tc.level || message != tc.message {
+			t.Errorf("Expected: %s %q. Got: %s %q", tc.level, tc.message, level, message)
+		}
+	}
+}
+
+func TestLogForwardLimiter(t *testing.T) {
+	var limiter logForwardLimiter
+
+	now := time.Now()
+
+	for i := 0; i < logForwardBurst; i++ {
+		if !limiter.allow(now) {
+			t.Fatalf("Message %d of the burst was dropped", i)
+		}
+	}
+
+	if limiter.allow(now) {
+		t.Errorf("Message exceeding the burst wasn't dropped")
+	}
+
+	// Tokens are added back over time.
+	now = now.Add(time.Second)
+	for i := 0; i < logForwardRate; i++ {
+		if !limiter.allow(now) {
+			t.Fatalf("Message %d after refill was dropped", i)
+		}
+	}
+
+	if limiter.allow(now) {
+		t.Errorf("Message exceeding the refilled tokens wasn't dropped")
+	}
+}
+
+func TestLogForwarderTruncate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "console.log")
+
+	err := os.WriteFile(path, bytes.Repeat([]byte("line\n"), logForwardMaxFileSize/5+1), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forwarder := newLogForwarder(events.NewServer(false, false, nil), "default", "v1")
+	forwarder.tailFile(path, true, true)
+	defer forwarder.stop()
+
+	// The file gets truncated once forwarded.
+	for i := 0; i < 50; i++ {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if fi.Size() == 0 {
+			return
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	t.Errorf("Console log file wasn't truncated")
+}
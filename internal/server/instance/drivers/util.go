@@ -1,11 +1,20 @@
 package drivers
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/fs"
+	"net/http"
 	"os"
+	"path"
 	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/sftp"
 
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
@@ -97,3 +106,87 @@ func GetClusterCPUFlags(ctx context.Context, s *state.State, servers []string, a
 
 	return flags, nil
 }
+
+// guestUser represents a user entry of the /etc/passwd file of an instance.
+type guestUser struct {
+	uid  int
+	gid  int
+	home string
+}
+
+// guestUserLookup looks up a user in the /etc/passwd file of an instance.
+func guestUserLookup(client *sftp.Client, username string) (*guestUser, error) {
+	f, err := client.Open("/etc/passwd")
+	if err != nil {
+		return nil, fmt.Errorf("Failed opening /etc/passwd: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 7 || fields[0] != username {
+			continue
+		}
+
+		uid, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid UID for user %q: %w", username, err)
+		}
+
+		gid, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid GID for user %q: %w", username, err)
+		}
+
+		return &guestUser{uid: uid, gid: gid, home: fields[5]}, nil
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading /etc/passwd: %w", err)
+	}
+
+	return nil, api.StatusErrorf(http.StatusNotFound, "User %q not found", username)
+}
+
+// sftpFS exposes the filesystem of an instance as a fs.FS through its SFTP server.
+type sftpFS struct {
+	client *sftp.Client
+}
+
+// Open opens the named file.
+func (f sftpFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	file, err := f.client.Open(path.Join("/", name))
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// ReadDir reads the named directory and returns its entries sorted by name.
+func (f sftpFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	infos, err := f.client.ReadDir(path.Join("/", name))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]fs.DirEntry, 0, len(infos))
+	for _, info := range infos {
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	return entries, nil
+}
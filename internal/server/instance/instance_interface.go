@@ -110,6 +110,16 @@ type Instance interface {
 	FileSFTPConn() (net.Conn, error)
 	FileSFTP() (*sftp.Client, error)
 
+	// Guest users.
+	SSHKeys(user string) ([]string, error)
+	SetSSHKeys(user string, keys []string) error
+
+	// Guest inventory.
+	Inventory() (*api.InstanceInventory, error)
+
+	// Port forwarding.
+	PortForwardConn(port int) (net.Conn, error)
+
 	// Console - Allocate and run a console tty or a spice Unix socket.
 	Console(protocol string) (*os.File, chan error, error)
 	Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (Cmd, error)
@@ -190,6 +200,9 @@ type VM interface {
 	Instance
 
 	AgentCertificate() *x509.Certificate
+	AgentStarted() bool
+	Quiesce(timeout time.Duration) (func(), error)
+	SyncGuestClock()
 }
 
 // CriuMigrationArgs arguments for CRIU migration.
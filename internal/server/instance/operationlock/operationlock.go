@@ -53,6 +53,7 @@ type InstanceOperation struct {
 	instanceName      string
 	reusable          bool
 	instanceInitiated bool
+	shutdownMethod    string
 	op                *operations.Operation
 }
 
@@ -234,6 +235,26 @@ func (op *InstanceOperation) GetInstanceInitiated() bool {
 	return op.instanceInitiated
 }
 
+// SetShutdownMethod records how the instance was requested to shut down.
+func (op *InstanceOperation) SetShutdownMethod(method string) {
+	// This function can be called on a nil struct.
+	if op == nil {
+		return
+	}
+
+	op.shutdownMethod = method
+}
+
+// GetShutdownMethod gets how the instance was requested to shut down.
+func (op *InstanceOperation) GetShutdownMethod() string {
+	// This function can be called on a nil struct.
+	if op == nil {
+		return ""
+	}
+
+	return op.shutdownMethod
+}
+
 // GetOperation gets the API background operation.
 func (op *InstanceOperation) GetOperation() *operations.Operation {
 	// This function can be called on a nil struct.
@@ -18,6 +18,7 @@ type InstanceAction string
 
 // All supported lifecycle events for instances.
 const (
+	InstanceAgentStarted     = InstanceAction(api.EventLifecycleInstanceAgentStarted)
 	InstanceConsole          = InstanceAction(api.EventLifecycleInstanceConsole)
 	InstanceConsoleReset     = InstanceAction(api.EventLifecycleInstanceConsoleReset)
 	InstanceConsoleRetrieved = InstanceAction(api.EventLifecycleInstanceConsoleRetrieved)
@@ -27,6 +28,7 @@ const (
 	InstanceFileDeleted      = InstanceAction(api.EventLifecycleInstanceFileDeleted)
 	InstanceFilePushed       = InstanceAction(api.EventLifecycleInstanceFilePushed)
 	InstanceFileRetrieved    = InstanceAction(api.EventLifecycleInstanceFileRetrieved)
+	InstanceHealthChanged    = InstanceAction(api.EventLifecycleInstanceHealthChanged)
 	InstanceMigrated         = InstanceAction(api.EventLifecycleInstanceMigrated)
 	InstancePaused           = InstanceAction(api.EventLifecycleInstancePaused)
 	InstanceReady            = InstanceAction(api.EventLifecycleInstanceReady)
@@ -35,6 +37,7 @@ const (
 	InstanceRestored         = InstanceAction(api.EventLifecycleInstanceRestored)
 	InstanceResumed          = InstanceAction(api.EventLifecycleInstanceResumed)
 	InstanceShutdown         = InstanceAction(api.EventLifecycleInstanceShutdown)
+	InstanceSSHKeysUpdated   = InstanceAction(api.EventLifecycleInstanceSSHKeysUpdated)
 	InstanceStarted          = InstanceAction(api.EventLifecycleInstanceStarted)
 	InstanceStopped          = InstanceAction(api.EventLifecycleInstanceStopped)
 	InstanceUpdated          = InstanceAction(api.EventLifecycleInstanceUpdated)
@@ -0,0 +1,29 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// WebhookAction represents a lifecycle event action for webhooks.
+type WebhookAction string
+
+// All supported lifecycle events for webhooks.
+const (
+	WebhookCreated            = WebhookAction(api.EventLifecycleWebhookCreated)
+	WebhookDeadLettersDeleted = WebhookAction(api.EventLifecycleWebhookDeadLettersDeleted)
+	WebhookDeleted            = WebhookAction(api.EventLifecycleWebhookDeleted)
+	WebhookUpdated            = WebhookAction(api.EventLifecycleWebhookUpdated)
+)
+
+// Event creates the lifecycle event for an action on a webhook.
+func (a WebhookAction) Event(name string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "webhooks", name)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
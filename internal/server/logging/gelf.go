@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+)
+
+const (
+	// gelfChunkSize is the maximum size of the UDP datagrams.
+	gelfChunkSize = 8192
+
+	// gelfMaxChunks is the maximum number of chunks of a message.
+	gelfMaxChunks = 128
+)
+
+// gelfFieldName matches the characters which aren't allowed in the names of additional fields.
+var gelfFieldName = regexp.MustCompile(`[^\w\.\-]`)
+
+// GELFClient sends the events to a Graylog server, using GELF messages over TCP, TLS or UDP.
+type GELFClient struct {
+	*streamClient
+}
+
+// NewGELFClient returns a GELFClient sending to the given address ("host:port").
+// The protocol is either "tcp", "tls" or "udp".
+func NewGELFClient(ctx context.Context, address string, protocol string, caCert string, cfg Config) (*GELFClient, error) {
+	stream, err := newStreamClient(ctx, address, protocol, caCert, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	stream.encode = func(entry *Entry) []byte {
+		buf, err := gelfMessage(entry)
+		if err != nil {
+			return nil
+		}
+
+		// Messages are null-byte delimited over TCP.
+		if protocol != "udp" {
+			buf = append(buf, 0)
+		}
+
+		return buf
+	}
+
+	if protocol == "udp" {
+		stream.write = gelfWriteChunked
+	}
+
+	stream.start()
+
+	return &GELFClient{streamClient: stream}, nil
+}
+
+// gelfMessage returns the GELF 1.1 message of the entry, with the labels as additional fields.
+func gelfMessage(entry *Entry) ([]byte, error) {
+	msg := map[string]any{
+		"version":       "1.1",
+		"host":          entry.Labels["location"],
+		"short_message": entry.Message,
+		"timestamp":     float64(entry.Timestamp.UnixMicro()) / 1e6,
+		"level":         syslogSeverity(entry.Level),
+	}
+
+	if msg["host"] == "" {
+		msg["host"] = "incus"
+	}
+
+	for k, v := range entry.Labels {
+		name := gelfFieldName.ReplaceAllString(k, "_")
+
+		// The "_id" field is reserved.
+		if name == "id" {
+			name = "id_"
+		}
+
+		msg["_"+name] = v
+	}
+
+	return json.Marshal(msg)
+}
+
+// gelfWriteChunked writes a message as UDP datagrams, splitting it into chunks if needed.
+func gelfWriteChunked(conn net.Conn, buf []byte) error {
+	if len(buf) <= gelfChunkSize {
+		_, err := conn.Write(buf)
+		return err
+	}
+
+	// Each chunk starts with the magic bytes, the message ID, the sequence number and count.
+	dataSize := gelfChunkSize - 12
+	count := (len(buf) + dataSize - 1) / dataSize
+	if count > gelfMaxChunks {
+		return fmt.Errorf("Message is too large (%d bytes)", len(buf))
+	}
+
+	id := make([]byte, 8)
+	_, err := rand.Read(id)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < count; i++ {
+		data := buf[i*dataSize : min((i+1)*dataSize, len(buf))]
+
+		chunk := make([]byte, 0, 12+len(data))
+		chunk = append(chunk, 0x1e, 0x0f)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, data...)
+
+		_, err := conn.Write(chunk)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
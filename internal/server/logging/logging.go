@@ -0,0 +1,253 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Target represents a remote logging target the server events are sent to.
+type Target interface {
+	// HandleEvent handles the event received from the internal event listener.
+	HandleEvent(event api.Event)
+
+	// Stop stops the target.
+	Stop()
+}
+
+// Config holds the settings common to all logging targets.
+type Config struct {
+	// Instance is the value of the instance label.
+	Instance string
+
+	// Location overrides the location of the events (used on standalone systems).
+	Location string
+
+	// LogLevel is the minimum level of the log messages to send.
+	LogLevel string
+
+	// Labels is the list of context keys to send as labels rather than as part of the message.
+	Labels []string
+
+	// Types is the list of event types to send.
+	Types []string
+}
+
+// Entry represents a log entry built from an event.
+type Entry struct {
+	Timestamp time.Time
+	Level     string
+	Labels    map[string]string
+	Message   string
+}
+
+// NewEntry returns the log entry for the given event, or nil if the event shouldn't be sent.
+func (c *Config) NewEntry(event api.Event) *Entry {
+	if !slices.Contains(c.Types, event.Type) {
+		return nil
+	}
+
+	// Support overriding the location field (used on standalone systems).
+	location := event.Location
+	if c.Location != "" {
+		location = c.Location
+	}
+
+	entry := Entry{
+		Timestamp: event.Timestamp,
+		Level:     logrus.InfoLevel.String(),
+		Labels: map[string]string{
+			"app":      "incus",
+			"type":     event.Type,
+			"location": location,
+			"instance": c.Instance,
+		},
+	}
+
+	context := make(map[string]string)
+
+	if event.Type == api.EventTypeLifecycle {
+		lifecycleEvent := api.EventLifecycle{}
+
+		err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+		if err != nil {
+			return nil
+		}
+
+		if lifecycleEvent.Name != "" {
+			entry.Labels["name"] = lifecycleEvent.Name
+		}
+
+		if lifecycleEvent.Project != "" {
+			entry.Labels["project"] = lifecycleEvent.Project
+		}
+
+		// Build map. These key-value pairs will either be added as labels, or be part of the
+		// log message itself.
+		context["action"] = lifecycleEvent.Action
+		context["source"] = lifecycleEvent.Source
+
+		for k, v := range buildNestedContext("context", lifecycleEvent.Context) {
+			context[k] = v
+		}
+
+		if lifecycleEvent.Requestor != nil {
+			context["requester-address"] = lifecycleEvent.Requestor.Address
+			context["requester-protocol"] = lifecycleEvent.Requestor.Protocol
+			context["requester-username"] = lifecycleEvent.Requestor.Username
+		}
+
+		// Add key-value pairs as labels but don't override any labels.
+		for _, k := range sortedKeys(context) {
+			v := context[k]
+
+			if slices.Contains(c.Labels, k) {
+				_, ok := entry.Labels[k]
+				if !ok {
+					// Label names may not contain any hyphens.
+					entry.Labels[strings.ReplaceAll(k, "-", "_")] = v
+					delete(context, k)
+				}
+			}
+		}
+
+		entry.Message = formatMessage(context, lifecycleEvent.Action)
+	} else if event.Type == api.EventTypeLogging || event.Type == api.EventTypeNetworkACL || event.Type == api.EventTypeInstanceLog {
+		logEvent := api.EventLogging{}
+
+		err := json.Unmarshal(event.Metadata, &logEvent)
+		if err != nil {
+			return nil
+		}
+
+		// Instance logs are labeled with the instance they come from.
+		if event.Type == api.EventTypeInstanceLog {
+			entry.Labels["name"] = logEvent.Context["name"]
+			entry.Labels["project"] = event.Project
+			delete(logEvent.Context, "name")
+		}
+
+		// The errors can be ignored as the values are validated elsewhere.
+		l1, _ := logrus.ParseLevel(logEvent.Level)
+		l2, _ := logrus.ParseLevel(c.LogLevel)
+
+		// Only consider log messages with a certain log level.
+		if l2 < l1 {
+			return nil
+		}
+
+		entry.Level = logEvent.Level
+
+		tmpContext := map[string]any{}
+
+		// Convert map[string]string to map[string]any as buildNestedContext takes the latter type.
+		for k, v := range logEvent.Context {
+			tmpContext[k] = v
+		}
+
+		// Build map. These key-value pairs will either be added as labels, or be part of the
+		// log message itself.
+		context["level"] = logEvent.Level
+
+		for k, v := range buildNestedContext("context", tmpContext) {
+			context[k] = v
+		}
+
+		// Add key-value pairs as labels but don't override any labels.
+		for k, v := range context {
+			if slices.Contains(c.Labels, k) {
+				_, ok := entry.Labels[k]
+				if !ok {
+					entry.Labels[k] = v
+					delete(context, k)
+				}
+			}
+		}
+
+		entry.Message = formatMessage(context, logEvent.Message)
+	}
+
+	return &entry
+}
+
+// formatMessage prefixes the message with the given context. The keys are sorted alphabetically.
+func formatMessage(context map[string]string, message string) string {
+	var b strings.Builder
+
+	for _, k := range sortedKeys(context) {
+		b.WriteString(fmt.Sprintf("%s=%q ", k, context[k]))
+	}
+
+	b.WriteString(message)
+
+	return b.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func buildNestedContext(prefix string, m map[string]any) map[string]string {
+	labels := map[string]string{}
+
+	for k, v := range m {
+		t := reflect.TypeOf(v)
+
+		if t != nil && t.Kind() == reflect.Map {
+			for k, v := range buildNestedContext(k, v.(map[string]any)) {
+				if prefix == "" {
+					labels[k] = v
+				} else {
+					labels[fmt.Sprintf("%s-%s", prefix, k)] = v
+				}
+			}
+		} else {
+			if prefix == "" {
+				labels[k] = fmt.Sprintf("%v", v)
+			} else {
+				labels[fmt.Sprintf("%s-%s", prefix, k)] = fmt.Sprintf("%v", v)
+			}
+		}
+	}
+
+	return labels
+}
+
+// syslogSeverity returns the syslog severity of a log level, as used by syslog and GELF.
+func syslogSeverity(level string) int {
+	l, err := logrus.ParseLevel(level)
+	if err != nil {
+		return 6
+	}
+
+	switch l {
+	case logrus.PanicLevel:
+		return 0
+	case logrus.FatalLevel:
+		return 2
+	case logrus.ErrorLevel:
+		return 3
+	case logrus.WarnLevel:
+		return 4
+	case logrus.InfoLevel:
+		return 6
+	default:
+		return 7
+	}
+}
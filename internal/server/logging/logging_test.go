@@ -0,0 +1,255 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func lifecycleEvent(t *testing.T) api.Event {
+	metadata, err := json.Marshal(api.EventLifecycle{
+		Action:  "instance-started",
+		Source:  "/1.0/instances/c1",
+		Name:    "c1",
+		Project: "default",
+		Context: map[string]any{"foo": "bar"},
+	})
+	require.NoError(t, err)
+
+	return api.Event{
+		Type:      api.EventTypeLifecycle,
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Location:  "node1",
+		Metadata:  metadata,
+	}
+}
+
+func loggingEvent(t *testing.T, level string) api.Event {
+	metadata, err := json.Marshal(api.EventLogging{
+		Message: "Hello",
+		Level:   level,
+		Context: map[string]string{"instance": "c1"},
+	})
+	require.NoError(t, err)
+
+	return api.Event{
+		Type:      api.EventTypeLogging,
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Location:  "node1",
+		Metadata:  metadata,
+	}
+}
+
+func instanceLogEvent(t *testing.T) api.Event {
+	metadata, err := json.Marshal(api.EventLogging{
+		Message: "Reached target Multi-User System.",
+		Level:   "info",
+		Context: map[string]string{"name": "c1", "source": "console"},
+	})
+	require.NoError(t, err)
+
+	return api.Event{
+		Type:      api.EventTypeInstanceLog,
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Location:  "node1",
+		Project:   "p1",
+		Metadata:  metadata,
+	}
+}
+
+func TestNewEntry(t *testing.T) {
+	cfg := Config{
+		Instance: "cluster",
+		LogLevel: "info",
+		Labels:   []string{"source"},
+		Types:    []string{api.EventTypeLifecycle, api.EventTypeLogging},
+	}
+
+	entry := cfg.NewEntry(lifecycleEvent(t))
+	require.NotNil(t, entry)
+	assert.Equal(t, "info", entry.Level)
+	assert.Equal(t, map[string]string{"app": "incus", "type": "lifecycle", "location": "node1", "instance": "cluster", "name": "c1", "project": "default", "source": "/1.0/instances/c1"}, entry.Labels)
+	assert.Equal(t, `action="instance-started" context-foo="bar" instance-started`, entry.Message)
+
+	entry = cfg.NewEntry(loggingEvent(t, "warning"))
+	require.NotNil(t, entry)
+	assert.Equal(t, "warning", entry.Level)
+	assert.Equal(t, `context-instance="c1" level="warning" Hello`, entry.Message)
+
+	// Filtered by level.
+	assert.Nil(t, cfg.NewEntry(loggingEvent(t, "debug")))
+
+	// Filtered by type.
+	cfg.Types = []string{api.EventTypeLogging}
+	assert.Nil(t, cfg.NewEntry(lifecycleEvent(t)))
+	assert.Nil(t, cfg.NewEntry(instanceLogEvent(t)))
+
+	// Instance logs are labeled with the instance name and project.
+	cfg.Types = []string{api.EventTypeInstanceLog}
+	entry = cfg.NewEntry(instanceLogEvent(t))
+	require.NotNil(t, entry)
+	assert.Equal(t, map[string]string{"app": "incus", "type": "instance-log", "location": "node1", "instance": "cluster", "name": "c1", "project": "p1"}, entry.Labels)
+	assert.Equal(t, `context-source="console" level="info" Reached target Multi-User System.`, entry.Message)
+}
+
+func TestSyslogMessage(t *testing.T) {
+	entry := &Entry{
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:     "error",
+		Labels:    map[string]string{"location": "node 1", "type": "logging", "name": `a"b]`},
+		Message:   "Failed",
+	}
+
+	assert.Equal(t, `<27>1 2024-01-02T03:04:05.000000Z node1 incus - logging [incus@32473 location="node 1" name="a\"b\]" type="logging"] Failed`, syslogMessage(entry))
+}
+
+func TestSyslogClient(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	lines := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		line, _ := bufio.NewReader(conn).ReadString(']')
+		lines <- line
+	}()
+
+	client, err := NewSyslogClient(context.Background(), listener.Addr().String(), "tcp", "", Config{Types: []string{api.EventTypeLifecycle}})
+	require.NoError(t, err)
+	defer client.Stop()
+
+	client.HandleEvent(lifecycleEvent(t))
+
+	select {
+	case line := <-lines:
+		assert.Equal(t, `233 <30>1 2024-01-02T03:04:05.000000Z node1 incus - lifecycle [incus@32473 app="incus" location="node1" name="c1" project="default" type="lifecycle"]`, line)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Message wasn't received")
+	}
+}
+
+func TestSyslogClientStop(t *testing.T) {
+	// Get an address nothing listens on.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	client, err := NewSyslogClient(context.Background(), address, "tcp", "", Config{Types: []string{api.EventTypeLifecycle}})
+	require.NoError(t, err)
+
+	for i := 0; i < streamQueueSize; i++ {
+		client.HandleEvent(lifecycleEvent(t))
+	}
+
+	// Stopping must neither wait for the retries nor try each of the pending entries.
+	start := time.Now()
+	client.Stop()
+	assert.Less(t, time.Since(start), streamStopTimeout+time.Second)
+}
+
+func TestGELFMessage(t *testing.T) {
+	entry := &Entry{
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 500000000, time.UTC),
+		Level:     "warning",
+		Labels:    map[string]string{"location": "node1", "id": "1", "context-foo": "bar"},
+		Message:   "Hello",
+	}
+
+	buf, err := gelfMessage(entry)
+	require.NoError(t, err)
+
+	msg := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf, &msg))
+	assert.Equal(t, map[string]any{
+		"version":       "1.1",
+		"host":          "node1",
+		"short_message": "Hello",
+		"timestamp":     1704164645.5,
+		"level":         float64(4),
+		"_location":     "node1",
+		"_id_":          "1",
+		"_context-foo":  "bar",
+	}, msg)
+}
+
+func TestGELFWriteChunked(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer server.Close()
+
+	conn, err := net.Dial("udp", server.LocalAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	message := []byte(strings.Repeat("a", gelfChunkSize*2))
+	require.NoError(t, gelfWriteChunked(conn, message))
+
+	var data []byte
+	buf := make([]byte, gelfChunkSize)
+	for i := 0; i < 3; i++ {
+		_ = server.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := server.ReadFrom(buf)
+		require.NoError(t, err)
+
+		assert.Equal(t, []byte{0x1e, 0x0f}, buf[:2])
+		assert.Equal(t, byte(i), buf[10])
+		assert.Equal(t, byte(3), buf[11])
+		data = append(data, buf[12:n]...)
+	}
+
+	assert.Equal(t, message, data)
+}
+
+func TestOTLPClient(t *testing.T) {
+	requests := make(chan otlpRequest, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/logs", r.URL.Path)
+
+		request := otlpRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		requests <- request
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client, err := NewOTLPClient(context.Background(), u, "", nil, Config{Instance: "node1", LogLevel: "info", Types: []string{api.EventTypeLogging}})
+	require.NoError(t, err)
+
+	client.HandleEvent(loggingEvent(t, "error"))
+	client.Stop()
+
+	request := <-requests
+	require.Len(t, request.ResourceLogs, 1)
+	require.Len(t, request.ResourceLogs[0].ScopeLogs, 1)
+
+	records := request.ResourceLogs[0].ScopeLogs[0].LogRecords
+	require.Len(t, records, 1)
+	assert.Equal(t, 17, records[0].SeverityNumber)
+	assert.Equal(t, "ERROR", records[0].SeverityText)
+	assert.Equal(t, `context-instance="c1" level="error" Hello`, records[0].Body.StringValue)
+	assert.Equal(t, "1704164645000000000", records[0].TimeUnixNano)
+}
@@ -0,0 +1,295 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+const (
+	otlpMaxErrMsgLen = 1024
+
+	// otlpBatchSize is the maximum number of log records sent in a single request.
+	otlpBatchSize = 512
+
+	// otlpBatchWait is the maximum time log records are held before being sent.
+	otlpBatchWait = time.Second
+)
+
+// OTLPClient sends the events to an OpenTelemetry collector, using OTLP over HTTP with the JSON encoding.
+type OTLPClient struct {
+	cfg      Config
+	url      *url.URL
+	headers  map[string]string
+	client   *http.Client
+	resource otlpResource
+
+	ctx     context.Context
+	quit    chan struct{}
+	once    sync.Once
+	entries chan *Entry
+	wg      sync.WaitGroup
+}
+
+// NewOTLPClient returns an OTLPClient sending to the OTLP/HTTP endpoint at the given URL.
+func NewOTLPClient(ctx context.Context, u *url.URL, caCert string, headers map[string]string, cfg Config) (*OTLPClient, error) {
+	client := OTLPClient{
+		cfg:     cfg,
+		url:     u,
+		headers: headers,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		ctx:     ctx,
+		quit:    make(chan struct{}),
+		entries: make(chan *Entry, streamQueueSize),
+	}
+
+	if caCert != "" {
+		tlsConfig, err := localtls.GetTLSConfigMem("", "", caCert, "", false)
+		if err != nil {
+			return nil, err
+		}
+
+		client.client.Transport = &http.Transport{
+			TLSClientConfig: tlsConfig,
+		}
+	}
+
+	client.resource.Attributes = otlpAttributes(map[string]string{
+		"service.name":        "incus",
+		"service.version":     version.Version,
+		"service.instance.id": cfg.Instance,
+	})
+
+	client.wg.Add(1)
+	go client.run()
+
+	return &client, nil
+}
+
+// HandleEvent handles the event received from the internal event listener.
+func (c *OTLPClient) HandleEvent(event api.Event) {
+	entry := c.cfg.NewEntry(event)
+	if entry == nil {
+		return
+	}
+
+	select {
+	case c.entries <- entry:
+	default:
+	}
+}
+
+// Stop stops the client after sending the pending entries.
+func (c *OTLPClient) Stop() {
+	c.once.Do(func() { close(c.quit) })
+	c.wg.Wait()
+}
+
+func (c *OTLPClient) run() {
+	defer c.wg.Done()
+
+	batch := make([]otlpLogRecord, 0, otlpBatchSize)
+	ticker := time.NewTicker(otlpBatchWait)
+	defer ticker.Stop()
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		c.sendBatch(batch)
+		batch = make([]otlpLogRecord, 0, otlpBatchSize)
+	}
+
+	add := func(entry *Entry) {
+		batch = append(batch, otlpRecord(entry))
+		if len(batch) >= otlpBatchSize {
+			flush()
+		}
+	}
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+
+		case <-c.quit:
+			// Send the pending entries.
+			for len(c.entries) > 0 {
+				add(<-c.entries)
+			}
+
+			flush()
+			return
+
+		case entry := <-c.entries:
+			add(entry)
+
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (c *OTLPClient) sendBatch(batch []otlpLogRecord) {
+	request := otlpRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource: c.resource,
+		ScopeLogs: []otlpScopeLogs{{
+			Scope:      otlpScope{Name: "incus", Version: version.Version},
+			LogRecords: batch,
+		}},
+	}}}
+
+	buf, err := json.Marshal(request)
+	if err != nil {
+		return
+	}
+
+	for i := 0; i < 3; i++ {
+		// Try to send the log records.
+		status, err := c.send(buf)
+		if err == nil {
+			return
+		}
+
+		// Only retry 429s, 500s and connection-level errors.
+		if status > 0 && status != http.StatusTooManyRequests && status/100 != 5 {
+			return
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(time.Duration(i+1) * time.Second):
+		}
+	}
+}
+
+func (c *OTLPClient) send(buf []byte) (int, error) {
+	req, err := http.NewRequestWithContext(c.ctx, "POST", c.url.JoinPath("/v1/logs").String(), bytes.NewReader(buf))
+	if err != nil {
+		return -1, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent)
+
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return -1, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		line, _ := io.ReadAll(io.LimitReader(resp.Body, otlpMaxErrMsgLen))
+
+		return resp.StatusCode, fmt.Errorf("Server returned HTTP status %s (%d): %s", resp.Status, resp.StatusCode, line)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// otlpRecord returns the OTLP log record of the entry, with the labels as attributes.
+func otlpRecord(entry *Entry) otlpLogRecord {
+	record := otlpLogRecord{
+		TimeUnixNano:         strconv.FormatInt(entry.Timestamp.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		SeverityText:         strings.ToUpper(entry.Level),
+		Body:                 otlpValue{StringValue: entry.Message},
+		Attributes:           otlpAttributes(entry.Labels),
+	}
+
+	level, err := logrus.ParseLevel(entry.Level)
+	if err != nil {
+		level = logrus.InfoLevel
+	}
+
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		record.SeverityNumber = 21
+	case logrus.ErrorLevel:
+		record.SeverityNumber = 17
+	case logrus.WarnLevel:
+		record.SeverityNumber = 13
+	case logrus.InfoLevel:
+		record.SeverityNumber = 9
+	case logrus.DebugLevel:
+		record.SeverityNumber = 5
+	default:
+		record.SeverityNumber = 1
+	}
+
+	return record
+}
+
+// OTLP JSON encoding of the log export requests (opentelemetry/proto/collector/logs/v1).
+
+type otlpRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string          `json:"timeUnixNano"`
+	ObservedTimeUnixNano string          `json:"observedTimeUnixNano"`
+	SeverityNumber       int             `json:"severityNumber"`
+	SeverityText         string          `json:"severityText"`
+	Body                 otlpValue       `json:"body"`
+	Attributes           []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// otlpAttributes converts labels to their OTLP encoding, sorted by key.
+func otlpAttributes(labels map[string]string) []otlpAttribute {
+	attributes := make([]otlpAttribute, 0, len(labels))
+	for _, k := range sortedKeys(labels) {
+		attributes = append(attributes, otlpAttribute{Key: k, Value: otlpValue{StringValue: labels[k]}})
+	}
+
+	return attributes
+}
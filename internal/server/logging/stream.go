@@ -0,0 +1,206 @@
+package logging
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/shared/api"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+const (
+	// streamQueueSize is the number of entries that can be waiting to be sent, further entries are dropped.
+	streamQueueSize = 1024
+
+	// streamAttempts is the number of attempts at sending an entry.
+	streamAttempts = 5
+
+	// streamTimeout is the timeout for connecting and writing an entry.
+	streamTimeout = 10 * time.Second
+
+	// streamStopTimeout is how long stopping the client may spend sending the pending entries.
+	streamStopTimeout = 5 * time.Second
+)
+
+// streamRetryDelay is the delay before the first retry, doubled on every attempt.
+var streamRetryDelay = time.Second
+
+// streamClient sends entries over a TCP, TLS or UDP connection, reconnecting as needed.
+type streamClient struct {
+	cfg       Config
+	network   string
+	address   string
+	tlsConfig *tls.Config
+
+	// encode returns the entry as sent over the connection.
+	encode func(entry *Entry) []byte
+
+	// write writes an encoded entry to the connection.
+	write func(conn net.Conn, buf []byte) error
+
+	conn    net.Conn
+	ctx     context.Context
+	quit    chan struct{}
+	once    sync.Once
+	entries chan *Entry
+	wg      sync.WaitGroup
+}
+
+func newStreamClient(ctx context.Context, address string, protocol string, caCert string, cfg Config) (*streamClient, error) {
+	client := streamClient{
+		cfg:     cfg,
+		network: protocol,
+		address: address,
+		ctx:     ctx,
+		quit:    make(chan struct{}),
+		entries: make(chan *Entry, streamQueueSize),
+		write: func(conn net.Conn, buf []byte) error {
+			_, err := conn.Write(buf)
+			return err
+		},
+	}
+
+	if protocol == "tls" {
+		tlsConfig, err := localtls.GetTLSConfigMem("", "", caCert, "", false)
+		if err != nil {
+			return nil, err
+		}
+
+		client.network = "tcp"
+		client.tlsConfig = tlsConfig
+	}
+
+	return &client, nil
+}
+
+func (c *streamClient) start() {
+	c.wg.Add(1)
+	go c.run()
+}
+
+// HandleEvent handles the event received from the internal event listener.
+func (c *streamClient) HandleEvent(event api.Event) {
+	entry := c.cfg.NewEntry(event)
+	if entry == nil {
+		return
+	}
+
+	select {
+	case c.entries <- entry:
+	default:
+	}
+}
+
+// Stop stops the client after trying to send the pending entries.
+func (c *streamClient) Stop() {
+	c.once.Do(func() { close(c.quit) })
+	c.wg.Wait()
+}
+
+func (c *streamClient) run() {
+	defer c.wg.Done()
+
+	defer c.disconnect()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+
+		case <-c.quit:
+			c.flush()
+			return
+
+		case entry := <-c.entries:
+			c.send(entry)
+		}
+	}
+}
+
+// flush sends the pending entries on a best-effort basis, giving up on the first failure or once
+// streamStopTimeout is reached so stopping the client doesn't block.
+func (c *streamClient) flush() {
+	deadline := time.Now().Add(streamStopTimeout)
+
+	for len(c.entries) > 0 && time.Now().Before(deadline) {
+		buf := c.encode(<-c.entries)
+		if buf == nil {
+			continue
+		}
+
+		err := c.sendBuf(buf, deadline)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (c *streamClient) send(entry *Entry) {
+	buf := c.encode(entry)
+	if buf == nil {
+		return
+	}
+
+	delay := streamRetryDelay
+
+	for i := 0; i < streamAttempts; i++ {
+		err := c.sendBuf(buf, time.Now().Add(streamTimeout))
+		if err == nil {
+			return
+		}
+
+		// Reconnect on the next attempt.
+		c.disconnect()
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-c.quit:
+			return
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+// disconnect closes the connection, if any.
+func (c *streamClient) disconnect() {
+	if c.conn != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+}
+
+// sendBuf writes the encoded entry to the connection, connecting first if needed. Connecting and writing
+// must complete before the deadline.
+func (c *streamClient) sendBuf(buf []byte, deadline time.Time) error {
+	if c.conn == nil {
+		dialer := &net.Dialer{Deadline: deadline}
+
+		var conn net.Conn
+		var err error
+
+		if c.tlsConfig != nil {
+			conn, err = tls.DialWithDialer(dialer, c.network, c.address, c.tlsConfig)
+		} else {
+			conn, err = dialer.DialContext(c.ctx, c.network, c.address)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		c.conn = conn
+	}
+
+	err := c.conn.SetWriteDeadline(deadline)
+	if err != nil {
+		return err
+	}
+
+	return c.write(c.conn, buf)
+}
@@ -4,21 +4,17 @@ import (
 	"bufio"
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"reflect"
-	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/sirupsen/logrus"
-
+	"github.com/lxc/incus/v6/internal/server/logging"
 	"github.com/lxc/incus/v6/shared/api"
 	localtls "github.com/lxc/incus/v6/shared/tls"
 )
@@ -34,14 +30,11 @@ type config struct {
 	batchSize int
 	batchWait time.Duration
 
+	logging.Config
+
 	caCert   string
 	username string
 	password string
-	labels   []string
-	instance string
-	logLevel string
-	types    []string
-	location string
 
 	timeout time.Duration
 	url     *url.URL
@@ -64,20 +57,16 @@ type Client struct {
 }
 
 // NewClient returns a Client.
-func NewClient(ctx context.Context, u *url.URL, username string, password string, caCert string, instance string, location string, logLevel string, labels []string, types []string) *Client {
+func NewClient(ctx context.Context, u *url.URL, username string, password string, caCert string, cfg logging.Config) *Client {
 	client := Client{
 		cfg: config{
+			Config:    cfg,
 			batchSize: 10 * 1024,
 			batchWait: 1 * time.Second,
 			caCert:    caCert,
 			username:  username,
 			password:  password,
-			instance:  instance,
-			location:  location,
-			labels:    labels,
-			logLevel:  logLevel,
 			timeout:   10 * time.Second,
-			types:     types,
 			url:       u,
 		},
 		client:  &http.Client{},
@@ -228,182 +217,18 @@ func (c *Client) Stop() {
 
 // HandleEvent handles the event received from the internal event listener.
 func (c *Client) HandleEvent(event api.Event) {
-	if !slices.Contains(c.cfg.types, event.Type) {
+	e := c.cfg.NewEntry(event)
+	if e == nil {
 		return
 	}
 
-	// Support overriding the location field (used on standalone systems).
-	location := event.Location
-	if c.cfg.location != "" {
-		location = c.cfg.location
-	}
-
-	entry := entry{
-		labels: LabelSet{
-			"app":      "incus",
-			"type":     event.Type,
-			"location": location,
-			"instance": c.cfg.instance,
-		},
+	c.entries <- entry{
+		labels: LabelSet(e.Labels),
 		Entry: Entry{
-			Timestamp: event.Timestamp,
+			Timestamp: e.Timestamp,
+			Line:      e.Message,
 		},
 	}
-
-	context := make(map[string]string)
-
-	if event.Type == api.EventTypeLifecycle {
-		lifecycleEvent := api.EventLifecycle{}
-
-		err := json.Unmarshal(event.Metadata, &lifecycleEvent)
-		if err != nil {
-			return
-		}
-
-		if lifecycleEvent.Name != "" {
-			entry.labels["name"] = lifecycleEvent.Name
-		}
-
-		if lifecycleEvent.Project != "" {
-			entry.labels["project"] = lifecycleEvent.Project
-		}
-
-		// Build map. These key-value pairs will either be added as labels, or be part of the
-		// log message itself.
-		context["action"] = lifecycleEvent.Action
-		context["source"] = lifecycleEvent.Source
-
-		for k, v := range buildNestedContext("context", lifecycleEvent.Context) {
-			context[k] = v
-		}
-
-		if lifecycleEvent.Requestor != nil {
-			context["requester-address"] = lifecycleEvent.Requestor.Address
-			context["requester-protocol"] = lifecycleEvent.Requestor.Protocol
-			context["requester-username"] = lifecycleEvent.Requestor.Username
-		}
-
-		// Get a sorted list of context keys.
-		keys := make([]string, 0, len(context))
-
-		for k := range context {
-			keys = append(keys, k)
-		}
-
-		sort.Strings(keys)
-
-		// Add key-value pairs as labels but don't override any labels.
-		for _, k := range keys {
-			v := context[k]
-
-			if slices.Contains(c.cfg.labels, k) {
-				_, ok := entry.labels[k]
-				if !ok {
-					// Label names may not contain any hyphens.
-					entry.labels[strings.ReplaceAll(k, "-", "_")] = v
-					delete(context, k)
-				}
-			}
-		}
-
-		messagePrefix := ""
-
-		// Add the remaining context as the message prefix.
-		for k, v := range context {
-			messagePrefix += fmt.Sprintf("%s=\"%s\" ", k, v)
-		}
-
-		entry.Line = fmt.Sprintf("%s%s", messagePrefix, lifecycleEvent.Action)
-	} else if event.Type == api.EventTypeLogging || event.Type == api.EventTypeNetworkACL {
-		logEvent := api.EventLogging{}
-
-		err := json.Unmarshal(event.Metadata, &logEvent)
-		if err != nil {
-			return
-		}
-
-		// The errors can be ignored as the values are validated elsewhere.
-		l1, _ := logrus.ParseLevel(logEvent.Level)
-		l2, _ := logrus.ParseLevel(c.cfg.logLevel)
-
-		// Only consider log messages with a certain log level.
-		if l2 < l1 {
-			return
-		}
-
-		tmpContext := map[string]any{}
-
-		// Convert map[string]string to map[string]any as buildNestedContext takes the latter type.
-		for k, v := range logEvent.Context {
-			tmpContext[k] = v
-		}
-
-		// Build map. These key-value pairs will either be added as labels, or be part of the
-		// log message itself.
-		context["level"] = logEvent.Level
-
-		for k, v := range buildNestedContext("context", tmpContext) {
-			context[k] = v
-		}
-
-		// Add key-value pairs as labels but don't override any labels.
-		for k, v := range context {
-			if slices.Contains(c.cfg.labels, k) {
-				_, ok := entry.labels[k]
-				if !ok {
-					entry.labels[k] = v
-					delete(context, k)
-				}
-			}
-		}
-
-		keys := make([]string, 0, len(context))
-
-		for k := range context {
-			keys = append(keys, k)
-		}
-
-		sort.Strings(keys)
-
-		var message strings.Builder
-
-		// Add the remaining context as the message prefix. The keys are sorted alphabetically.
-		for _, k := range keys {
-			message.WriteString(fmt.Sprintf("%s=%q ", k, context[k]))
-		}
-
-		message.WriteString(logEvent.Message)
-
-		entry.Line = message.String()
-	}
-
-	c.entries <- entry
-}
-
-func buildNestedContext(prefix string, m map[string]any) map[string]string {
-	labels := map[string]string{}
-
-	for k, v := range m {
-		t := reflect.TypeOf(v)
-
-		if t != nil && t.Kind() == reflect.Map {
-			for k, v := range buildNestedContext(k, v.(map[string]any)) {
-				if prefix == "" {
-					labels[k] = v
-				} else {
-					labels[fmt.Sprintf("%s-%s", prefix, k)] = v
-				}
-			}
-		} else {
-			if prefix == "" {
-				labels[k] = fmt.Sprintf("%v", v)
-			} else {
-				labels[fmt.Sprintf("%s-%s", prefix, k)] = fmt.Sprintf("%v", v)
-			}
-		}
-	}
-
-	return labels
 }
 
 // MarshalJSON returns the JSON encoding of Entry.
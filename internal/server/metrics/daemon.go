@@ -0,0 +1,28 @@
+package metrics
+
+// Internal metrics of the daemon, updated as the requests, transactions and transfers happen.
+var (
+	// APIRequests records the duration of the API requests, by method and endpoint.
+	APIRequests = NewHistogram(LatencyBuckets)
+
+	// DqliteTransactions records the duration of the cluster database transactions.
+	DqliteTransactions = NewHistogram(LatencyBuckets)
+
+	// Operations records the duration of the completed operations, by type and status.
+	Operations = NewHistogram(DurationBuckets)
+
+	// ImageDownloadBytes counts the bytes of the downloaded images, by protocol.
+	ImageDownloadBytes = NewCounter()
+
+	// MigrationBytes counts the bytes transferred by migrations, by direction ("sent" or "received").
+	MigrationBytes = NewCounter()
+)
+
+// AddDaemonSamples adds the samples of the internal metrics of the daemon to the MetricSet.
+func (m *MetricSet) AddDaemonSamples() {
+	m.AddSamples(APIRequestDurationSeconds, APIRequests.Samples()...)
+	m.AddSamples(DqliteTransactionSeconds, DqliteTransactions.Samples()...)
+	m.AddSamples(OperationDurationSeconds, Operations.Samples()...)
+	m.AddSamples(ImageDownloadBytesTotal, ImageDownloadBytes.Samples()...)
+	m.AddSamples(MigrationBytesTotal, MigrationBytes.Samples()...)
+}
@@ -0,0 +1,185 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LatencyBuckets are the upper bounds, in seconds, of the buckets used for request latencies.
+var LatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// DurationBuckets are the upper bounds, in seconds, of the buckets used for long running tasks.
+var DurationBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 1800, 3600}
+
+// Histogram counts the observed values in cumulative buckets, separately for each set of labels.
+type Histogram struct {
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labels map[string]string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram returns a new Histogram with the given bucket upper bounds, in increasing order.
+func NewHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		buckets: buckets,
+		series:  map[string]*histogramSeries{},
+	}
+}
+
+// Observe records a value for the given labels.
+func (h *Histogram) Observe(labels map[string]string, value float64) {
+	key := seriesKey(labels)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogramSeries{
+			labels: copyLabels(labels),
+			counts: make([]uint64, len(h.buckets)),
+		}
+
+		h.series[key] = series
+	}
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			series.counts[i]++
+		}
+	}
+
+	series.count++
+	series.sum += value
+}
+
+// Samples returns the bucket, sum and count samples of the histogram.
+func (h *Histogram) Samples() []Sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := make([]Sample, 0, len(h.series)*(len(h.buckets)+3))
+
+	for _, key := range sortedSeriesKeys(h.series) {
+		series := h.series[key]
+
+		for i, bound := range h.buckets {
+			labels := copyLabels(series.labels)
+			labels["le"] = formatBound(bound)
+			samples = append(samples, Sample{Labels: labels, Value: float64(series.counts[i]), Suffix: "_bucket"})
+		}
+
+		labels := copyLabels(series.labels)
+		labels["le"] = formatBound(math.Inf(1))
+		samples = append(samples, Sample{Labels: labels, Value: float64(series.count), Suffix: "_bucket"})
+		samples = append(samples, Sample{Labels: copyLabels(series.labels), Value: series.sum, Suffix: "_sum"})
+		samples = append(samples, Sample{Labels: copyLabels(series.labels), Value: float64(series.count), Suffix: "_count"})
+	}
+
+	return samples
+}
+
+// Counter is a value that only increases, separately for each set of labels.
+type Counter struct {
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labels map[string]string
+	value  float64
+}
+
+// NewCounter returns a new Counter.
+func NewCounter() *Counter {
+	return &Counter{series: map[string]*counterSeries{}}
+}
+
+// Add increases the counter of the given labels by value.
+func (c *Counter) Add(labels map[string]string, value float64) {
+	key := seriesKey(labels)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	series, ok := c.series[key]
+	if !ok {
+		series = &counterSeries{labels: copyLabels(labels)}
+		c.series[key] = series
+	}
+
+	series.value += value
+}
+
+// Samples returns the samples of the counter.
+func (c *Counter) Samples() []Sample {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	samples := make([]Sample, 0, len(c.series))
+
+	for _, key := range sortedSeriesKeys(c.series) {
+		samples = append(samples, Sample{Labels: copyLabels(c.series[key].labels), Value: c.series[key].value})
+	}
+
+	return samples
+}
+
+// seriesKey returns a key identifying the set of labels.
+func seriesKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte(0)
+		b.WriteString(labels[name])
+		b.WriteByte(0)
+	}
+
+	return b.String()
+}
+
+func sortedSeriesKeys[T any](series map[string]T) []string {
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	out := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		out[k] = v
+	}
+
+	return out
+}
+
+func formatBound(bound float64) string {
+	if math.IsInf(bound, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(bound, 'g', -1, 64)
+}
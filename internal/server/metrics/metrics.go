@@ -65,6 +65,22 @@ func (m *MetricSet) AddSamples(metricType MetricType, samples ...Sample) {
 	m.set[metricType] = append(m.set[metricType], samples...)
 }
 
+// Sum returns the sum of the values of the samples of type metricType whose labels match the filter.
+// A nil filter matches all samples.
+func (m *MetricSet) Sum(metricType MetricType, filter func(labels map[string]string) bool) float64 {
+	var total float64
+
+	for _, sample := range m.set[metricType] {
+		if filter != nil && !filter(sample.Labels) {
+			continue
+		}
+
+		total += sample.Value
+	}
+
+	return total
+}
+
 // Merge merges two MetricSets. Missing labels from m's samples are added to all samples in n.
 func (m *MetricSet) Merge(metricSet *MetricSet) {
 	if metricSet == nil {
@@ -109,8 +125,10 @@ func (m *MetricSet) String() string {
 		metricTypeName := ""
 
 		// ProcsTotal is a gauge according to the OpenMetrics spec as its value can decrease.
-		if metricType == ProcsTotal || metricType == CPUs || metricType == GoGoroutines || metricType == GoHeapObjects {
+		if metricType == ProcsTotal || metricType == CPUs || metricType == GoGoroutines || metricType == GoHeapObjects || metricType == EventListeners {
 			metricTypeName = "gauge"
+		} else if metricType == APIRequestDurationSeconds || metricType == DqliteTransactionSeconds || metricType == OperationDurationSeconds {
+			metricTypeName = "histogram"
 		} else if strings.HasSuffix(MetricNames[metricType], "_total") || strings.HasSuffix(MetricNames[metricType], "_seconds") {
 			metricTypeName = "counter"
 		} else if strings.HasSuffix(MetricNames[metricType], "_bytes") {
@@ -147,9 +165,9 @@ func (m *MetricSet) String() string {
 			valueStr := strconv.FormatFloat(sample.Value, 'g', -1, 64)
 
 			if labels != "" {
-				_, err = out.WriteString(fmt.Sprintf("%s{%s} %s\n", MetricNames[metricType], labels, valueStr))
+				_, err = out.WriteString(fmt.Sprintf("%s%s{%s} %s\n", MetricNames[metricType], sample.Suffix, labels, valueStr))
 			} else {
-				_, err = out.WriteString(fmt.Sprintf("%s %s\n", MetricNames[metricType], valueStr))
+				_, err = out.WriteString(fmt.Sprintf("%s%s %s\n", MetricNames[metricType], sample.Suffix, valueStr))
 			}
 
 			if err != nil {
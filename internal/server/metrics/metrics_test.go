@@ -56,3 +56,48 @@ func TestMetricSet_FilterSamples(t *testing.T) {
 		require.Contains(t, hasKeys, "project")
 	}
 }
+
+func TestMetricSet_Sum(t *testing.T) {
+	m := NewMetricSet(nil)
+	m.AddSamples(CPUSecondsTotal,
+		Sample{Value: 10, Labels: map[string]string{"mode": "user"}},
+		Sample{Value: 5, Labels: map[string]string{"mode": "system"}},
+		Sample{Value: 100, Labels: map[string]string{"mode": "idle"}},
+	)
+
+	require.Equal(t, float64(115), m.Sum(CPUSecondsTotal, nil))
+	require.Equal(t, float64(15), m.Sum(CPUSecondsTotal, func(labels map[string]string) bool { return labels["mode"] != "idle" }))
+	require.Equal(t, float64(0), m.Sum(MemoryRSSBytes, nil))
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]float64{0.1, 1})
+	h.Observe(map[string]string{"method": "GET"}, 0.05)
+	h.Observe(map[string]string{"method": "GET"}, 0.5)
+	h.Observe(map[string]string{"method": "GET"}, 2)
+
+	m := NewMetricSet(nil)
+	m.AddSamples(APIRequestDurationSeconds, h.Samples()...)
+
+	require.Equal(t, `# HELP incus_api_request_duration_seconds The duration of the API requests in seconds.
+# TYPE incus_api_request_duration_seconds histogram
+incus_api_request_duration_seconds_bucket{le="0.1",method="GET"} 1
+incus_api_request_duration_seconds_bucket{le="1",method="GET"} 2
+incus_api_request_duration_seconds_bucket{le="+Inf",method="GET"} 3
+incus_api_request_duration_seconds_sum{method="GET"} 2.55
+incus_api_request_duration_seconds_count{method="GET"} 3
+# EOF
+`, m.String())
+}
+
+func TestCounter(t *testing.T) {
+	c := NewCounter()
+	c.Add(map[string]string{"direction": "sent"}, 10)
+	c.Add(map[string]string{"direction": "received"}, 5)
+	c.Add(map[string]string{"direction": "sent"}, 20)
+
+	require.Equal(t, []Sample{
+		{Labels: map[string]string{"direction": "received"}, Value: 5},
+		{Labels: map[string]string{"direction": "sent"}, Value: 30},
+	}, c.Samples())
+}
@@ -4,6 +4,9 @@ package metrics
 type Sample struct {
 	Labels map[string]string
 	Value  float64
+
+	// Suffix is appended to the metric name (used for the "_bucket", "_sum" and "_count" samples of histograms).
+	Suffix string
 }
 
 // MetricSet represents a set of metrics.
@@ -144,16 +147,35 @@ const (
 	GoOtherSysBytes
 	// GoNextGCBytes represents the number of heap bytes when next garbage collection will take place.
 	GoNextGCBytes
+	// GoGCTotal represents the number of completed garbage collection cycles.
+	GoGCTotal
+	// GoGCPauseSecondsTotal represents the total time spent in garbage collection pauses.
+	GoGCPauseSecondsTotal
+	// APIRequestDurationSeconds represents the duration of the API requests.
+	APIRequestDurationSeconds
+	// DqliteTransactionSeconds represents the duration of the cluster database transactions.
+	DqliteTransactionSeconds
+	// OperationDurationSeconds represents the duration of the completed operations.
+	OperationDurationSeconds
+	// ImageDownloadBytesTotal represents the number of bytes of downloaded images.
+	ImageDownloadBytesTotal
+	// MigrationBytesTotal represents the number of bytes transferred by migrations.
+	MigrationBytesTotal
+	// EventListeners represents the number of connected event listeners.
+	EventListeners
 )
 
 // MetricNames associates a metric type to its name.
 var MetricNames = map[MetricType]string{
+	APIRequestDurationSeconds:   "incus_api_request_duration_seconds",
 	CPUSecondsTotal:             "incus_cpu_seconds_total",
 	CPUs:                        "incus_cpu_effective_total",
 	DiskReadBytesTotal:          "incus_disk_read_bytes_total",
 	DiskReadsCompletedTotal:     "incus_disk_reads_completed_total",
 	DiskWrittenBytesTotal:       "incus_disk_written_bytes_total",
 	DiskWritesCompletedTotal:    "incus_disk_writes_completed_total",
+	DqliteTransactionSeconds:    "incus_dqlite_transaction_duration_seconds",
+	EventListeners:              "incus_event_listeners",
 	FilesystemAvailBytes:        "incus_filesystem_avail_bytes",
 	FilesystemFreeBytes:         "incus_filesystem_free_bytes",
 	FilesystemSizeBytes:         "incus_filesystem_size_bytes",
@@ -162,6 +184,8 @@ var MetricNames = map[MetricType]string{
 	GoBuckHashSysBytes:          "incus_go_buck_hash_sys_bytes",
 	GoFreesTotal:                "incus_go_frees_total",
 	GoGCSysBytes:                "incus_go_gc_sys_bytes",
+	GoGCPauseSecondsTotal:       "incus_go_gc_pause_seconds_total",
+	GoGCTotal:                   "incus_go_gc_total",
 	GoGoroutines:                "incus_go_goroutines",
 	GoHeapAllocBytes:            "incus_go_heap_alloc_bytes",
 	GoHeapIdleBytes:             "incus_go_heap_idle_bytes",
@@ -180,6 +204,7 @@ var MetricNames = map[MetricType]string{
 	GoStackInuseBytes:           "incus_go_stack_inuse_bytes",
 	GoStackSysBytes:             "incus_go_stack_sys_bytes",
 	GoSysBytes:                  "incus_go_sys_bytes",
+	ImageDownloadBytesTotal:     "incus_image_download_bytes_total",
 	MemoryActiveAnonBytes:       "incus_memory_Active_anon_bytes",
 	MemoryActiveFileBytes:       "incus_memory_Active_file_bytes",
 	MemoryActiveBytes:           "incus_memory_Active_bytes",
@@ -200,6 +225,7 @@ var MetricNames = map[MetricType]string{
 	MemoryUnevictableBytes:      "incus_memory_Unevictable_bytes",
 	MemoryWritebackBytes:        "incus_memory_Writeback_bytes",
 	MemoryOOMKillsTotal:         "incus_memory_OOM_kills_total",
+	MigrationBytesTotal:         "incus_migration_bytes_total",
 	NetworkReceiveBytesTotal:    "incus_network_receive_bytes_total",
 	NetworkReceiveDropTotal:     "incus_network_receive_drop_total",
 	NetworkReceiveErrsTotal:     "incus_network_receive_errs_total",
@@ -208,6 +234,7 @@ var MetricNames = map[MetricType]string{
 	NetworkTransmitDropTotal:    "incus_network_transmit_drop_total",
 	NetworkTransmitErrsTotal:    "incus_network_transmit_errs_total",
 	NetworkTransmitPacketsTotal: "incus_network_transmit_packets_total",
+	OperationDurationSeconds:    "incus_operation_duration_seconds",
 	OperationsTotal:             "incus_operations_total",
 	ProcsTotal:                  "incus_procs_total",
 	UptimeSeconds:               "incus_uptime_seconds",
@@ -216,12 +243,15 @@ var MetricNames = map[MetricType]string{
 
 // MetricHeaders represents the metric headers which contain help messages as specified by OpenMetrics.
 var MetricHeaders = map[MetricType]string{
+	APIRequestDurationSeconds:   "# HELP incus_api_request_duration_seconds The duration of the API requests in seconds.",
 	CPUSecondsTotal:             "# HELP incus_cpu_seconds_total The total number of CPU time used in seconds.",
 	CPUs:                        "# HELP incus_cpu_effective_total The total number of effective CPUs.",
 	DiskReadBytesTotal:          "# HELP incus_disk_read_bytes_total The total number of bytes read.",
 	DiskReadsCompletedTotal:     "# HELP incus_disk_reads_completed_total The total number of completed reads.",
 	DiskWrittenBytesTotal:       "# HELP incus_disk_written_bytes_total The total number of bytes written.",
 	DiskWritesCompletedTotal:    "# HELP incus_disk_writes_completed_total The total number of completed writes.",
+	DqliteTransactionSeconds:    "# HELP incus_dqlite_transaction_duration_seconds The duration of the cluster database transactions in seconds.",
+	EventListeners:              "# HELP incus_event_listeners The number of connected event listeners.",
 	FilesystemAvailBytes:        "# HELP incus_filesystem_avail_bytes The number of available space in bytes.",
 	FilesystemFreeBytes:         "# HELP incus_filesystem_free_bytes The number of free space in bytes.",
 	FilesystemSizeBytes:         "# HELP incus_filesystem_size_bytes The size of the filesystem in bytes.",
@@ -230,6 +260,8 @@ var MetricHeaders = map[MetricType]string{
 	GoBuckHashSysBytes:          "# HELP incus_go_buck_hash_sys_bytes Number of bytes used by the profiling bucket hash table.",
 	GoFreesTotal:                "# HELP incus_go_frees_total Total number of frees.",
 	GoGCSysBytes:                "# HELP incus_go_gc_sys_bytes Number of bytes used for garbage collection system metadata.",
+	GoGCPauseSecondsTotal:       "# HELP incus_go_gc_pause_seconds_total Total time spent in garbage collection pauses in seconds.",
+	GoGCTotal:                   "# HELP incus_go_gc_total Number of completed garbage collection cycles.",
 	GoGoroutines:                "# HELP incus_go_goroutines Number of goroutines that currently exist.",
 	GoHeapAllocBytes:            "# HELP incus_go_heap_alloc_bytes Number of heap bytes allocated and still in use.",
 	GoHeapIdleBytes:             "# HELP incus_go_heap_idle_bytes Number of heap bytes waiting to be used.",
@@ -248,6 +280,7 @@ var MetricHeaders = map[MetricType]string{
 	GoStackInuseBytes:           "# HELP incus_go_stack_inuse_bytes Number of bytes in use by the stack allocator.",
 	GoStackSysBytes:             "# HELP incus_go_stack_sys_bytes Number of bytes obtained from system for stack allocator.",
 	GoSysBytes:                  "# HELP incus_go_sys_bytes Number of bytes obtained from system.",
+	ImageDownloadBytesTotal:     "# HELP incus_image_download_bytes_total The total number of bytes of downloaded images.",
 	MemoryActiveAnonBytes:       "# HELP incus_memory_Active_anon_bytes The amount of anonymous memory on active LRU list.",
 	MemoryActiveFileBytes:       "# HELP incus_memory_Active_file_bytes The amount of file-backed memory on active LRU list.",
 	MemoryActiveBytes:           "# HELP incus_memory_Active_bytes The amount of memory on active LRU list.",
@@ -268,6 +301,7 @@ var MetricHeaders = map[MetricType]string{
 	MemoryUnevictableBytes:      "# HELP incus_memory_Unevictable_bytes The amount of unevictable memory.",
 	MemoryWritebackBytes:        "# HELP incus_memory_Writeback_bytes The amount of memory queued for syncing to disk.",
 	MemoryOOMKillsTotal:         "# HELP incus_memory_OOM_kills_total The number of out of memory kills.",
+	MigrationBytesTotal:         "# HELP incus_migration_bytes_total The total number of bytes transferred by migrations.",
 	NetworkReceiveBytesTotal:    "# HELP incus_network_receive_bytes_total The amount of received bytes on a given interface.",
 	NetworkReceiveDropTotal:     "# HELP incus_network_receive_drop_total The amount of received dropped bytes on a given interface.",
 	NetworkReceiveErrsTotal:     "# HELP incus_network_receive_errs_total The amount of received errors on a given interface.",
@@ -276,6 +310,7 @@ var MetricHeaders = map[MetricType]string{
 	NetworkTransmitDropTotal:    "# HELP incus_network_transmit_drop_total The amount of transmitted dropped bytes on a given interface.",
 	NetworkTransmitErrsTotal:    "# HELP incus_network_transmit_errs_total The amount of transmitted errors on a given interface.",
 	NetworkTransmitPacketsTotal: "# HELP incus_network_transmit_packets_total The amount of transmitted packets on a given interface.",
+	OperationDurationSeconds:    "# HELP incus_operation_duration_seconds The duration of the completed operations in seconds.",
 	OperationsTotal:             "# HELP incus_operations_total The number of running operations",
 	ProcsTotal:                  "# HELP incus_procs_total The number of running processes.",
 	UptimeSeconds:               "# HELP incus_uptime_seconds The daemon uptime in seconds.",
@@ -12,9 +12,11 @@ import (
 	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/events"
+	"github.com/lxc/incus/v6/internal/server/metrics"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/tracing"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/cancel"
@@ -109,6 +111,10 @@ type Operation struct {
 	requestor   *api.EventLifecycleRequestor
 	logger      logger.Logger
 
+	// Tracing of the operation, as a child of the request which created it.
+	traceCtx context.Context
+	span     *tracing.Span
+
 	// Those functions are called at various points in the Operation lifecycle
 	onRun     func(*Operation) error
 	onCancel  func(*Operation) error
@@ -187,6 +193,20 @@ func OperationCreate(s *state.State, projectName string, opClass OperationClass,
 		op.SetRequestor(r)
 	}
 
+	// Trace the operation, token operations don't run anything.
+	op.traceCtx = context.Background()
+	if r != nil {
+		op.traceCtx = tracing.Detach(r.Context())
+		tracing.SpanFromContext(r.Context()).SetAttribute("incus.operation.id", op.id)
+	}
+
+	if op.class != OperationClassToken {
+		op.traceCtx, op.span = tracing.Start(op.traceCtx, op.description, tracing.KindInternal)
+		op.span.SetAttribute("incus.operation.id", op.id)
+		op.span.SetAttribute("incus.operation.class", op.class.String())
+		op.span.SetAttribute("incus.project", op.projectName)
+	}
+
 	operationsLock.Lock()
 	operations[op.id] = &op
 	operationsLock.Unlock()
@@ -237,6 +257,9 @@ func (op *Operation) done() {
 	op.onCancel = nil
 	op.onConnect = nil
 	op.finished.Cancel()
+	op.span.SetAttribute("incus.operation.status", op.status.String())
+	op.span.End(op.err)
+	metrics.Operations.Observe(map[string]string{"type": op.dbOpType.Description(), "status": op.status.String()}, time.Since(op.createdAt).Seconds())
 	op.lock.Unlock()
 
 	go func() {
@@ -287,7 +310,9 @@ func (op *Operation) Start() error {
 
 	if op.onRun != nil {
 		go func(op *Operation) {
+			_, span := tracing.Start(op.traceCtx, "run", tracing.KindInternal)
 			err := op.onRun(op)
+			span.End(err)
 			if err != nil {
 				op.lock.Lock()
 				op.status = api.Failure
@@ -433,7 +458,9 @@ func (op *Operation) Connect(r *http.Request, w http.ResponseWriter) (chan error
 	chanConnect := make(chan error, 1)
 
 	go func(op *Operation, chanConnect chan error) {
+		_, span := tracing.Start(op.traceCtx, "connect", tracing.KindInternal)
 		err := op.onConnect(op, r, w)
+		span.End(err)
 		if err != nil {
 			chanConnect <- err
 
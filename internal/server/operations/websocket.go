@@ -3,9 +3,12 @@ package operations
 import (
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 
 	"github.com/gorilla/websocket"
 
+	"github.com/lxc/incus/v6/internal/asciicast"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/shared/ws"
 )
@@ -80,3 +83,25 @@ func (r *forwardedOperationWebSocket) String() string {
 func (r *forwardedOperationWebSocket) Code() int {
 	return http.StatusOK
 }
+
+// NewSessionRecorder returns a recorder for the terminal session mirrored by the operation.
+// The recording is stored as session_<operation>.cast in dir and the terminal output relayed through the
+// returned writer (but not the input) is recorded to it until it's closed.
+func NewSessionRecorder(op *Operation, dir string, header asciicast.Header) (*asciicast.Writer, error) {
+	if header.Env == nil && op.requestor != nil && op.requestor.Username != "" {
+		header.Env = map[string]string{"USER": op.requestor.Username}
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, fmt.Sprintf("session_%s.cast", op.ID())), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating session recording: %w", err)
+	}
+
+	w, err := asciicast.NewWriter(f, header)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("Failed writing session recording header: %w", err)
+	}
+
+	return w, nil
+}
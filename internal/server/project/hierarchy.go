@@ -0,0 +1,237 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// CheckProjectParent checks that the parent project exists and that making it the parent of the
+// project wouldn't create a loop.
+func CheckProjectParent(ctx context.Context, tx *db.ClusterTx, projectName string, parent string) error {
+	if parent == "" {
+		return nil
+	}
+
+	if parent == projectName {
+		return api.StatusErrorf(http.StatusBadRequest, "Project %q can't be its own parent", projectName)
+	}
+
+	exists, err := cluster.ProjectExists(ctx, tx.Tx(), parent)
+	if err != nil {
+		return fmt.Errorf("Failed checking if project %q exists: %w", parent, err)
+	}
+
+	if !exists {
+		return api.StatusErrorf(http.StatusBadRequest, "Parent project %q doesn't exist", parent)
+	}
+
+	parents, err := cluster.GetProjectParents(ctx, tx.Tx())
+	if err != nil {
+		return err
+	}
+
+	parents[projectName] = parent
+
+	for _, ancestor := range projectAncestors(parents, parent) {
+		if ancestor == projectName {
+			return api.StatusErrorf(http.StatusBadRequest, "Project %q can't be a parent of its own parent %q", projectName, parent)
+		}
+	}
+
+	return nil
+}
+
+// CheckProjectParentPermission checks that the user can edit both the current and the new parent of a
+// project when changing it, as the limits of a parent apply to its descendants.
+func CheckProjectParentPermission(authorizer auth.Authorizer, r *http.Request, oldParent string, newParent string) error {
+	if oldParent == newParent {
+		return nil
+	}
+
+	for _, parent := range []string{oldParent, newParent} {
+		if parent == "" {
+			continue
+		}
+
+		err := authorizer.CheckPermission(r.Context(), r, auth.ObjectProject(parent), auth.EntitlementCanEdit)
+		if err != nil && api.StatusErrorCheck(err, http.StatusForbidden) {
+			return api.StatusErrorf(http.StatusForbidden, "Changing the parent of a project requires editing both the current and the new parent project")
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetProjectChildren returns the sorted names of the projects whose parent is the given project.
+func GetProjectChildren(ctx context.Context, tx *db.ClusterTx, projectName string) ([]string, error) {
+	parents, err := cluster.GetProjectParents(ctx, tx.Tx())
+	if err != nil {
+		return nil, err
+	}
+
+	children := []string{}
+	for name, parent := range parents {
+		if parent == projectName {
+			children = append(children, name)
+		}
+	}
+
+	sort.Strings(children)
+
+	return children, nil
+}
+
+// projectAncestors returns the names of the given project and of its ancestors, closest first.
+// Walking up stops if a loop is found.
+func projectAncestors(parents map[string]string, projectName string) []string {
+	ancestors := []string{}
+	seen := map[string]bool{}
+
+	for name := projectName; name != "" && !seen[name]; name = parents[name] {
+		seen[name] = true
+		ancestors = append(ancestors, name)
+	}
+
+	return ancestors
+}
+
+// projectDescendants returns the names of all the descendants of the given project.
+func projectDescendants(parents map[string]string, projectName string) []string {
+	descendants := []string{}
+
+	for name := range parents {
+		if name == projectName {
+			continue
+		}
+
+		ancestors := projectAncestors(parents, name)
+		for _, ancestor := range ancestors[1:] {
+			if ancestor == projectName {
+				descendants = append(descendants, name)
+				break
+			}
+		}
+	}
+
+	sort.Strings(descendants)
+
+	return descendants
+}
+
+// projectHasLimits returns true if the project has some limits set.
+func projectHasLimits(project api.Project) bool {
+	for k := range project.Config {
+		if strings.HasPrefix(k, "limits.") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fetchProjectTree returns the combined entities of the given project and of all of its descendants,
+// with the configuration and devices of the instances expanded.
+// The entities of the project described by override are taken from it rather than from the database.
+func fetchProjectTree(tx *db.ClusterTx, projectName string, parents map[string]string, override *projectInfo) (*projectInfo, error) {
+	var tree *projectInfo
+
+	for _, name := range append([]string{projectName}, projectDescendants(parents, projectName)...) {
+		member := override
+		if name != override.Project.Name {
+			var err error
+
+			member, err = fetchProject(tx, name, false)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		instances, err := expandInstancesConfigAndDevices(member.Instances, member.Profiles)
+		if err != nil {
+			return nil, err
+		}
+
+		if tree == nil {
+			tree = &projectInfo{Project: member.Project}
+		}
+
+		tree.Instances = append(tree.Instances, instances...)
+		tree.Volumes = append(tree.Volumes, member.Volumes...)
+	}
+
+	return tree, nil
+}
+
+// fetchAncestorTrees returns the combined entities of each ancestor of the project that has limits set,
+// as returned by fetchProjectTree. The project's own entities and parent are taken from info.
+func fetchAncestorTrees(tx *db.ClusterTx, info *projectInfo) ([]*projectInfo, error) {
+	parent := info.Project.Config["parent"]
+	if parent == "" {
+		return nil, nil
+	}
+
+	parents, err := cluster.GetProjectParents(context.Background(), tx.Tx())
+	if err != nil {
+		return nil, err
+	}
+
+	parents[info.Project.Name] = parent
+
+	trees := []*projectInfo{}
+	for _, ancestor := range projectAncestors(parents, parent) {
+		if ancestor == info.Project.Name {
+			break
+		}
+
+		dbProject, err := cluster.GetProject(context.Background(), tx.Tx(), ancestor)
+		if err != nil {
+			return nil, fmt.Errorf("Fetch parent project %q: %w", ancestor, err)
+		}
+
+		config, err := cluster.GetProjectConfig(context.Background(), tx.Tx(), dbProject.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !projectHasLimits(api.Project{ProjectPut: api.ProjectPut{Config: config}}) {
+			continue
+		}
+
+		tree, err := fetchProjectTree(tx, ancestor, parents, info)
+		if err != nil {
+			return nil, err
+		}
+
+		trees = append(trees, tree)
+	}
+
+	return trees, nil
+}
+
+// checkAncestorsAggregateLimits checks that the aggregate limits of the ancestors of the project
+// hold for their whole tree, including the changes to the project in info.
+func checkAncestorsAggregateLimits(tx *db.ClusterTx, info *projectInfo) error {
+	trees, err := fetchAncestorTrees(tx, info)
+	if err != nil {
+		return err
+	}
+
+	for _, tree := range trees {
+		err = checkAggregateLimits(tree, projectAggregateKeys(tree.Project.Config))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
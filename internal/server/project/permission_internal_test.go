@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/idmap"
 )
 
@@ -75,3 +76,57 @@ func TestParseHostIDMapRange(t *testing.T) {
 		assert.Equal(t, idmaps, expected)
 	}
 }
+
+func TestGetInstanceDevicesLimit(t *testing.T) {
+	inst := api.Instance{
+		Name:    "c1",
+		Project: "p1",
+		Devices: map[string]map[string]string{
+			"eth0":   {"type": "nic", "limits.egress": "100Mbit", "limits.ingress": "1Gbit"},
+			"eth1":   {"type": "nic", "limits.max": "10Mbit"},
+			"root":   {"type": "disk", "path": "/", "pool": "default", "limits.read": "100iops", "limits.write": "50iops"},
+			"data":   {"type": "disk", "path": "/data", "source": "vol", "limits.max": "10iops"},
+			"config": {"type": "disk", "source": "cloud-init:config"},
+		},
+	}
+
+	limit, err := getInstanceDevicesLimit(inst, "limits.network.egress", false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(110_000_000), limit)
+
+	limit, err = getInstanceDevicesLimit(inst, "limits.network.ingress", false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1_010_000_000), limit)
+
+	limit, err = getInstanceDevicesLimit(inst, "limits.disk.iops", false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(170), limit)
+
+	// Devices without a limit are rejected unless they're skipped.
+	inst.Devices["data"]["limits.max"] = "10MB"
+	_, err = getInstanceDevicesLimit(inst, "limits.disk.iops", false)
+	assert.Error(t, err)
+
+	limit, err = getInstanceDevicesLimit(inst, "limits.disk.iops", true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150), limit)
+}
+
+func TestProjectAncestorsAndDescendants(t *testing.T) {
+	parents := map[string]string{
+		"team":  "org",
+		"dev":   "team",
+		"prod":  "team",
+		"other": "org",
+		"loop1": "loop2",
+		"loop2": "loop1",
+	}
+
+	assert.Equal(t, []string{"dev", "team", "org"}, projectAncestors(parents, "dev"))
+	assert.Equal(t, []string{"org"}, projectAncestors(parents, "org"))
+	assert.Equal(t, []string{"loop1", "loop2"}, projectAncestors(parents, "loop1"))
+
+	assert.Equal(t, []string{"dev", "other", "prod", "team"}, projectDescendants(parents, "org"))
+	assert.Equal(t, []string{"dev", "prod"}, projectDescendants(parents, "team"))
+	assert.Equal(t, []string{}, projectDescendants(parents, "dev"))
+}
@@ -84,6 +84,24 @@ func AllowInstanceCreation(tx *db.ClusterTx, projectName string, req api.Instanc
 		return err
 	}
 
+	// Instance count limits of the ancestors apply to their whole tree.
+	trees, err := fetchAncestorTrees(tx, info)
+	if err != nil {
+		return err
+	}
+
+	for _, tree := range trees {
+		err = checkInstanceCountLimit(tree, instanceType)
+		if err != nil {
+			return err
+		}
+
+		err = checkTotalInstanceCountLimit(tree)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Add the instance being created.
 	info.Instances = append(info.Instances, api.Instance{
 		Name:        req.Name,
@@ -263,8 +281,8 @@ func AllowVolumeCreation(tx *db.ClusterTx, projectName string, poolName string,
 		return nil
 	}
 
-	// If "limits.disk" is not set, there's nothing to do.
-	if info.Project.Config["limits.disk"] == "" {
+	// If "limits.disk" is not set and the project has no parent, there's nothing to do.
+	if info.Project.Config["limits.disk"] == "" && info.Project.Config["parent"] == "" {
 		return nil
 	}
 
@@ -337,22 +355,11 @@ func GetImageSpaceBudget(tx *db.ClusterTx, projectName string) (int64, error) {
 func checkRestrictionsAndAggregateLimits(tx *db.ClusterTx, info *projectInfo) error {
 	// List of config keys for which we need to check aggregate values
 	// across all project instances.
-	aggregateKeys := []string{}
-	isRestricted := false
+	aggregateKeys := projectAggregateKeys(info.Project.Config)
+	isRestricted := util.IsTrue(info.Project.Config["restricted"])
+	hasParent := info.Project.Config["parent"] != ""
 
-	for key, value := range info.Project.Config {
-		if slices.Contains(allAggregateLimits, key) || strings.HasPrefix(key, projectLimitDiskPool) {
-			aggregateKeys = append(aggregateKeys, key)
-			continue
-		}
-
-		if key == "restricted" && util.IsTrue(value) {
-			isRestricted = true
-			continue
-		}
-	}
-
-	if len(aggregateKeys) == 0 && !isRestricted {
+	if len(aggregateKeys) == 0 && !isRestricted && !hasParent {
 		return nil
 	}
 
@@ -375,9 +382,29 @@ func checkRestrictionsAndAggregateLimits(tx *db.ClusterTx, info *projectInfo) er
 		}
 	}
 
+	if hasParent {
+		err = checkAncestorsAggregateLimits(tx, info)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// projectAggregateKeys returns the config keys of the aggregate limits set in the project config.
+func projectAggregateKeys(config map[string]string) []string {
+	aggregateKeys := []string{}
+
+	for key := range config {
+		if slices.Contains(allAggregateLimits, key) || strings.HasPrefix(key, projectLimitDiskPool) {
+			aggregateKeys = append(aggregateKeys, key)
+		}
+	}
+
+	return aggregateKeys
+}
+
 func getAggregateLimits(info *projectInfo, aggregateKeys []string) (map[string]api.ProjectStateResource, error) {
 	result := map[string]api.ProjectStateResource{}
 
@@ -477,6 +504,7 @@ func parseHostIDMapRange(isUID bool, isGID bool, listValue string) ([]idmap.Entr
 // instances and profiles.
 func checkRestrictions(project api.Project, instances []api.Instance, profiles []api.Profile) error {
 	containerConfigChecks := map[string]func(value string) error{}
+	instanceConfigChecks := map[string]func(value string) error{}
 	devicesChecks := map[string]func(value map[string]string) error{}
 
 	allowContainerLowLevel := false
@@ -515,6 +543,18 @@ func checkRestrictions(project api.Project, instances []api.Instance, profiles [
 				return nil
 			}
 
+		case "restricted.exec":
+			// These keys run commands in the instance on behalf of the user.
+			for _, key := range []string{"snapshots.hooks.pre", "snapshots.hooks.post", "boot.shutdown_command"} {
+				instanceConfigChecks[key] = func(instanceValue string) error {
+					if restrictionValue == "block" && instanceValue != "" {
+						return fmt.Errorf("Running commands in instances is forbidden")
+					}
+
+					return nil
+				}
+			}
+
 		case "restricted.containers.lowlevel":
 			if restrictionValue == "allow" {
 				allowContainerLowLevel = true
@@ -731,8 +771,8 @@ func checkRestrictions(project api.Project, instances []api.Instance, profiles [
 				return fmt.Errorf("Use of low-level config %q on %s %q of project %q is forbidden", key, entityTypeLabel, entityName, project.Name)
 			}
 
-			var checker func(value string) error
-			if isContainerOrProfile {
+			checker := instanceConfigChecks[key]
+			if checker == nil && isContainerOrProfile {
 				checker = containerConfigChecks[key]
 			}
 
@@ -828,7 +868,10 @@ func CheckRestrictedDevicesDiskPaths(projectConfig map[string]string, sourcePath
 var allAggregateLimits = []string{
 	"limits.cpu",
 	"limits.disk",
+	"limits.disk.iops",
 	"limits.memory",
+	"limits.network.egress",
+	"limits.network.ingress",
 	"limits.processes",
 }
 
@@ -853,6 +896,10 @@ var allRestrictions = map[string]string{
 	"restricted.devices.nic":               "managed",
 	"restricted.devices.disk":              "managed",
 	"restricted.devices.disk.paths":        "",
+	"restricted.exec":                      "allow",
+	"restricted.exec.record":               "false",
+	"restricted.console":                   "allow",
+	"restricted.files":                     "allow",
 	"restricted.idmap.uid":                 "",
 	"restricted.idmap.gid":                 "",
 	"restricted.networks.access":           "",
@@ -966,8 +1013,8 @@ func AllowVolumeUpdate(tx *db.ClusterTx, projectName, volumeName string, req api
 		return nil
 	}
 
-	// If "limits.disk" is not set, there's nothing to do.
-	if info.Project.Config["limits.disk"] == "" {
+	// If "limits.disk" is not set and the project has no parent, there's nothing to do.
+	if info.Project.Config["limits.disk"] == "" && info.Project.Config["parent"] == "" {
 		return nil
 	}
 
@@ -1019,6 +1066,10 @@ func AllowProfileUpdate(tx *db.ClusterTx, projectName, profileName string, req a
 }
 
 // AllowProjectUpdate checks the new config to be set on a project is valid.
+//
+// Limits are checked against the project and all of its descendants, and
+// changing the parent of the project must not exceed the limits of its new
+// ancestors.
 func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]string, changed []string) error {
 	info, err := fetchProject(tx, projectName, false)
 	if err != nil {
@@ -1030,9 +1081,8 @@ func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]
 		return err
 	}
 
-	// List of keys that need to check aggregate values across all project
-	// instances.
-	aggregateKeys := []string{}
+	// List of limits keys that need to be checked.
+	limitKeys := []string{}
 
 	for _, key := range changed {
 		if strings.HasPrefix(key, "restricted.") {
@@ -1051,6 +1101,72 @@ func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]
 			continue
 		}
 
+		if strings.HasPrefix(key, "limits.") {
+			limitKeys = append(limitKeys, key)
+		}
+	}
+
+	if slices.Contains(changed, "parent") {
+		err = CheckProjectParent(context.Background(), tx, projectName, config["parent"])
+		if err != nil {
+			return err
+		}
+	}
+
+	parents, err := cluster.GetProjectParents(context.Background(), tx.Tx())
+	if err != nil {
+		return err
+	}
+
+	parents[projectName] = config["parent"]
+	info.Project.Config = config
+
+	if len(limitKeys) > 0 {
+		tree, err := fetchProjectTree(tx, projectName, parents, info)
+		if err != nil {
+			return err
+		}
+
+		err = validateLimits(tree, config, limitKeys)
+		if err != nil {
+			return err
+		}
+	}
+
+	if slices.Contains(changed, "parent") {
+		trees, err := fetchAncestorTrees(tx, info)
+		if err != nil {
+			return err
+		}
+
+		for _, tree := range trees {
+			keys := []string{}
+			for key := range tree.Project.Config {
+				if strings.HasPrefix(key, "limits.") {
+					keys = append(keys, key)
+				}
+			}
+
+			err = validateLimits(tree, tree.Project.Config, keys)
+			if err != nil {
+				return fmt.Errorf("Can't set parent of project %q: %w", projectName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateLimits checks that the given limits keys in config are above the current usage of the
+// (already expanded) project entities.
+func validateLimits(info *projectInfo, config map[string]string, keys []string) error {
+	projectName := info.Project.Name
+
+	// List of keys that need to check aggregate values across all project
+	// instances.
+	aggregateKeys := []string{}
+
+	for _, key := range keys {
 		switch key {
 		case "limits.instances":
 			err := validateTotalInstanceCountLimit(info.Instances, config[key], projectName)
@@ -1073,6 +1189,12 @@ func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]
 		case "limits.memory":
 			fallthrough
 		case "limits.disk":
+			fallthrough
+		case "limits.disk.iops":
+			fallthrough
+		case "limits.network.egress":
+			fallthrough
+		case "limits.network.ingress":
 			aggregateKeys = append(aggregateKeys, key)
 		}
 	}
@@ -1198,6 +1320,11 @@ func projectHasLimitsOrRestrictions(project api.Project) bool {
 		if k == "restricted" && util.IsTrue(v) {
 			return true
 		}
+
+		// The limits of the ancestors apply to the project.
+		if k == "parent" && v != "" {
+			return true
+		}
 	}
 
 	return false
@@ -1440,6 +1567,11 @@ func getInstanceLimits(inst api.Instance, keys []string, skipUnset bool) (map[st
 
 				limit += sizeStateLimit
 			}
+		} else if key == "limits.disk.iops" || key == "limits.network.egress" || key == "limits.network.ingress" {
+			limit, err = getInstanceDevicesLimit(inst, key, skipUnset)
+			if err != nil {
+				return nil, err
+			}
 		} else {
 			value, ok := inst.Config[key]
 			if !ok || value == "" {
@@ -1466,6 +1598,80 @@ func getInstanceLimits(inst api.Instance, keys []string, skipUnset bool) (map[st
 	return limits, nil
 }
 
+// Return the sum of the device-level limits matching the given project limit across the devices of the instance.
+// Network limits sum the ingress or egress limit of all NICs, while the IOPS limit sums the read and write
+// IOPS limits of all disks.
+func getInstanceDevicesLimit(inst api.Instance, key string, skipUnset bool) (int64, error) {
+	var total int64
+
+	for devName, device := range inst.Devices {
+		switch key {
+		case "limits.network.egress", "limits.network.ingress":
+			if device["type"] != "nic" {
+				continue
+			}
+
+			deviceKey := strings.Replace(key, "limits.network.", "limits.", 1)
+			value := device[deviceKey]
+			if device["limits.max"] != "" {
+				value = device["limits.max"]
+			}
+
+			if value == "" {
+				if skipUnset {
+					continue
+				}
+
+				return -1, fmt.Errorf("Instance %q in project %q has no %q set on NIC device %q, either directly or via a profile", inst.Name, inst.Project, deviceKey, devName)
+			}
+
+			limit, err := units.ParseBitSizeString(value)
+			if err != nil {
+				if skipUnset {
+					continue
+				}
+
+				return -1, fmt.Errorf("Failed parsing %q of NIC device %q for instance %q in project %q", deviceKey, devName, inst.Name, inst.Project)
+			}
+
+			total += limit
+		case "limits.disk.iops":
+			// Configuration drives don't perform block I/O.
+			if device["type"] != "disk" || device["source"] == "cloud-init:config" || device["source"] == "agent:config" {
+				continue
+			}
+
+			for _, deviceKey := range []string{"limits.read", "limits.write"} {
+				value := device[deviceKey]
+				if device["limits.max"] != "" {
+					value = device["limits.max"]
+				}
+
+				if !strings.HasSuffix(value, "iops") {
+					if skipUnset {
+						continue
+					}
+
+					return -1, fmt.Errorf("Instance %q in project %q has no IOPS %q limit set on disk device %q, either directly or via a profile", inst.Name, inst.Project, deviceKey, devName)
+				}
+
+				limit, err := strconv.ParseInt(strings.TrimSuffix(value, "iops"), 10, 64)
+				if err != nil {
+					if skipUnset {
+						continue
+					}
+
+					return -1, fmt.Errorf("Failed parsing %q of disk device %q for instance %q in project %q", deviceKey, devName, inst.Name, inst.Project)
+				}
+
+				total += limit
+			}
+		}
+	}
+
+	return total, nil
+}
+
 var aggregateLimitConfigValueParsers = map[string]func(string) (int64, error){
 	"limits.memory": func(value string) (int64, error) {
 		if strings.HasSuffix(value, "%") {
@@ -1497,6 +1703,15 @@ var aggregateLimitConfigValueParsers = map[string]func(string) (int64, error){
 	"limits.disk": func(value string) (int64, error) {
 		return units.ParseByteSizeString(value)
 	},
+	"limits.disk.iops": func(value string) (int64, error) {
+		return strconv.ParseInt(value, 10, 64)
+	},
+	"limits.network.egress": func(value string) (int64, error) {
+		return units.ParseBitSizeString(value)
+	},
+	"limits.network.ingress": func(value string) (int64, error) {
+		return units.ParseBitSizeString(value)
+	},
 }
 
 var aggregateLimitConfigValuePrinters = map[string]func(int64) string{
@@ -1512,6 +1727,15 @@ var aggregateLimitConfigValuePrinters = map[string]func(int64) string{
 	"limits.disk": func(limit int64) string {
 		return units.GetByteSizeStringIEC(limit, 1)
 	},
+	"limits.disk.iops": func(limit int64) string {
+		return fmt.Sprintf("%d", limit)
+	},
+	"limits.network.egress": func(limit int64) string {
+		return units.GetBitSizeString(limit, 1)
+	},
+	"limits.network.ingress": func(limit int64) string {
+		return units.GetBitSizeString(limit, 1)
+	},
 }
 
 // FilterUsedBy filters a UsedBy list based on project access.
@@ -1588,6 +1812,45 @@ func CheckClusterTargetRestriction(authorizer auth.Authorizer, r *http.Request,
 	return nil
 }
 
+// instanceAccessDescriptions describes the kind of instance access controlled by each restriction.
+var instanceAccessDescriptions = map[string]string{
+	"restricted.exec":    "running commands in instances",
+	"restricted.console": "accessing instance consoles",
+	"restricted.files":   "accessing instance files",
+}
+
+// CheckInstanceAccessRestriction checks if the user is allowed the interactive instance access controlled by
+// the given restriction key ("restricted.exec", "restricted.console" or "restricted.files").
+// The write argument indicates whether the access modifies the instance, which a "read-only" restriction forbids.
+// Server administrators aren't subject to those restrictions.
+func CheckInstanceAccessRestriction(authorizer auth.Authorizer, r *http.Request, project *api.Project, restrictionKey string, write bool) error {
+	if util.IsFalseOrEmpty(project.Config["restricted"]) {
+		return nil
+	}
+
+	restrictionValue, ok := project.Config[restrictionKey]
+	if !ok {
+		restrictionValue = allRestrictions[restrictionKey]
+	}
+
+	if restrictionValue == "allow" || (restrictionValue == "read-only" && !write) {
+		return nil
+	}
+
+	err := authorizer.CheckPermission(r.Context(), r, auth.ObjectServer(), auth.EntitlementCanEdit)
+	if err != nil && api.StatusErrorCheck(err, http.StatusForbidden) {
+		if restrictionValue == "read-only" {
+			return api.StatusErrorf(http.StatusForbidden, "Project %q only allows read-only access to instance files", project.Name)
+		}
+
+		return api.StatusErrorf(http.StatusForbidden, "Project %q doesn't allow %s", project.Name, instanceAccessDescriptions[restrictionKey])
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // AllowBackupCreation returns an error if any project-specific restriction is violated
 // when creating a new backup in a project.
 func AllowBackupCreation(tx *db.ClusterTx, projectName string) error {
@@ -1619,6 +1882,16 @@ func AllowSnapshotCreation(p *api.Project) error {
 	return nil
 }
 
+// RecordSessions returns whether the interactive exec and console sessions of instances in the project
+// must be recorded.
+func RecordSessions(p *api.Project) bool {
+	if util.IsFalseOrEmpty(p.Config["restricted"]) {
+		return false
+	}
+
+	return util.IsTrue(p.Config["restricted.exec.record"])
+}
+
 // GetRestrictedClusterGroups returns a slice of restricted cluster groups for the given project.
 func GetRestrictedClusterGroups(p *api.Project) []string {
 	return util.SplitNTrimSpace(p.Config["restricted.cluster.groups"], ",", -1, true)
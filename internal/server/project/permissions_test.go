@@ -157,6 +157,34 @@ func TestAllowInstanceCreation_AboveInstances(t *testing.T) {
 	assert.EqualError(t, err, `Reached maximum number of instances in project "p1"`)
 }
 
+// If exec is blocked, instances can't run commands through snapshot hooks.
+func TestAllowInstanceCreation_ExecBlocked(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	id, err := cluster.CreateProject(ctx, tx.Tx(), cluster.Project{Name: "p1"})
+	require.NoError(t, err)
+
+	err = cluster.CreateProjectConfig(ctx, tx.Tx(), id, map[string]string{"restricted": "true", "restricted.exec": "block"})
+	require.NoError(t, err)
+
+	req := api.InstancesPost{
+		Name: "c1",
+		Type: api.InstanceTypeContainer,
+		InstancePut: api.InstancePut{
+			Config: map[string]string{"snapshots.hooks.pre": "/bin/true"},
+		},
+	}
+
+	err = project.AllowInstanceCreation(tx, "p1", req)
+	assert.EqualError(t, err, `Invalid value "/bin/true" for config "snapshots.hooks.pre" on container "c1" of project "p1": Running commands in instances is forbidden`)
+
+	req.Config = nil
+	err = project.AllowInstanceCreation(tx, "p1", req)
+	assert.NoError(t, err)
+}
+
 // If a direct targeting is blocked, the check fails.
 func TestCheckClusterTargetRestriction_RestrictedTrue(t *testing.T) {
 	tx, cleanup := db.NewTestClusterTx(t)
@@ -249,3 +277,63 @@ func TestCheckClusterTargetRestriction_RestrictedFalse(t *testing.T) {
 	err = project.CheckClusterTargetRestriction(authorizer, req, p, "n1")
 	assert.NoError(t, err)
 }
+
+// If exec is blocked, the check fails for non-admin users.
+func TestCheckInstanceAccessRestriction_Blocked(t *testing.T) {
+	p := &api.Project{Name: "p1", ProjectPut: api.ProjectPut{Config: map[string]string{"restricted": "true", "restricted.exec": "block"}}}
+
+	req := &http.Request{}
+	authorizer, err := auth.LoadAuthorizer(context.Background(), auth.DriverTLS, logger.Log, &certificate.Cache{})
+	require.NoError(t, err)
+
+	err = project.CheckInstanceAccessRestriction(authorizer, req, p, "restricted.exec", true)
+	assert.EqualError(t, err, `Project "p1" doesn't allow running commands in instances`)
+
+	err = project.CheckInstanceAccessRestriction(authorizer, req, p, "restricted.console", true)
+	assert.NoError(t, err)
+}
+
+// If file access is read-only, only reads are allowed.
+func TestCheckInstanceAccessRestriction_ReadOnly(t *testing.T) {
+	p := &api.Project{Name: "p1", ProjectPut: api.ProjectPut{Config: map[string]string{"restricted": "true", "restricted.files": "read-only"}}}
+
+	req := &http.Request{}
+	authorizer, err := auth.LoadAuthorizer(context.Background(), auth.DriverTLS, logger.Log, &certificate.Cache{})
+	require.NoError(t, err)
+
+	err = project.CheckInstanceAccessRestriction(authorizer, req, p, "restricted.files", false)
+	assert.NoError(t, err)
+
+	err = project.CheckInstanceAccessRestriction(authorizer, req, p, "restricted.files", true)
+	assert.EqualError(t, err, `Project "p1" only allows read-only access to instance files`)
+}
+
+// If the project isn't restricted, the check passes.
+func TestCheckInstanceAccessRestriction_RestrictedFalse(t *testing.T) {
+	p := &api.Project{Name: "p1", ProjectPut: api.ProjectPut{Config: map[string]string{"restricted": "false", "restricted.exec": "block"}}}
+
+	req := &http.Request{}
+	authorizer, err := auth.LoadAuthorizer(context.Background(), auth.DriverTLS, logger.Log, &certificate.Cache{})
+	require.NoError(t, err)
+
+	err = project.CheckInstanceAccessRestriction(authorizer, req, p, "restricted.exec", true)
+	assert.NoError(t, err)
+}
+
+// Changing the parent of a project requires editing both the current and the new parent.
+func TestCheckProjectParentPermission(t *testing.T) {
+	req := &http.Request{}
+	authorizer, err := auth.LoadAuthorizer(context.Background(), auth.DriverTLS, logger.Log, &certificate.Cache{})
+	require.NoError(t, err)
+
+	err = project.CheckProjectParentPermission(authorizer, req, "p1", "p1")
+	assert.NoError(t, err)
+
+	err = project.CheckProjectParentPermission(authorizer, req, "", "")
+	assert.NoError(t, err)
+
+	for _, parents := range [][2]string{{"", "p2"}, {"p1", ""}, {"p1", "p2"}} {
+		err = project.CheckProjectParentPermission(authorizer, req, parents[0], parents[1])
+		assert.True(t, api.StatusErrorCheck(err, http.StatusForbidden), "Expected forbidden error changing parent from %q to %q", parents[0], parents[1])
+	}
+}
@@ -51,6 +51,9 @@ func GetCurrentAllocations(ctx context.Context, tx *db.ClusterTx, projectName st
 	result["memory"] = raw["limits.memory"]
 	result["networks"] = raw["limits.networks"]
 	result["processes"] = raw["limits.processes"]
+	result["disk-iops"] = raw["limits.disk.iops"]
+	result["network-egress"] = raw["limits.network.egress"]
+	result["network-ingress"] = raw["limits.network.ingress"]
 
 	// Add the pool-specific disk limits.
 	for k, v := range raw {
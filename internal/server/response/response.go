@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/tracing"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
@@ -540,16 +541,25 @@ func (r *forwardedResponse) Render(w http.ResponseWriter) error {
 		forwarded.Header.Set(key, r.request.Header.Get(key))
 	}
 
+	ctx, span := tracing.Start(r.request.Context(), "cluster forward", tracing.KindClient)
+	span.SetAttribute("server.address", info.Addresses[0])
+	tracing.Inject(ctx, forwarded.Header)
+
 	httpClient, err := r.client.GetHTTPClient()
 	if err != nil {
+		span.End(err)
 		return err
 	}
 
 	response, err := httpClient.Do(forwarded)
 	if err != nil {
+		span.End(err)
 		return err
 	}
 
+	span.SetAttribute("http.response.status_code", response.StatusCode)
+	span.End(nil)
+
 	for key := range response.Header {
 		w.Header().Set(key, response.Header.Get(key))
 	}
@@ -20,6 +20,7 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v2"
@@ -57,6 +58,10 @@ import (
 	"github.com/lxc/incus/v6/shared/util"
 )
 
+// backupQuiesceTimeout is how long the guest filesystems may stay frozen while taking the snapshot a backup is
+// made from.
+const backupQuiesceTimeout = 5 * time.Minute
+
 var unavailablePools = make(map[string]struct{})
 var unavailablePoolsMu = sync.Mutex{}
 
@@ -2666,6 +2671,35 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 		}
 	}
 
+	// Freeze the guest filesystems of running virtual machines while taking a temporary snapshot and back up
+	// from that snapshot, so the backup is consistent without keeping the guest frozen during the export.
+	if inst.Type() == instancetype.VM && inst.IsRunning() && util.IsTrue(inst.ExpandedConfig()["snapshots.quiesce"]) {
+		vm, ok := inst.(instance.VM)
+		if !ok {
+			return fmt.Errorf("Instance is not a virtual machine")
+		}
+
+		snapVol, err := vol.NewSnapshot(fmt.Sprintf("backup-%s", uuid.New().String()))
+		if err != nil {
+			return err
+		}
+
+		err = b.createQuiescedSnapshot(vm, snapVol, op)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err := b.driver.DeleteVolumeSnapshot(snapVol, op)
+			if err != nil {
+				l.Warn("Failed deleting temporary snapshot for backup", logger.Ctx{"snapshot": snapVol.Name(), "err": err})
+			}
+		}()
+
+		_, snapName, _ := api.GetParentAndSnapshotName(snapVol.Name())
+		vol.SetBackupSnapshot(snapName)
+	}
+
 	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, op)
 	if err != nil {
 		return err
@@ -2674,6 +2708,19 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 	return nil
 }
 
+// createQuiescedSnapshot creates a snapshot of the volume of the virtual machine while its guest filesystems are
+// frozen.
+func (b *backend) createQuiescedSnapshot(vm instance.VM, snapVol drivers.Volume, op *operations.Operation) error {
+	thaw, err := vm.Quiesce(backupQuiesceTimeout)
+	if err != nil {
+		return err
+	}
+
+	defer thaw()
+
+	return b.driver.CreateVolumeSnapshot(snapVol, op)
+}
+
 // GetInstanceUsage returns the disk usage of the instance's root volume.
 func (b *backend) GetInstanceUsage(inst instance.Instance) (*VolumeUsage, error) {
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
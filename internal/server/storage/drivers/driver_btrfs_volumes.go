@@ -1725,6 +1725,28 @@ func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWr
 		lastVolPath = snapVol.MountPath()
 	}
 
+	// Dump the instance to a file.
+	fileNamePrefix := "container"
+	if vol.volType == VolumeTypeVM {
+		if vol.contentType == ContentTypeFS {
+			fileNamePrefix = "virtual-machine-config"
+		} else {
+			fileNamePrefix = "virtual-machine"
+		}
+	} else if vol.volType == VolumeTypeCustom {
+		fileNamePrefix = "volume"
+	}
+
+	// Use the snapshot provided for the backup.
+	if vol.backupSnapshot != "" {
+		snapVol, err := vol.NewSnapshot(vol.backupSnapshot)
+		if err != nil {
+			return err
+		}
+
+		return addVolume(vol, snapVol.MountPath(), lastVolPath, fileNamePrefix)
+	}
+
 	// Make a temporary copy of the instance.
 	sourceVolume := vol.MountPath()
 	instancesPath := GetVolumeMountPath(d.name, vol.volType, "")
@@ -1755,18 +1777,6 @@ func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWr
 		return err
 	}
 
-	// Dump the instance to a file.
-	fileNamePrefix := "container"
-	if vol.volType == VolumeTypeVM {
-		if vol.contentType == ContentTypeFS {
-			fileNamePrefix = "virtual-machine-config"
-		} else {
-			fileNamePrefix = "virtual-machine"
-		}
-	} else if vol.volType == VolumeTypeCustom {
-		fileNamePrefix = "volume"
-	}
-
 	err = addVolume(vol, targetVolume, lastVolPath, fileNamePrefix)
 	if err != nil {
 		return err
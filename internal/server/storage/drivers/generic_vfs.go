@@ -648,7 +648,17 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 		prefix = "backup/volume"
 	}
 
-	err := backupVolume(vol, prefix)
+	srcVol := vol
+	if vol.backupSnapshot != "" {
+		var err error
+
+		srcVol, err = vol.NewSnapshot(vol.backupSnapshot)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := backupVolume(srcVol, prefix)
 	if err != nil {
 		return err
 	}
@@ -105,6 +105,7 @@ type Volume struct {
 	mountCustomPath      string // Mount the filesystem volume at a custom location.
 	mountFilesystemProbe bool   // Probe filesystem type when mounting volume (when needed).
 	hasSource            bool   // Whether the volume is created from a source volume.
+	backupSnapshot       string // Snapshot the main volume is read from when backing it up.
 }
 
 // NewVolume instantiates a new Volume struct.
@@ -373,6 +374,10 @@ func (v Volume) SnapshotsMatch(snapNames []string, op *operations.Operation) err
 	}
 
 	for _, snapshot := range snapshots {
+		if snapshot == v.backupSnapshot {
+			continue
+		}
+
 		if !slices.Contains(snapNames, snapshot) {
 			return fmt.Errorf("Snapshot %q in storage but not expected", snapshot)
 		}
@@ -428,8 +433,9 @@ func (v Volume) NewVMBlockFilesystemVolume() Volume {
 
 	vol := NewVolume(v.driver, v.pool, v.volType, ContentTypeFS, v.name, newConf, v.poolConfig)
 
-	// Propagate filesystem probe mode of parent volume.
+	// Propagate filesystem probe mode and backup snapshot of parent volume.
 	vol.SetMountFilesystemProbe(v.mountFilesystemProbe)
+	vol.SetBackupSnapshot(v.backupSnapshot)
 
 	return vol
 }
@@ -568,6 +574,12 @@ func (v *Volume) SetMountFilesystemProbe(probe bool) {
 	v.mountFilesystemProbe = probe
 }
 
+// SetBackupSnapshot sets the snapshot of the volume its content is read from when backing it up, rather than
+// from the volume itself. The snapshot isn't included in the backup as a snapshot.
+func (v *Volume) SetBackupSnapshot(snapshotName string) {
+	v.backupSnapshot = snapshotName
+}
+
 // SetHasSource indicates whether the Volume is created from a source.
 func (v *Volume) SetHasSource(hasSource bool) {
 	v.hasSource = hasSource
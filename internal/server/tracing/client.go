@@ -0,0 +1,319 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/version"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+const (
+	contentType  = "application/json"
+	maxErrMsgLen = 1024
+
+	// batchSize is the maximum number of spans sent in a single request.
+	batchSize = 512
+
+	// batchWait is the maximum time spans are held before being sent.
+	batchWait = 5 * time.Second
+
+	// queueSize is the number of ended spans that can be waiting to be sent, further spans are dropped.
+	queueSize = 4096
+)
+
+// Client exports spans to an OpenTelemetry collector using OTLP over HTTP, with the JSON encoding.
+type Client struct {
+	url       *url.URL
+	headers   map[string]string
+	client    *http.Client
+	resource  otlpResource
+	threshold uint64
+
+	ctx   context.Context
+	quit  chan struct{}
+	once  sync.Once
+	spans chan otlpSpan
+	wg    sync.WaitGroup
+}
+
+// NewClient returns a Client sending the spans to the OTLP/HTTP endpoint at the given URL.
+// The sample ratio is the fraction of the traces started on this server that are recorded.
+func NewClient(ctx context.Context, u *url.URL, caCert string, headers map[string]string, location string, sampleRatio float64) (*Client, error) {
+	client := Client{
+		url:       u,
+		headers:   headers,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		threshold: sampleThreshold(sampleRatio),
+		ctx:       ctx,
+		quit:      make(chan struct{}),
+		spans:     make(chan otlpSpan, queueSize),
+	}
+
+	if caCert != "" {
+		tlsConfig, err := localtls.GetTLSConfigMem("", "", caCert, "", false)
+		if err != nil {
+			return nil, err
+		}
+
+		client.client.Transport = &http.Transport{
+			TLSClientConfig: tlsConfig,
+		}
+	}
+
+	client.resource.Attributes = otlpAttributes(map[string]any{
+		"service.name":        "incus",
+		"service.version":     version.Version,
+		"service.instance.id": location,
+		"host.name":           location,
+	})
+
+	client.wg.Add(1)
+	go client.run()
+
+	return &client, nil
+}
+
+// Stop stops the client after sending the pending spans.
+func (c *Client) Stop() {
+	c.once.Do(func() { close(c.quit) })
+	c.wg.Wait()
+}
+
+func (c *Client) sample(traceID [16]byte) bool {
+	return sampled(traceID, c.threshold)
+}
+
+// export queues an ended span to be sent, dropping it if the queue is full.
+func (c *Client) export(s *Span, end time.Time) {
+	s.lock.Lock()
+	span := otlpSpan{
+		TraceID:           hex.EncodeToString(s.traceID[:]),
+		SpanID:            hex.EncodeToString(s.spanID[:]),
+		Name:              s.name,
+		Kind:              int(s.kind),
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+		Attributes:        otlpAttributes(s.attributes),
+	}
+
+	if s.parentID != [8]byte{} {
+		span.ParentSpanID = hex.EncodeToString(s.parentID[:])
+	}
+
+	if s.err != "" {
+		span.Status = &otlpStatus{Code: 2, Message: s.err}
+	}
+
+	s.lock.Unlock()
+
+	select {
+	case c.spans <- span:
+	default:
+	}
+}
+
+func (c *Client) run() {
+	defer c.wg.Done()
+
+	batch := make([]otlpSpan, 0, batchSize)
+	ticker := time.NewTicker(batchWait)
+	defer ticker.Stop()
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		c.sendBatch(batch)
+		batch = make([]otlpSpan, 0, batchSize)
+	}
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+
+		case <-c.quit:
+			// Send the pending spans.
+			for len(c.spans) > 0 {
+				batch = append(batch, <-c.spans)
+				if len(batch) >= batchSize {
+					flush()
+				}
+			}
+
+			flush()
+			return
+
+		case span := <-c.spans:
+			batch = append(batch, span)
+			if len(batch) >= batchSize {
+				flush()
+			}
+
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (c *Client) sendBatch(batch []otlpSpan) {
+	request := otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: c.resource,
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "incus", Version: version.Version},
+			Spans: batch,
+		}},
+	}}}
+
+	buf, err := json.Marshal(request)
+	if err != nil {
+		return
+	}
+
+	for i := 0; i < 3; i++ {
+		// Try to send the spans.
+		status, err := c.send(buf)
+		if err == nil {
+			return
+		}
+
+		// Only retry 429s, 500s and connection-level errors.
+		if status > 0 && status != http.StatusTooManyRequests && status/100 != 5 {
+			return
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(time.Duration(i+1) * time.Second):
+		}
+	}
+}
+
+func (c *Client) send(buf []byte) (int, error) {
+	req, err := http.NewRequestWithContext(c.ctx, "POST", c.url.JoinPath("/v1/traces").String(), bytes.NewReader(buf))
+	if err != nil {
+		return -1, err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", version.UserAgent)
+
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return -1, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		scanner := io.LimitReader(resp.Body, maxErrMsgLen)
+		line, _ := io.ReadAll(scanner)
+
+		return resp.StatusCode, fmt.Errorf("Server returned HTTP status %s (%d): %s", resp.Status, resp.StatusCode, line)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// OTLP JSON encoding of the trace export requests (opentelemetry/proto/collector/trace/v1).
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            *otlpStatus     `json:"status,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// otlpAttributes converts attributes to their OTLP encoding, sorted by key.
+func otlpAttributes(attributes map[string]any) []otlpAttribute {
+	result := make([]otlpAttribute, 0, len(attributes))
+	for key, value := range attributes {
+		attribute := otlpAttribute{Key: key}
+
+		switch v := value.(type) {
+		case string:
+			attribute.Value.StringValue = &v
+		case bool:
+			attribute.Value.BoolValue = &v
+		case int:
+			s := strconv.Itoa(v)
+			attribute.Value.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(v, 10)
+			attribute.Value.IntValue = &s
+		case float64:
+			attribute.Value.DoubleValue = &v
+		default:
+			s := fmt.Sprint(v)
+			attribute.Value.StringValue = &s
+		}
+
+		result = append(result, attribute)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+
+	return result
+}
@@ -0,0 +1,217 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SpanKind indicates the role of a span in a trace.
+type SpanKind int
+
+// The span kinds, as defined by OpenTelemetry.
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+// HeaderTraceParent is the W3C Trace Context header used to propagate traces across cluster members.
+const HeaderTraceParent = "Traceparent"
+
+type ctxKey struct{}
+
+// exporter is the client spans are exported with, nil when tracing is disabled.
+var exporter atomic.Pointer[Client]
+
+// SetClient sets the client used to export the spans, disabling tracing if nil.
+func SetClient(client *Client) {
+	exporter.Store(client)
+}
+
+// Span represents a traced operation.
+type Span struct {
+	client *Client
+
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	sampled  bool
+
+	name  string
+	kind  SpanKind
+	start time.Time
+
+	lock       sync.Mutex
+	attributes map[string]any
+	err        string
+	ended      bool
+}
+
+// Start starts a new span, as a child of the span of the context if any.
+// It returns a nil span, on which all methods are no-ops, if tracing is disabled.
+func Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	client := exporter.Load()
+	if client == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		client:     client,
+		name:       name,
+		kind:       kind,
+		start:      time.Now(),
+		attributes: map[string]any{},
+	}
+
+	_, _ = rand.Read(span.spanID[:])
+
+	parent := SpanFromContext(ctx)
+	if parent != nil {
+		span.traceID = parent.traceID
+		span.parentID = parent.spanID
+		span.sampled = parent.sampled
+	} else {
+		_, _ = rand.Read(span.traceID[:])
+		span.sampled = client.sample(span.traceID)
+	}
+
+	return context.WithValue(ctx, ctxKey{}, span), span
+}
+
+// SpanFromContext returns the span of the context, or nil if there isn't any.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(ctxKey{}).(*Span)
+	return span
+}
+
+// Detach returns a context carrying the span of the given context, without its deadline and cancellation.
+// This is used for work outliving the request that started it.
+func Detach(ctx context.Context) context.Context {
+	span := SpanFromContext(ctx)
+	if span == nil {
+		return context.Background()
+	}
+
+	return context.WithValue(context.Background(), ctxKey{}, span)
+}
+
+// Inject adds the trace context of the span of the context to the headers of an outgoing request.
+func Inject(ctx context.Context, header http.Header) {
+	span := SpanFromContext(ctx)
+	if span == nil {
+		return
+	}
+
+	flags := "00"
+	if span.sampled {
+		flags = "01"
+	}
+
+	header.Set(HeaderTraceParent, fmt.Sprintf("00-%s-%s-%s", hex.EncodeToString(span.traceID[:]), hex.EncodeToString(span.spanID[:]), flags))
+}
+
+// Extract returns a context carrying the remote parent span of an incoming request, if valid.
+// Spans started from that context belong to the trace of the remote span.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	fields := strings.Split(header.Get(HeaderTraceParent), "-")
+	if len(fields) != 4 || fields[0] != "00" || len(fields[1]) != 32 || len(fields[2]) != 16 || len(fields[3]) != 2 {
+		return ctx
+	}
+
+	parent := &Span{ended: true}
+
+	_, err := hex.Decode(parent.traceID[:], []byte(fields[1]))
+	if err != nil || parent.traceID == [16]byte{} {
+		return ctx
+	}
+
+	_, err = hex.Decode(parent.spanID[:], []byte(fields[2]))
+	if err != nil || parent.spanID == [8]byte{} {
+		return ctx
+	}
+
+	flags, err := hex.DecodeString(fields[3])
+	if err != nil {
+		return ctx
+	}
+
+	parent.sampled = flags[0]&0x01 != 0
+
+	return context.WithValue(ctx, ctxKey{}, parent)
+}
+
+// TraceID returns the ID of the trace of the span.
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+
+	return hex.EncodeToString(s.traceID[:])
+}
+
+// SetAttribute sets an attribute of the span.
+func (s *Span) SetAttribute(key string, value any) {
+	if s == nil || !s.sampled {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.attributes[key] = value
+}
+
+// End ends the span, marking it as failed if an error is given, and queues it for export.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+
+	s.ended = true
+	if err != nil {
+		s.err = err.Error()
+	}
+
+	s.lock.Unlock()
+
+	if s.sampled {
+		s.client.export(s, time.Now())
+	}
+}
+
+// sampleThreshold converts a sampling ratio into a threshold compared with the trace IDs.
+func sampleThreshold(ratio float64) uint64 {
+	if ratio >= 1 {
+		return ^uint64(0)
+	}
+
+	if ratio <= 0 {
+		return 0
+	}
+
+	return uint64(ratio * float64(^uint64(0)))
+}
+
+// sampled returns whether a trace ID falls below the sampling threshold, so that the decision is
+// consistent across the members handling the same trace.
+func sampled(traceID [16]byte, threshold uint64) bool {
+	if threshold == ^uint64(0) {
+		return true
+	}
+
+	return binary.BigEndian.Uint64(traceID[8:]) < threshold
+}
@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartDisabled(t *testing.T) {
+	SetClient(nil)
+
+	ctx, span := Start(context.Background(), "test", KindInternal)
+	assert.Nil(t, span)
+	assert.Nil(t, SpanFromContext(ctx))
+
+	// Methods on nil spans are no-ops.
+	span.SetAttribute("foo", "bar")
+	span.End(nil)
+	assert.Equal(t, "", span.TraceID())
+}
+
+func TestPropagation(t *testing.T) {
+	client := &Client{threshold: sampleThreshold(1)}
+	SetClient(client)
+	defer SetClient(nil)
+
+	ctx, parent := Start(context.Background(), "parent", KindServer)
+	require.NotNil(t, parent)
+
+	header := http.Header{}
+	Inject(ctx, header)
+	assert.Equal(t, "00-"+parent.TraceID()+"-"+hex.EncodeToString(parent.spanID[:])+"-01", header.Get(HeaderTraceParent))
+
+	// The remote span continues the trace.
+	_, remote := Start(Extract(context.Background(), header), "remote", KindServer)
+	require.NotNil(t, remote)
+	assert.Equal(t, parent.TraceID(), remote.TraceID())
+	assert.Equal(t, parent.spanID, remote.parentID)
+	assert.True(t, remote.sampled)
+
+	// Detached contexts keep the span.
+	_, child := Start(Detach(ctx), "child", KindInternal)
+	assert.Equal(t, parent.spanID, child.parentID)
+
+	// Invalid headers are ignored.
+	header.Set(HeaderTraceParent, "00-00000000000000000000000000000000-0000000000000000-01")
+	assert.Nil(t, SpanFromContext(Extract(context.Background(), header)))
+}
+
+func TestSampled(t *testing.T) {
+	low := [16]byte{}
+	high := [16]byte{}
+	high[8] = 0xff
+
+	assert.True(t, sampled(high, sampleThreshold(1)))
+	assert.False(t, sampled(low, sampleThreshold(0)))
+	assert.True(t, sampled(low, sampleThreshold(0.5)))
+	assert.False(t, sampled(high, sampleThreshold(0.5)))
+}
+
+func TestClient(t *testing.T) {
+	requests := make(chan otlpRequest, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "token", r.Header.Get("Authorization"))
+
+		request := otlpRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		requests <- request
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client, err := NewClient(context.Background(), u, "", map[string]string{"Authorization": "token"}, "node1", 1)
+	require.NoError(t, err)
+
+	SetClient(client)
+	defer SetClient(nil)
+
+	ctx, parent := Start(context.Background(), "GET /1.0", KindServer)
+	parent.SetAttribute("http.response.status_code", 200)
+
+	_, child := Start(ctx, "run", KindInternal)
+	child.End(errors.New("Failed"))
+	parent.End(nil)
+
+	client.Stop()
+
+	request := <-requests
+	require.Len(t, request.ResourceSpans, 1)
+	require.Len(t, request.ResourceSpans[0].ScopeSpans, 1)
+
+	spans := request.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "run", spans[0].Name)
+	assert.Equal(t, spans[1].SpanID, spans[0].ParentSpanID)
+	assert.Equal(t, spans[1].TraceID, spans[0].TraceID)
+	require.NotNil(t, spans[0].Status)
+	assert.Equal(t, "Failed", spans[0].Status.Message)
+
+	assert.Equal(t, "GET /1.0", spans[1].Name)
+	assert.Equal(t, int(KindServer), spans[1].Kind)
+	assert.Equal(t, "", spans[1].ParentSpanID)
+	require.Len(t, spans[1].Attributes, 1)
+	assert.Equal(t, "200", *spans[1].Attributes[0].Value.IntValue)
+}
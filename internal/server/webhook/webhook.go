@@ -0,0 +1,237 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// EventTypeWarning is the type of the events sent to webhooks when a new warning is raised.
+const EventTypeWarning = "warning"
+
+// EventTypes lists the types of events that can be sent to webhooks.
+var EventTypes = []string{api.EventTypeLifecycle, EventTypeWarning}
+
+const (
+	// cacheDuration is how long the list of webhooks is cached for, so that changes made on other cluster
+	// members are picked up.
+	cacheDuration = 10 * time.Second
+
+	// timeout is the maximum duration of a delivery attempt.
+	timeout = 10 * time.Second
+
+	// maxErrMsgLen is the maximum length of the response body included in errors.
+	maxErrMsgLen = 1024
+)
+
+// Delivery attempts are retried with an exponential backoff, starting at retryDelay.
+var (
+	maxAttempts = 5
+	retryDelay  = 2 * time.Second
+)
+
+// LoadFunc returns the configured webhooks.
+type LoadFunc func() ([]api.Webhook, error)
+
+// FailureFunc is called with the events that couldn't be delivered to a webhook.
+type FailureFunc func(name string, letter api.WebhookDeadLetter)
+
+// Dispatcher sends events to the webhooks subscribed to them.
+type Dispatcher struct {
+	ctx     context.Context
+	client  *http.Client
+	load    LoadFunc
+	failure FailureFunc
+
+	lock     sync.Mutex
+	webhooks []api.Webhook
+	loadedAt time.Time
+}
+
+// NewDispatcher returns a Dispatcher.
+func NewDispatcher(ctx context.Context, load LoadFunc, failure FailureFunc) *Dispatcher {
+	return &Dispatcher{
+		ctx:     ctx,
+		client:  &http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}},
+		load:    load,
+		failure: failure,
+	}
+}
+
+// Reload discards the cached list of webhooks, so that it's loaded again for the next event.
+func (d *Dispatcher) Reload() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.loadedAt = time.Time{}
+}
+
+// getWebhooks returns the list of webhooks, loading it if the cached one is too old.
+func (d *Dispatcher) getWebhooks() []api.Webhook {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if time.Since(d.loadedAt) < cacheDuration {
+		return d.webhooks
+	}
+
+	webhooks, err := d.load()
+	if err != nil {
+		logger.Warn("Failed loading webhooks", logger.Ctx{"err": err})
+		return d.webhooks
+	}
+
+	d.webhooks = webhooks
+	d.loadedAt = time.Now()
+
+	return d.webhooks
+}
+
+// Subscribed returns whether any webhook is subscribed to the given type of events.
+func (d *Dispatcher) Subscribed(eventType string) bool {
+	for _, webhook := range d.getWebhooks() {
+		if len(webhook.Types) == 0 || slices.Contains(webhook.Types, eventType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HandleEvent sends the event to the webhooks subscribed to it.
+func (d *Dispatcher) HandleEvent(event api.Event) {
+	for _, webhook := range d.getWebhooks() {
+		if !Matches(webhook, event) {
+			continue
+		}
+
+		go d.deliver(webhook, event)
+	}
+}
+
+// Matches returns whether the webhook is subscribed to the event.
+// Events that aren't tied to a project are only sent to the webhooks not filtering on projects.
+func Matches(webhook api.Webhook, event api.Event) bool {
+	if !slices.Contains(EventTypes, event.Type) {
+		return false
+	}
+
+	if len(webhook.Types) > 0 && !slices.Contains(webhook.Types, event.Type) {
+		return false
+	}
+
+	if len(webhook.Projects) > 0 && !slices.Contains(webhook.Projects, event.Project) {
+		return false
+	}
+
+	return true
+}
+
+// Sign returns the signature of the payload, as sent in the X-Incus-Signature-256 header.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver sends the event to the webhook, retrying on failure, and reports it if it couldn't be delivered.
+func (d *Dispatcher) deliver(webhook api.Webhook, event api.Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	deliveryID := uuid.New().String()
+	delay := retryDelay
+
+	attempts := 0
+	for {
+		attempts++
+
+		var retry bool
+		retry, err = d.send(webhook, event.Type, deliveryID, payload)
+		if err == nil {
+			return
+		}
+
+		if !retry || attempts >= maxAttempts {
+			break
+		}
+
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+
+	logger.Warn("Failed delivering event to webhook", logger.Ctx{"webhook": webhook.Name, "type": event.Type, "attempts": attempts, "err": err})
+
+	if d.failure != nil {
+		d.failure(webhook.Name, api.WebhookDeadLetter{
+			Event:    event,
+			Attempts: attempts,
+			Error:    err.Error(),
+			FailedAt: time.Now().UTC(),
+		})
+	}
+}
+
+// send makes a single delivery attempt and returns whether it should be retried on failure.
+// Only connection-level errors, 429 and 5xx responses are retried.
+func (d *Dispatcher) send(webhook api.Webhook, eventType string, deliveryID string, payload []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent)
+	req.Header.Set("X-Incus-Event", eventType)
+	req.Header.Set("X-Incus-Delivery", deliveryID)
+
+	if webhook.Secret != "" {
+		req.Header.Set("X-Incus-Signature-256", Sign(webhook.Secret, payload))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrMsgLen))
+
+		err = fmt.Errorf("Unexpected status code %d", resp.StatusCode)
+		if len(bytes.TrimSpace(body)) > 0 {
+			err = fmt.Errorf("Unexpected status code %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+		}
+
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode/100 == 5, err
+	}
+
+	return false, nil
+}
@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestMatches(t *testing.T) {
+	lifecycle := api.Event{Type: api.EventTypeLifecycle, Project: "foo"}
+	global := api.Event{Type: EventTypeWarning}
+	logging := api.Event{Type: api.EventTypeLogging, Project: "foo"}
+
+	all := api.Webhook{}
+	assert.True(t, Matches(all, lifecycle))
+	assert.True(t, Matches(all, global))
+	assert.False(t, Matches(all, logging))
+
+	warnings := api.Webhook{WebhookPut: api.WebhookPut{Types: []string{EventTypeWarning}}}
+	assert.False(t, Matches(warnings, lifecycle))
+	assert.True(t, Matches(warnings, global))
+
+	foo := api.Webhook{WebhookPut: api.WebhookPut{Projects: []string{"foo"}}}
+	assert.True(t, Matches(foo, lifecycle))
+	assert.False(t, Matches(foo, global))
+
+	bar := api.Webhook{WebhookPut: api.WebhookPut{Projects: []string{"bar"}}}
+	assert.False(t, Matches(bar, lifecycle))
+}
+
+func TestDispatcher(t *testing.T) {
+	retryDelay = 10 * time.Millisecond
+
+	received := make(chan *http.Request, 1)
+	var failures atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt.
+		if failures.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, Sign("secret", body), r.Header.Get("X-Incus-Signature-256"))
+
+		event := api.Event{}
+		assert.NoError(t, json.Unmarshal(body, &event))
+		assert.Equal(t, "foo", event.Project)
+
+		received <- r
+	}))
+	defer server.Close()
+
+	load := func() ([]api.Webhook, error) {
+		return []api.Webhook{{Name: "test", WebhookPut: api.WebhookPut{URL: server.URL, Secret: "secret"}}}, nil
+	}
+
+	dispatcher := NewDispatcher(context.Background(), load, nil)
+	dispatcher.HandleEvent(api.Event{Type: api.EventTypeLifecycle, Project: "foo"})
+
+	select {
+	case r := <-received:
+		assert.Equal(t, api.EventTypeLifecycle, r.Header.Get("X-Incus-Event"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("Event wasn't delivered")
+	}
+}
+
+func TestDispatcherDeadLetter(t *testing.T) {
+	retryDelay = 10 * time.Millisecond
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		http.Error(w, "Not here", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	load := func() ([]api.Webhook, error) {
+		return []api.Webhook{{Name: "test", WebhookPut: api.WebhookPut{URL: server.URL}}}, nil
+	}
+
+	letters := make(chan api.WebhookDeadLetter, 1)
+	failure := func(name string, letter api.WebhookDeadLetter) {
+		assert.Equal(t, "test", name)
+		letters <- letter
+	}
+
+	dispatcher := NewDispatcher(context.Background(), load, failure)
+	dispatcher.HandleEvent(api.Event{Type: EventTypeWarning})
+
+	select {
+	case letter := <-letters:
+		// Client errors aren't retried.
+		require.Equal(t, 1, letter.Attempts)
+		assert.Equal(t, int32(1), attempts.Load())
+		assert.Equal(t, "Unexpected status code 404: Not here", letter.Error)
+		assert.Equal(t, EventTypeWarning, letter.Event.Type)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Event wasn't reported as undelivered")
+	}
+}
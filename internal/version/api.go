@@ -433,6 +433,29 @@ var APIExtensions = []string{
 	"disk_volume_subpath",
 	"projects_limits_disk_pool",
 	"network_ovn_isolated",
+	"instance_snapshots_quiesce",
+	"instance_snapshots_hooks",
+	"instance_ssh_keys",
+	"instance_inventory",
+	"instance_clock_sync",
+	"instance_shutdown_agent",
+	"devincus_guest_state",
+	"instance_wait",
+	"instance_file_resume",
+	"instance_port_forward",
+	"instance_exec_detach",
+	"instance_session_recording",
+	"projects_restricted_instance_access",
+	"projects_limits_network_disk_iops",
+	"projects_usage_history",
+	"projects_templates",
+	"instances_config_origin",
+	"events_history",
+	"webhooks",
+	"tracing",
+	"logging_targets",
+	"instance_log_forwarding",
+	"metrics_daemon",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
@@ -17,4 +17,8 @@ type API10Put struct {
 	// Whether or not to enable /dev/incus
 	// Example: true
 	DevIncus bool `json:"dev_incus" yaml:"dev_incus"`
+
+	// Whether or not to load the KVM PTP clock driver
+	// Example: true
+	PTPKVM bool `json:"ptp_kvm" yaml:"ptp_kvm"`
 }
@@ -0,0 +1,12 @@
+package api
+
+import (
+	"time"
+)
+
+// ClockPut contains the fields used to step the guest clock.
+type ClockPut struct {
+	// Current time on the host
+	// Example: 2024-09-20T13:31:20.123456789Z
+	Time time.Time `json:"time" yaml:"time"`
+}
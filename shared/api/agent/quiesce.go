@@ -0,0 +1,15 @@
+package api
+
+// QuiescePost contains the fields used to freeze the guest filesystems.
+type QuiescePost struct {
+	// Number of seconds after which the filesystems get automatically thawed
+	// Example: 300
+	Timeout int `json:"timeout" yaml:"timeout"`
+}
+
+// Quiesce represents the freeze state of the guest filesystems.
+type Quiesce struct {
+	// List of frozen mount points
+	// Example: ["/", "/var/lib/mysql"]
+	Filesystems []string `json:"filesystems" yaml:"filesystems"`
+}
@@ -0,0 +1,8 @@
+package api
+
+// ShutdownPost contains the fields used to request a guest shutdown.
+type ShutdownPost struct {
+	// Command to run to shut down the guest (defaults to the init system's poweroff)
+	// Example: ["/bin/sh", "-c", "appliance-ctl halt"]
+	Command []string `json:"command" yaml:"command"`
+}
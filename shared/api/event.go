@@ -8,10 +8,11 @@ import (
 
 // Event types.
 const (
-	EventTypeLifecycle  = "lifecycle"
-	EventTypeLogging    = "logging"
-	EventTypeOperation  = "operation"
-	EventTypeNetworkACL = "network-acl"
+	EventTypeLifecycle   = "lifecycle"
+	EventTypeLogging     = "logging"
+	EventTypeOperation   = "operation"
+	EventTypeNetworkACL  = "network-acl"
+	EventTypeInstanceLog = "instance-log"
 )
 
 // Event represents an event entry (over websocket)
@@ -41,11 +42,17 @@ type Event struct {
 	//
 	// API extension: event_project
 	Project string `yaml:"project,omitempty" json:"project,omitempty"`
+
+	// ID of the event in the event history (only set for recorded events)
+	// Example: 1234
+	//
+	// API extension: events_history
+	ID int64 `yaml:"id,omitempty" json:"id,omitempty"`
 }
 
 // ToLogging creates log record for the event.
 func (event *Event) ToLogging() (EventLogRecord, error) {
-	if event.Type == EventTypeLogging || event.Type == EventTypeNetworkACL {
+	if event.Type == EventTypeLogging || event.Type == EventTypeNetworkACL || event.Type == EventTypeInstanceLog {
 		e := &EventLogging{}
 		err := json.Unmarshal(event.Metadata, &e)
 		if err != nil {
@@ -35,6 +35,7 @@ const (
 	EventLifecycleInstanceBackupDeleted             = "instance-backup-deleted"
 	EventLifecycleInstanceBackupRenamed             = "instance-backup-renamed"
 	EventLifecycleInstanceBackupRetrieved           = "instance-backup-retrieved"
+	EventLifecycleInstanceAgentStarted              = "instance-agent-started"
 	EventLifecycleInstanceConsole                   = "instance-console"
 	EventLifecycleInstanceConsoleReset              = "instance-console-reset"
 	EventLifecycleInstanceConsoleRetrieved          = "instance-console-retrieved"
@@ -44,6 +45,7 @@ const (
 	EventLifecycleInstanceFileDeleted               = "instance-file-deleted"
 	EventLifecycleInstanceFilePushed                = "instance-file-pushed"
 	EventLifecycleInstanceFileRetrieved             = "instance-file-retrieved"
+	EventLifecycleInstanceHealthChanged             = "instance-health-changed"
 	EventLifecycleInstanceLogDeleted                = "instance-log-deleted"
 	EventLifecycleInstanceLogRetrieved              = "instance-log-retrieved"
 	EventLifecycleInstanceMetadataRetrieved         = "instance-metadata-retrieved"
@@ -59,6 +61,7 @@ const (
 	EventLifecycleInstanceRestored                  = "instance-restored"
 	EventLifecycleInstanceResumed                   = "instance-resumed"
 	EventLifecycleInstanceShutdown                  = "instance-shutdown"
+	EventLifecycleInstanceSSHKeysUpdated            = "instance-ssh-keys-updated"
 	EventLifecycleInstanceSnapshotCreated           = "instance-snapshot-created"
 	EventLifecycleInstanceSnapshotDeleted           = "instance-snapshot-deleted"
 	EventLifecycleInstanceSnapshotRenamed           = "instance-snapshot-renamed"
@@ -101,6 +104,9 @@ const (
 	EventLifecycleProjectCreated                    = "project-created"
 	EventLifecycleProjectDeleted                    = "project-deleted"
 	EventLifecycleProjectRenamed                    = "project-renamed"
+	EventLifecycleProjectTemplateCreated            = "project-template-created"
+	EventLifecycleProjectTemplateDeleted            = "project-template-deleted"
+	EventLifecycleProjectTemplateUpdated            = "project-template-updated"
 	EventLifecycleProjectUpdated                    = "project-updated"
 	EventLifecycleStorageBucketBackupCreated        = "storage-bucket-backup-created"
 	EventLifecycleStorageBucketBackupDeleted        = "storage-bucket-backup-deleted"
@@ -131,4 +137,8 @@ const (
 	EventLifecycleWarningAcknowledged               = "warning-acknowledged"
 	EventLifecycleWarningDeleted                    = "warning-deleted"
 	EventLifecycleWarningReset                      = "warning-reset"
+	EventLifecycleWebhookCreated                    = "webhook-created"
+	EventLifecycleWebhookDeadLettersDeleted         = "webhook-dead-letters-deleted"
+	EventLifecycleWebhookDeleted                    = "webhook-deleted"
+	EventLifecycleWebhookUpdated                    = "webhook-updated"
 )
@@ -5,6 +5,10 @@ type DevIncusPut struct {
 	// Instance state
 	// Example: Started
 	State string `json:"state" yaml:"state"`
+
+	// Instance health (healthy or unhealthy)
+	// Example: healthy
+	Health string `json:"health,omitempty" yaml:"health,omitempty"`
 }
 
 // DevIncusGet represents the server data which is returned as the root of the /dev/incus API.
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 )
 
 // ParseFileHeaders extracts the file ownership, type, mode and operation type from HTTP headers.
@@ -53,3 +54,43 @@ func ParseFileHeaders(headers http.Header) (int64, int64, int, string, string) {
 
 	return uid, gid, mode, fileType, writeMode
 }
+
+// ParseContentRange extracts the start and end offsets (inclusive) and the total size from a
+// "bytes <start>-<end>/<total>" Content-Range header as used for resumable file uploads.
+// The total is -1 when not known ("*").
+func ParseContentRange(value string) (int64, int64, int64, error) {
+	byteRange, found := strings.CutPrefix(value, "bytes ")
+	if !found {
+		return -1, -1, -1, fmt.Errorf("Unsupported range unit in %q", value)
+	}
+
+	byteRange, totalStr, found := strings.Cut(byteRange, "/")
+	if !found {
+		return -1, -1, -1, fmt.Errorf("Missing total size in %q", value)
+	}
+
+	startStr, endStr, found := strings.Cut(byteRange, "-")
+	if !found {
+		return -1, -1, -1, fmt.Errorf("Invalid range in %q", value)
+	}
+
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil || start < 0 {
+		return -1, -1, -1, fmt.Errorf("Invalid range start in %q", value)
+	}
+
+	end, err := strconv.ParseInt(endStr, 10, 64)
+	if err != nil || end < start {
+		return -1, -1, -1, fmt.Errorf("Invalid range end in %q", value)
+	}
+
+	total := int64(-1)
+	if totalStr != "*" {
+		total, err = strconv.ParseInt(totalStr, 10, 64)
+		if err != nil || total <= end {
+			return -1, -1, -1, fmt.Errorf("Invalid total size in %q", value)
+		}
+	}
+
+	return start, end, total, nil
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"fmt"
+)
+
+func ExampleParseContentRange() {
+	for _, value := range []string{"bytes 0-99/200", "bytes 100-199/*", "bytes 100-99/200", "bytes 0-199/100", "items 0-1/2"} {
+		start, end, total, err := ParseContentRange(value)
+		fmt.Println(start, end, total, err)
+	}
+
+	// Output: 0 99 200 <nil>
+	// 100 199 -1 <nil>
+	// -1 -1 -1 Invalid range end in "bytes 100-99/200"
+	// -1 -1 -1 Invalid total size in "bytes 0-199/100"
+	// -1 -1 -1 Unsupported range unit in "items 0-1/2"
+}
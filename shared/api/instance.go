@@ -217,6 +217,18 @@ type Instance struct {
 	// Example: {"root": {"type": "disk", "pool": "default", "path": "/"}}
 	ExpandedDevices map[string]map[string]string `json:"expanded_devices,omitempty" yaml:"expanded_devices,omitempty"`
 
+	// Origin of each expanded configuration key (only set when requested)
+	// Example: {"security.nesting": {"source": "profile", "profile": "default"}}
+	//
+	// API extension: instances_config_origin
+	ExpandedConfigOrigin map[string]InstanceConfigOrigin `json:"expanded_config_origin,omitempty" yaml:"expanded_config_origin,omitempty"`
+
+	// Origin of each expanded device (only set when requested)
+	// Example: {"root": {"source": "profile", "profile": "default"}}
+	//
+	// API extension: instances_config_origin
+	ExpandedDevicesOrigin map[string]InstanceConfigOrigin `json:"expanded_devices_origin,omitempty" yaml:"expanded_devices_origin,omitempty"`
+
 	// Instance name
 	// Example: foo
 	Name string `json:"name" yaml:"name"`
@@ -292,6 +304,48 @@ func (c *Instance) URL(apiVersion string, project string) *URL {
 	return NewURL().Path(apiVersion, "instances", c.Name).Project(project)
 }
 
+// InstanceConfigOrigin represents where an expanded configuration key or device of an instance comes from.
+//
+// swagger:model
+//
+// API extension: instances_config_origin.
+type InstanceConfigOrigin struct {
+	// Where the value comes from (instance or profile)
+	// Example: profile
+	Source string `json:"source" yaml:"source"`
+
+	// Name of the profile the value comes from
+	// Example: default
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
+
+	// Values overridden by this one, the most recently overridden first
+	Overridden []InstanceConfigOverride `json:"overridden,omitempty" yaml:"overridden,omitempty"`
+}
+
+// InstanceConfigOverride represents a configuration value or device of a profile that's overridden
+// in the expanded configuration of an instance.
+//
+// swagger:model
+//
+// API extension: instances_config_origin.
+type InstanceConfigOverride struct {
+	// Where the overridden value comes from (always profile)
+	// Example: profile
+	Source string `json:"source" yaml:"source"`
+
+	// Name of the profile the overridden value comes from
+	// Example: default
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
+
+	// Overridden configuration value
+	// Example: 2
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+
+	// Overridden device
+	// Example: {"type": "disk", "pool": "default", "path": "/"}
+	Device map[string]string `json:"device,omitempty" yaml:"device,omitempty"`
+}
+
 // InstanceSource represents the creation source for a new instance.
 //
 // swagger:model
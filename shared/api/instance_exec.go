@@ -53,4 +53,10 @@ type InstanceExecPost struct {
 	// Current working directory for the command
 	// Example: /home/foo/
 	Cwd string `json:"cwd" yaml:"cwd"`
+
+	// Whether the command keeps running when the websockets disconnect (requires wait-for-websocket)
+	// Example: true
+	//
+	// API extension: instance_exec_detach
+	Detachable bool `json:"detachable" yaml:"detachable"`
 }
@@ -0,0 +1,86 @@
+package api
+
+// InstanceInventory represents the software inventory of an instance.
+//
+// swagger:model
+//
+// API extension: instance_inventory.
+type InstanceInventory struct {
+	// Operating system information
+	OS InstanceInventoryOS `json:"os" yaml:"os"`
+
+	// List of installed packages
+	Packages []InstanceInventoryPackage `json:"packages" yaml:"packages"`
+
+	// List of system services
+	Services []InstanceInventoryService `json:"services" yaml:"services"`
+}
+
+// InstanceInventoryOS represents the operating system of an instance.
+//
+// swagger:model
+//
+// API extension: instance_inventory.
+type InstanceInventoryOS struct {
+	// Operating system identifier
+	// Example: ubuntu
+	ID string `json:"id" yaml:"id"`
+
+	// Operating system name
+	// Example: Ubuntu
+	Name string `json:"name" yaml:"name"`
+
+	// Operating system version
+	// Example: 24.04
+	Version string `json:"version" yaml:"version"`
+
+	// Full operating system version including its patch level
+	// Example: Ubuntu 24.04.1 LTS
+	PrettyName string `json:"pretty_name" yaml:"pretty_name"`
+
+	// Running kernel version
+	// Example: 6.8.0-45-generic
+	Kernel string `json:"kernel" yaml:"kernel"`
+}
+
+// InstanceInventoryPackage represents an installed package.
+//
+// swagger:model
+//
+// API extension: instance_inventory.
+type InstanceInventoryPackage struct {
+	// Package name
+	// Example: openssl
+	Name string `json:"name" yaml:"name"`
+
+	// Package version
+	// Example: 3.0.13-0ubuntu3.4
+	Version string `json:"version" yaml:"version"`
+
+	// Package architecture
+	// Example: amd64
+	Architecture string `json:"architecture" yaml:"architecture"`
+
+	// Package manager the package was installed with (apk, dpkg or rpm)
+	// Example: dpkg
+	Manager string `json:"manager" yaml:"manager"`
+}
+
+// InstanceInventoryService represents a system service.
+//
+// swagger:model
+//
+// API extension: instance_inventory.
+type InstanceInventoryService struct {
+	// Service name
+	// Example: ssh.service
+	Name string `json:"name" yaml:"name"`
+
+	// Whether the service is started at boot
+	// Example: true
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Current state of the service (empty if the instance isn't running)
+	// Example: running
+	State string `json:"state" yaml:"state"`
+}
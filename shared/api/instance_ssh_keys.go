@@ -0,0 +1,30 @@
+package api
+
+// InstanceSSHKeysPut represents the modifiable list of authorized SSH keys of a guest user.
+//
+// swagger:model
+//
+// API extension: instance_ssh_keys.
+type InstanceSSHKeysPut struct {
+	// List of authorized SSH public keys
+	// Example: ["ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHc4J3CZXvhb5V1P2R5n9+Zp6pVKH0hW7e4dY2kqRl0e user@host"]
+	Keys []string `json:"keys" yaml:"keys"`
+}
+
+// InstanceSSHKeys represents the authorized SSH keys of a guest user.
+//
+// swagger:model
+//
+// API extension: instance_ssh_keys.
+type InstanceSSHKeys struct {
+	InstanceSSHKeysPut `yaml:",inline"`
+
+	// Name of the guest user
+	// Example: ubuntu
+	User string `json:"user" yaml:"user"`
+}
+
+// Writable converts a full InstanceSSHKeys struct into a InstanceSSHKeysPut struct (filters read-only fields).
+func (keys *InstanceSSHKeys) Writable() InstanceSSHKeysPut {
+	return keys.InstanceSSHKeysPut
+}
@@ -65,6 +65,12 @@ type InstanceState struct {
 	//
 	// API extension: instance_state_started_at.
 	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+
+	// Health reported by the instance through the guest API (healthy, unhealthy or empty if never reported)
+	// Example: healthy
+	//
+	// API extension: devincus_guest_state
+	Health string `json:"health" yaml:"health"`
 }
 
 // InstanceStateDisk represents the disk information section of an instance's state.
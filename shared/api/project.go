@@ -14,6 +14,12 @@ type ProjectsPost struct {
 	// The name of the new project
 	// Example: foo
 	Name string `json:"name" yaml:"name"`
+
+	// Name of the project template to create the project from
+	// Example: tenant
+	//
+	// API extension: projects_templates
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
 }
 
 // ProjectPost represents the fields required to rename a project
@@ -0,0 +1,56 @@
+package api
+
+// ProjectTemplatesPost represents the fields of a new project template
+//
+// swagger:model
+//
+// API extension: projects_templates.
+type ProjectTemplatesPost struct {
+	ProjectTemplatePut `yaml:",inline"`
+
+	// The name of the new project template
+	// Example: tenant
+	Name string `json:"name" yaml:"name"`
+}
+
+// ProjectTemplatePut represents the modifiable fields of a project template
+//
+// swagger:model
+//
+// API extension: projects_templates.
+type ProjectTemplatePut struct {
+	// Description of the project template
+	// Example: Default setup for tenant projects
+	Description string `json:"description" yaml:"description"`
+
+	// Configuration of the projects created from the template (refer to doc/projects.md)
+	// Example: {"features.profiles": "true", "restricted": "true", "limits.instances": "10"}
+	Config map[string]string `json:"config" yaml:"config"`
+
+	// Profiles to create in the projects created from the template
+	Profiles []ProfilesPost `json:"profiles" yaml:"profiles"`
+
+	// Networks to create in the projects created from the template
+	Networks []NetworksPost `json:"networks" yaml:"networks"`
+}
+
+// ProjectTemplate represents a project template
+//
+// swagger:model
+//
+// API extension: projects_templates.
+type ProjectTemplate struct {
+	ProjectTemplatePut `yaml:",inline"`
+
+	// The project template name
+	// Read only: true
+	// Example: tenant
+	Name string `json:"name" yaml:"name"`
+}
+
+// Writable converts a full ProjectTemplate struct into a ProjectTemplatePut struct (filters read-only fields)
+//
+// API extension: projects_templates.
+func (template *ProjectTemplate) Writable() ProjectTemplatePut {
+	return template.ProjectTemplatePut
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"time"
+)
+
+// ProjectUsageRecord represents the resource usage of an instance of a project during an hour
+//
+// swagger:model
+//
+// API extension: projects_usage_history.
+type ProjectUsageRecord struct {
+	// Name of the instance
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Type of the instance
+	// Example: container
+	Type string `json:"type" yaml:"type"`
+
+	// Start of the hour the record covers
+	// Example: 2026-10-01T13:00:00Z
+	Date time.Time `json:"date" yaml:"date"`
+
+	// CPU time used during the hour (in seconds)
+	// Example: 1250.5
+	CPUSeconds float64 `json:"cpu_seconds" yaml:"cpu_seconds"`
+
+	// Average memory usage during the hour (in bytes)
+	// Example: 536870912
+	MemoryBytes int64 `json:"memory_bytes" yaml:"memory_bytes"`
+
+	// Maximum root disk usage during the hour (in bytes)
+	// Example: 2147483648
+	DiskBytes int64 `json:"disk_bytes" yaml:"disk_bytes"`
+
+	// Network traffic received during the hour (in bytes)
+	// Example: 10485760
+	NetworkReceivedBytes int64 `json:"network_received_bytes" yaml:"network_received_bytes"`
+
+	// Network traffic sent during the hour (in bytes)
+	// Example: 1048576
+	NetworkSentBytes int64 `json:"network_sent_bytes" yaml:"network_sent_bytes"`
+}
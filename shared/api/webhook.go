@@ -0,0 +1,88 @@
+package api
+
+import (
+	"time"
+)
+
+// WebhooksPost represents the fields of a new webhook
+//
+// swagger:model
+//
+// API extension: webhooks.
+type WebhooksPost struct {
+	WebhookPut `yaml:",inline"`
+
+	// The name of the new webhook
+	// Example: chatops
+	Name string `json:"name" yaml:"name"`
+}
+
+// WebhookPut represents the modifiable fields of a webhook
+//
+// swagger:model
+//
+// API extension: webhooks.
+type WebhookPut struct {
+	// Description of the webhook
+	// Example: Notify the operations channel
+	Description string `json:"description" yaml:"description"`
+
+	// URL the events are sent to
+	// Example: https://chat.example.net/hooks/incus
+	URL string `json:"url" yaml:"url"`
+
+	// Secret used to sign the deliveries (HMAC-SHA256)
+	// Example: s3cr3t
+	Secret string `json:"secret" yaml:"secret"`
+
+	// Types of events to send (lifecycle or warning), all of them if empty
+	// Example: ["lifecycle"]
+	Types []string `json:"types" yaml:"types"`
+
+	// Projects to send the events of, all of them if empty
+	// Example: ["default"]
+	Projects []string `json:"projects" yaml:"projects"`
+}
+
+// Webhook represents a webhook
+//
+// swagger:model
+//
+// API extension: webhooks.
+type Webhook struct {
+	WebhookPut `yaml:",inline"`
+
+	// The webhook name
+	// Read only: true
+	// Example: chatops
+	Name string `json:"name" yaml:"name"`
+}
+
+// Writable converts a full Webhook struct into a WebhookPut struct (filters read-only fields)
+//
+// API extension: webhooks.
+func (webhook *Webhook) Writable() WebhookPut {
+	return webhook.WebhookPut
+}
+
+// WebhookDeadLetter represents an event that couldn't be delivered to a webhook
+//
+// swagger:model
+//
+// API extension: webhooks.
+type WebhookDeadLetter struct {
+	// The undelivered event
+	Event Event `json:"event" yaml:"event"`
+
+	// Number of delivery attempts
+	// Example: 5
+	Attempts int `json:"attempts" yaml:"attempts"`
+
+	// Error of the last delivery attempt
+	// Example: Unexpected status code 503
+	Error string `json:"error" yaml:"error"`
+
+	// When the last delivery attempt failed
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	FailedAt time.Time `json:"failed_at" yaml:"failed_at"`
+}
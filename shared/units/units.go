@@ -192,3 +192,22 @@ func GetByteSizeStringIEC(input int64, precision uint) string {
 
 	return fmt.Sprintf("%.*fEB", precision, value)
 }
+
+// GetBitSizeString takes a number of bits and precision and returns a
+// human representation of the amount of data, as used for bandwidth limits.
+func GetBitSizeString(input int64, precision uint) string {
+	if input < 1000 {
+		return fmt.Sprintf("%dbit", input)
+	}
+
+	value := float64(input)
+
+	for _, unit := range []string{"kbit", "Mbit", "Gbit", "Tbit", "Pbit", "Ebit"} {
+		value = value / 1000
+		if value < 1000 {
+			return fmt.Sprintf("%.*f%s", precision, value, unit)
+		}
+	}
+
+	return fmt.Sprintf("%.*fEbit", precision, value)
+}